@@ -83,6 +83,10 @@ func (m *ThingID) GetValue() string {
 type AccessByIDReq struct {
 	ThingID   string `protobuf:"bytes,1,opt,name=thingID,proto3" json:"thingID,omitempty"`
 	ProjectID string `protobuf:"bytes,2,opt,name=projectID,proto3" json:"projectID,omitempty"`
+	// Subtopic is used only by CanPublish and CanSubscribe.
+	Subtopic string `protobuf:"bytes,3,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
+	// Token is the thing key, used only by CanPublish if the thing ID is empty.
+	Token string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
 }
 
 func (m *AccessByIDReq) Reset()                    { *m = AccessByIDReq{} }
@@ -104,6 +108,20 @@ func (m *AccessByIDReq) GetProjectID() string {
 	return ""
 }
 
+func (m *AccessByIDReq) GetSubtopic() string {
+	if m != nil {
+		return m.Subtopic
+	}
+	return ""
+}
+
+func (m *AccessByIDReq) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
 // If a token is not carrying any information itself, the type
 // field can be used to determine how to validate the token.
 // Also, different tokens can be encoded in different ways.
@@ -124,7 +142,8 @@ func (m *Token) GetValue() string {
 }
 
 type UserID struct {
-	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Value        string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Organization string `protobuf:"bytes,2,opt,name=organization,proto3" json:"organization,omitempty"`
 }
 
 func (m *UserID) Reset()                    { *m = UserID{} }
@@ -139,9 +158,17 @@ func (m *UserID) GetValue() string {
 	return ""
 }
 
+func (m *UserID) GetOrganization() string {
+	if m != nil {
+		return m.Organization
+	}
+	return ""
+}
+
 type IssueReq struct {
-	Issuer string `protobuf:"bytes,1,opt,name=issuer,proto3" json:"issuer,omitempty"`
-	Type   uint32 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	Issuer       string `protobuf:"bytes,1,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Type         uint32 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	Organization string `protobuf:"bytes,3,opt,name=organization,proto3" json:"organization,omitempty"`
 }
 
 func (m *IssueReq) Reset()                    { *m = IssueReq{} }
@@ -163,6 +190,13 @@ func (m *IssueReq) GetType() uint32 {
 	return 0
 }
 
+func (m *IssueReq) GetOrganization() string {
+	if m != nil {
+		return m.Organization
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*AccessByKeyReq)(nil), "alpha.AccessByKeyReq")
 	proto.RegisterType((*ThingID)(nil), "alpha.ThingID")
@@ -185,6 +219,8 @@ const _ = grpc.SupportPackageIsVersion4
 type ThingsServiceClient interface {
 	CanAccessByKey(ctx context.Context, in *AccessByKeyReq, opts ...grpc.CallOption) (*ThingID, error)
 	CanAccessByID(ctx context.Context, in *AccessByIDReq, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	CanPublish(ctx context.Context, in *AccessByIDReq, opts ...grpc.CallOption) (*ThingID, error)
+	CanSubscribe(ctx context.Context, in *AccessByIDReq, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
 	Identify(ctx context.Context, in *Token, opts ...grpc.CallOption) (*ThingID, error)
 }
 
@@ -214,6 +250,24 @@ func (c *thingsServiceClient) CanAccessByID(ctx context.Context, in *AccessByIDR
 	return out, nil
 }
 
+func (c *thingsServiceClient) CanPublish(ctx context.Context, in *AccessByIDReq, opts ...grpc.CallOption) (*ThingID, error) {
+	out := new(ThingID)
+	err := grpc.Invoke(ctx, "/alpha.ThingsService/CanPublish", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsServiceClient) CanSubscribe(ctx context.Context, in *AccessByIDReq, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/alpha.ThingsService/CanSubscribe", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *thingsServiceClient) Identify(ctx context.Context, in *Token, opts ...grpc.CallOption) (*ThingID, error) {
 	out := new(ThingID)
 	err := grpc.Invoke(ctx, "/alpha.ThingsService/Identify", in, out, c.cc, opts...)
@@ -228,6 +282,8 @@ func (c *thingsServiceClient) Identify(ctx context.Context, in *Token, opts ...g
 type ThingsServiceServer interface {
 	CanAccessByKey(context.Context, *AccessByKeyReq) (*ThingID, error)
 	CanAccessByID(context.Context, *AccessByIDReq) (*google_protobuf.Empty, error)
+	CanPublish(context.Context, *AccessByIDReq) (*ThingID, error)
+	CanSubscribe(context.Context, *AccessByIDReq) (*google_protobuf.Empty, error)
 	Identify(context.Context, *Token) (*ThingID, error)
 }
 
@@ -271,6 +327,42 @@ func _ThingsService_CanAccessByID_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ThingsService_CanPublish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessByIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsServiceServer).CanPublish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/alpha.ThingsService/CanPublish",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsServiceServer).CanPublish(ctx, req.(*AccessByIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsService_CanSubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessByIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsServiceServer).CanSubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/alpha.ThingsService/CanSubscribe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsServiceServer).CanSubscribe(ctx, req.(*AccessByIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ThingsService_Identify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Token)
 	if err := dec(in); err != nil {
@@ -301,6 +393,14 @@ var _ThingsService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CanAccessByID",
 			Handler:    _ThingsService_CanAccessByID_Handler,
 		},
+		{
+			MethodName: "CanPublish",
+			Handler:    _ThingsService_CanPublish_Handler,
+		},
+		{
+			MethodName: "CanSubscribe",
+			Handler:    _ThingsService_CanSubscribe_Handler,
+		},
 		{
 			MethodName: "Identify",
 			Handler:    _ThingsService_Identify_Handler,
@@ -488,6 +588,18 @@ func (m *AccessByIDReq) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintAuthn(dAtA, i, uint64(len(m.ProjectID)))
 		i += copy(dAtA[i:], m.ProjectID)
 	}
+	if len(m.Subtopic) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintAuthn(dAtA, i, uint64(len(m.Subtopic)))
+		i += copy(dAtA[i:], m.Subtopic)
+	}
+	if len(m.Token) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintAuthn(dAtA, i, uint64(len(m.Token)))
+		i += copy(dAtA[i:], m.Token)
+	}
 	return i, nil
 }
 
@@ -536,6 +648,12 @@ func (m *UserID) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintAuthn(dAtA, i, uint64(len(m.Value)))
 		i += copy(dAtA[i:], m.Value)
 	}
+	if len(m.Organization) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintAuthn(dAtA, i, uint64(len(m.Organization)))
+		i += copy(dAtA[i:], m.Organization)
+	}
 	return i, nil
 }
 
@@ -565,6 +683,12 @@ func (m *IssueReq) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintAuthn(dAtA, i, uint64(m.Type))
 	}
+	if len(m.Organization) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintAuthn(dAtA, i, uint64(len(m.Organization)))
+		i += copy(dAtA[i:], m.Organization)
+	}
 	return i, nil
 }
 
@@ -612,6 +736,14 @@ func (m *AccessByIDReq) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovAuthn(uint64(l))
 	}
+	l = len(m.Subtopic)
+	if l > 0 {
+		n += 1 + l + sovAuthn(uint64(l))
+	}
+	l = len(m.Token)
+	if l > 0 {
+		n += 1 + l + sovAuthn(uint64(l))
+	}
 	return n
 }
 
@@ -632,6 +764,10 @@ func (m *UserID) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovAuthn(uint64(l))
 	}
+	l = len(m.Organization)
+	if l > 0 {
+		n += 1 + l + sovAuthn(uint64(l))
+	}
 	return n
 }
 
@@ -645,6 +781,10 @@ func (m *IssueReq) Size() (n int) {
 	if m.Type != 0 {
 		n += 1 + sovAuthn(uint64(m.Type))
 	}
+	l = len(m.Organization)
+	if l > 0 {
+		n += 1 + l + sovAuthn(uint64(l))
+	}
 	return n
 }
 
@@ -935,6 +1075,64 @@ func (m *AccessByIDReq) Unmarshal(dAtA []byte) error {
 			}
 			m.ProjectID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subtopic", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuthn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subtopic = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Token", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuthn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Token = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuthn(dAtA[iNdEx:])
@@ -1093,6 +1291,35 @@ func (m *UserID) Unmarshal(dAtA []byte) error {
 			}
 			m.Value = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Organization", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuthn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Organization = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuthn(dAtA[iNdEx:])
@@ -1191,6 +1418,35 @@ func (m *IssueReq) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Organization", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuthn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Organization = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuthn(dAtA[iNdEx:])
@@ -1320,28 +1576,33 @@ var (
 func init() { proto.RegisterFile("authn.proto", fileDescriptorAuthn) }
 
 var fileDescriptorAuthn = []byte{
-	// 366 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x5f, 0x4e, 0xab, 0x40,
-	0x14, 0xc6, 0xe1, 0xe6, 0xd2, 0x3f, 0xe7, 0x96, 0x5e, 0x33, 0xd1, 0xa6, 0x41, 0x45, 0xc3, 0x53,
-	0xf5, 0x81, 0x26, 0x35, 0x31, 0xf1, 0xc9, 0xb4, 0x62, 0x0c, 0x31, 0xf1, 0xa1, 0xd6, 0x05, 0x50,
-	0x3c, 0x2d, 0x68, 0x05, 0x84, 0xa1, 0x09, 0x3b, 0x71, 0x27, 0x6e, 0xc1, 0x47, 0x97, 0x60, 0xea,
-	0x46, 0xcc, 0x0c, 0x33, 0xb1, 0x55, 0x1b, 0xdf, 0xe6, 0x1b, 0xce, 0x37, 0xdf, 0xc7, 0xef, 0xc0,
-	0x3f, 0x2f, 0xa7, 0x41, 0x64, 0x27, 0x69, 0x4c, 0x63, 0xa2, 0x79, 0xb3, 0x24, 0xf0, 0x8c, 0xed,
-	0x69, 0x1c, 0x4f, 0x67, 0xd8, 0xe5, 0x97, 0xe3, 0x7c, 0xd2, 0xc5, 0x87, 0x84, 0x16, 0xe5, 0x8c,
-	0xe5, 0x40, 0xb3, 0xef, 0xfb, 0x98, 0x65, 0x83, 0xe2, 0x12, 0x8b, 0x21, 0x3e, 0x92, 0x4d, 0xd0,
-	0x68, 0x7c, 0x8f, 0x51, 0x5b, 0xdd, 0x57, 0x3b, 0xf5, 0x61, 0x29, 0xc8, 0x0e, 0xd4, 0x93, 0x34,
-	0xbe, 0x43, 0x9f, 0xba, 0x4e, 0xfb, 0x0f, 0xff, 0xf2, 0x79, 0x61, 0xed, 0x41, 0x75, 0x14, 0x84,
-	0xd1, 0xd4, 0x75, 0x98, 0x7d, 0xee, 0xcd, 0x72, 0x94, 0x76, 0x2e, 0xac, 0x0b, 0xd0, 0x65, 0x8c,
-	0xeb, 0xb0, 0x94, 0x36, 0x54, 0x69, 0xe9, 0x10, 0x83, 0x52, 0xfe, 0x92, 0xb4, 0x0b, 0xda, 0x88,
-	0x17, 0xfa, 0x39, 0xc7, 0x84, 0xca, 0x4d, 0x86, 0xe9, 0xda, 0x1e, 0xc7, 0x50, 0x73, 0xb3, 0x2c,
-	0x47, 0x56, 0xa1, 0x05, 0x95, 0x90, 0x9d, 0x53, 0x31, 0x22, 0x14, 0x21, 0xf0, 0x97, 0x16, 0x09,
-	0xf2, 0x6c, 0x7d, 0xc8, 0xcf, 0xbd, 0x67, 0x15, 0x74, 0xfe, 0x87, 0xd9, 0x35, 0xa6, 0xf3, 0xd0,
-	0x47, 0x72, 0x02, 0xcd, 0x33, 0x2f, 0x5a, 0x62, 0x47, 0xb6, 0x6c, 0xce, 0xdb, 0x5e, 0xe5, 0x69,
-	0x34, 0xc5, 0xb5, 0x00, 0x64, 0x29, 0xe4, 0x14, 0xf4, 0x25, 0x2b, 0xeb, 0xfa, 0xc5, 0xc9, 0x11,
-	0x19, 0x2d, 0xbb, 0x5c, 0x9c, 0x2d, 0x17, 0x67, 0x9f, 0xb3, 0xc5, 0x59, 0x0a, 0x39, 0x84, 0x9a,
-	0x7b, 0x8b, 0x11, 0x0d, 0x27, 0x05, 0x69, 0xc8, 0xe7, 0x19, 0x95, 0xef, 0x61, 0x3d, 0x1f, 0x1a,
-	0xfd, 0x9c, 0x06, 0x57, 0xb2, 0x77, 0x07, 0x34, 0x4e, 0x80, 0xfc, 0x17, 0xa3, 0x92, 0x87, 0xb1,
-	0xf2, 0x92, 0xa5, 0x90, 0x83, 0xb5, 0x29, 0xba, 0x50, 0x25, 0x6a, 0x4b, 0x19, 0x6c, 0xbc, 0x2c,
-	0x4c, 0xf5, 0x75, 0x61, 0xaa, 0x6f, 0x0b, 0x53, 0x7d, 0x7a, 0x37, 0x95, 0x71, 0x85, 0x97, 0x3e,
-	0xfa, 0x08, 0x00, 0x00, 0xff, 0xff, 0x33, 0xbc, 0x71, 0x63, 0x91, 0x02, 0x00, 0x00,
+	// 438 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x41, 0x6e, 0xd4, 0x30,
+	0x14, 0x4d, 0xda, 0xce, 0x74, 0xfa, 0x99, 0x0c, 0xc8, 0x82, 0x2a, 0x0a, 0x10, 0x90, 0x57, 0x85,
+	0x45, 0x2a, 0x15, 0x36, 0x6c, 0x40, 0x9d, 0x09, 0x8b, 0x08, 0x09, 0xa1, 0x69, 0xd9, 0xb0, 0x73,
+	0x82, 0x9b, 0x18, 0x82, 0x1d, 0x62, 0xbb, 0x52, 0x38, 0x09, 0x97, 0xe0, 0x1e, 0x2c, 0x39, 0x02,
+	0x1a, 0x2e, 0x82, 0xe2, 0x38, 0x74, 0x86, 0x30, 0x48, 0xdd, 0xf9, 0x7d, 0xfb, 0xfd, 0xf7, 0xbe,
+	0xdf, 0x87, 0x1b, 0x44, 0xab, 0x82, 0x47, 0x55, 0x2d, 0x94, 0x40, 0x23, 0x52, 0x56, 0x05, 0x09,
+	0xee, 0xe6, 0x42, 0xe4, 0x25, 0x3d, 0x36, 0xc5, 0x54, 0x5f, 0x1c, 0xd3, 0x4f, 0x95, 0x6a, 0xba,
+	0x37, 0x38, 0x86, 0xd9, 0x69, 0x96, 0x51, 0x29, 0xe7, 0xcd, 0x2b, 0xda, 0x2c, 0xe9, 0x67, 0x74,
+	0x1b, 0x46, 0x4a, 0x7c, 0xa4, 0xdc, 0x77, 0x1f, 0xba, 0x47, 0x07, 0xcb, 0x0e, 0xa0, 0x7b, 0x70,
+	0x50, 0xd5, 0xe2, 0x03, 0xcd, 0x54, 0x12, 0xfb, 0x3b, 0xe6, 0xe6, 0xaa, 0x80, 0x1f, 0xc0, 0xfe,
+	0x79, 0xc1, 0x78, 0x9e, 0xc4, 0x2d, 0xfd, 0x92, 0x94, 0x9a, 0xf6, 0x74, 0x03, 0x70, 0x03, 0x5e,
+	0x2f, 0x93, 0xc4, 0xad, 0x8a, 0x0f, 0xfb, 0xaa, 0x63, 0xd8, 0x87, 0x3d, 0xfc, 0xbf, 0x12, 0x0a,
+	0x60, 0x22, 0x75, 0xaa, 0x44, 0xc5, 0x32, 0x7f, 0xd7, 0x5c, 0xfe, 0xc1, 0x57, 0xce, 0xf7, 0xd6,
+	0x9c, 0xe3, 0xfb, 0x30, 0x3a, 0x37, 0x23, 0xfc, 0xdb, 0xd9, 0x1c, 0xc6, 0x6f, 0x25, 0xad, 0xb7,
+	0x39, 0x47, 0x18, 0xa6, 0xa2, 0xce, 0x09, 0x67, 0x5f, 0x88, 0x62, 0x82, 0x5b, 0x47, 0x1b, 0x35,
+	0xfc, 0x0e, 0x26, 0x89, 0x94, 0x9a, 0xb6, 0x83, 0x1d, 0xc2, 0x98, 0xb5, 0xe7, 0xda, 0xb6, 0xb1,
+	0x08, 0x21, 0xd8, 0x53, 0x4d, 0x45, 0x0d, 0xdf, 0x5b, 0x9a, 0xf3, 0xa0, 0xf7, 0xee, 0xb0, 0xf7,
+	0xc9, 0xb7, 0x1d, 0xf0, 0xcc, 0xdf, 0xca, 0x33, 0x5a, 0x5f, 0xb2, 0x8c, 0xa2, 0x67, 0x30, 0x5b,
+	0x10, 0xbe, 0x96, 0x1a, 0xba, 0x13, 0x99, 0xa4, 0xa3, 0xcd, 0x24, 0x83, 0x99, 0x2d, 0xdb, 0x68,
+	0xb0, 0x83, 0x5e, 0x80, 0xb7, 0x46, 0x6d, 0x67, 0xfe, 0x8b, 0x69, 0xc2, 0x09, 0x0e, 0xa3, 0x6e,
+	0x65, 0xa2, 0x7e, 0x65, 0xa2, 0x97, 0xed, 0xca, 0x60, 0x07, 0x3d, 0x05, 0x58, 0x10, 0xfe, 0x46,
+	0xa7, 0x25, 0x93, 0xc5, 0x16, 0xf6, 0x50, 0xf6, 0x39, 0x4c, 0x17, 0x84, 0x9f, 0xe9, 0x54, 0x66,
+	0x35, 0x4b, 0xe9, 0xb5, 0x55, 0x1f, 0xc3, 0x24, 0x79, 0x4f, 0xb9, 0x62, 0x17, 0x0d, 0x9a, 0xf6,
+	0xdd, 0xdb, 0x4c, 0x87, 0x5a, 0x27, 0x19, 0x4c, 0x4f, 0xb5, 0x2a, 0x5e, 0xf7, 0xbf, 0x75, 0x04,
+	0x23, 0x93, 0x0d, 0xba, 0x69, 0x9f, 0xf6, 0x49, 0x05, 0x1b, 0x9d, 0xb0, 0x83, 0x1e, 0x6d, 0x55,
+	0xf1, 0x2c, 0xea, 0x16, 0x05, 0x3b, 0xf3, 0x5b, 0xdf, 0x57, 0xa1, 0xfb, 0x63, 0x15, 0xba, 0x3f,
+	0x57, 0xa1, 0xfb, 0xf5, 0x57, 0xe8, 0xa4, 0x63, 0x63, 0xfa, 0xc9, 0xef, 0x01, 0x00, 0xdf, 0x05,
+	0x48, 0xf0, 0x81, 0x03, 0x00, 0x00,
 }
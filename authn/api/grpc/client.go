@@ -46,7 +46,7 @@ func (client grpcClient) Issue(ctx context.Context, req *alpha.IssueReq, _ ...gr
 	ctx, close := context.WithTimeout(ctx, client.timeout)
 	defer close()
 
-	res, err := client.issue(ctx, issueReq{issuer: req.GetIssuer(), keyType: req.Type})
+	res, err := client.issue(ctx, issueReq{issuer: req.GetIssuer(), keyType: req.GetType(), organization: req.GetOrganization()})
 	if err != nil {
 		return nil, err
 	}
@@ -57,12 +57,12 @@ func (client grpcClient) Issue(ctx context.Context, req *alpha.IssueReq, _ ...gr
 
 func encodeIssueRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(issueReq)
-	return &alpha.IssueReq{Issuer: req.issuer, Type: req.keyType}, nil
+	return &alpha.IssueReq{Issuer: req.issuer, Type: req.keyType, Organization: req.organization}, nil
 }
 
 func decodeIssueResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*alpha.UserID)
-	return identityRes{res.GetValue(), nil}, nil
+	return identityRes{id: res.GetValue()}, nil
 }
 
 func (client grpcClient) Identify(ctx context.Context, token *alpha.Token, _ ...grpc.CallOption) (*alpha.UserID, error) {
@@ -75,7 +75,7 @@ func (client grpcClient) Identify(ctx context.Context, token *alpha.Token, _ ...
 	}
 
 	ir := res.(identityRes)
-	return &alpha.UserID{Value: ir.id}, ir.err
+	return &alpha.UserID{Value: ir.id, Organization: ir.organization}, ir.err
 }
 
 func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -85,5 +85,5 @@ func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 
 func decodeIdentifyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*alpha.UserID)
-	return identityRes{res.GetValue(), nil}, nil
+	return identityRes{id: res.GetValue(), organization: res.GetOrganization()}, nil
 }
@@ -17,8 +17,9 @@ func issueEndpoint(svc authn.Service) endpoint.Endpoint {
 
 		now := time.Now().UTC()
 		key := authn.Key{
-			Type:     req.keyType,
-			IssuedAt: now,
+			Type:         req.keyType,
+			Organization: req.organization,
+			IssuedAt:     now,
 		}
 
 		k, err := svc.Issue(ctx, req.issuer, key)
@@ -26,7 +27,7 @@ func issueEndpoint(svc authn.Service) endpoint.Endpoint {
 			return identityRes{}, err
 		}
 
-		return identityRes{k.Secret, nil}, nil
+		return identityRes{id: k.Secret}, nil
 	}
 }
 
@@ -42,6 +43,6 @@ func identifyEndpoint(svc authn.Service) endpoint.Endpoint {
 			return identityRes{}, err
 		}
 
-		return identityRes{id, nil}, nil
+		return identityRes{id: id.ID, organization: id.Organization}, nil
 	}
 }
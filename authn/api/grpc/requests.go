@@ -21,8 +21,9 @@ func (req identityReq) validate() error {
 }
 
 type issueReq struct {
-	issuer  string
-	keyType uint32
+	issuer       string
+	keyType      uint32
+	organization string
 }
 
 func (req issueReq) validate() error {
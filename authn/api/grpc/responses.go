@@ -1,6 +1,7 @@
 package grpc
 
 type identityRes struct {
-	id  string
-	err error
+	id           string
+	organization string
+	err          error
 }
@@ -51,7 +51,7 @@ func (s *grpcServer) Identify(ctx context.Context, token *alpha.Token) (*alpha.U
 
 func decodeIssueRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*alpha.IssueReq)
-	return issueReq{issuer: req.GetIssuer(), keyType: req.GetType()}, nil
+	return issueReq{issuer: req.GetIssuer(), keyType: req.GetType(), organization: req.GetOrganization()}, nil
 }
 
 func encodeIssueResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
@@ -66,7 +66,7 @@ func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 
 func encodeIdentifyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(identityRes)
-	return &alpha.UserID{Value: res.id}, encodeError(res.err)
+	return &alpha.UserID{Value: res.id, Organization: res.organization}, encodeError(res.err)
 }
 
 func encodeError(err error) error {
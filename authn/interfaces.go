@@ -38,14 +38,23 @@ type Tokenizer interface {
 	Parse(string) (Key, error)
 }
 
-// Key represents API key.
+// Key represents API key. Organization is the active organization of the
+// user the key is issued to, if any.
 type Key struct {
-	ID        string
-	Type      uint32
-	Issuer    string
-	Secret    string
-	IssuedAt  time.Time
-	ExpiresAt time.Time
+	ID           string
+	Type         uint32
+	Issuer       string
+	Secret       string
+	Organization string
+	IssuedAt     time.Time
+	ExpiresAt    time.Time
+}
+
+// Identity represents the user identified by the key, acting on behalf of
+// the active organization.
+type Identity struct {
+	ID           string
+	Organization string
 }
 
 // Expired verifies if the key is expired.
@@ -10,7 +10,8 @@ import (
 
 type claims struct {
 	jwt.StandardClaims
-	Type *uint32 `json:"type,omitempty"`
+	Type         *uint32 `json:"type,omitempty"`
+	Organization string  `json:"org,omitempty"`
 }
 
 func (c claims) Valid() error {
@@ -37,7 +38,8 @@ func (svc tokenizer) Issue(key authn.Key) (string, error) {
 			Subject:  key.Secret,
 			IssuedAt: key.IssuedAt.UTC().Unix(),
 		},
-		Type: &key.Type,
+		Type:         &key.Type,
+		Organization: key.Organization,
 	}
 
 	if !key.ExpiresAt.IsZero() {
@@ -78,8 +80,9 @@ func (c claims) toKey() authn.Key {
 	key := authn.Key{
 		ID:       c.Id,
 		Issuer:   c.Issuer,
-		Secret:   c.Subject,
-		IssuedAt: time.Unix(c.IssuedAt, 0).UTC(),
+		Secret:       c.Subject,
+		Organization: c.Organization,
+		IssuedAt:     time.Unix(c.IssuedAt, 0).UTC(),
 	}
 	if c.ExpiresAt != 0 {
 		key.ExpiresAt = time.Unix(c.ExpiresAt, 0).UTC()
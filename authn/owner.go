@@ -0,0 +1,31 @@
+package authn
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+)
+
+// PersonalOrganization returns the ID of the personal organization of the
+// user. It is derived from the user email, so that the resources owned by
+// the user before the organizations were introduced can be migrated to the
+// personal organization independently in each service database (as
+// md5(email)::uuid).
+func PersonalOrganization(email string) string {
+	sum := md5.Sum([]byte(email))
+	h := hex.EncodeToString(sum[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:])
+}
+
+// Owner returns the owner of the resources of the user identified by the
+// key, being the organization the user acts on behalf of. Keys issued
+// before the organizations were introduced have no organization, so that
+// the user acts on behalf of the personal organization, which the resources
+// of the user were migrated to.
+func Owner(user, org string) string {
+	if org != "" {
+		return org
+	}
+
+	return PersonalOrganization(user)
+}
@@ -48,10 +48,11 @@ type Service interface {
 	// ID, that is issued by the user identified by the provided key.
 	Retrieve(context.Context, string, string) (Key, error)
 
-	// Identify validates token token. If token is valid, content
-	// is returned. If token is invalid, or invocation failed for some
-	// other reason, non-nil error value is returned in response.
-	Identify(context.Context, string) (string, error)
+	// Identify validates token token. If token is valid, the identity
+	// of the key owner is returned. If token is invalid, or invocation
+	// failed for some other reason, non-nil error value is returned in
+	// response.
+	Identify(context.Context, string) (Identity, error)
 }
 
 var _ Service = (*service)(nil)
@@ -86,50 +87,50 @@ func (svc service) Issue(ctx context.Context, issuer string, key Key) (Key, erro
 }
 
 func (svc service) Revoke(ctx context.Context, issuer, id string) error {
-	email, err := svc.login(issuer)
+	login, err := svc.login(issuer)
 	if err != nil {
 		return errors.Wrap(errRevoke, err)
 	}
-	if err := svc.keys.Remove(ctx, email, id); err != nil {
+	if err := svc.keys.Remove(ctx, login.Secret, id); err != nil {
 		return errors.Wrap(errRevoke, err)
 	}
 	return nil
 }
 
 func (svc service) Retrieve(ctx context.Context, issuer, id string) (Key, error) {
-	email, err := svc.login(issuer)
+	login, err := svc.login(issuer)
 	if err != nil {
 		return Key{}, errors.Wrap(errRetrieve, err)
 	}
 
-	return svc.keys.Retrieve(ctx, email, id)
+	return svc.keys.Retrieve(ctx, login.Secret, id)
 }
 
-func (svc service) Identify(ctx context.Context, token string) (string, error) {
+func (svc service) Identify(ctx context.Context, token string) (Identity, error) {
 	c, err := svc.tokenizer.Parse(token)
 	if err != nil {
-		return "", errors.Wrap(errIdentify, err)
+		return Identity{}, errors.Wrap(errIdentify, err)
 	}
 
 	switch c.Type {
 	case APIKey:
 		k, err := svc.keys.Retrieve(ctx, c.Issuer, c.ID)
 		if err != nil {
-			return "", err
+			return Identity{}, err
 		}
 		// Auto revoke expired key.
 		if k.Expired() {
 			svc.keys.Remove(ctx, c.Issuer, c.ID)
-			return "", ErrKeyExpired
+			return Identity{}, ErrKeyExpired
 		}
-		return c.Issuer, nil
+		return Identity{ID: c.Issuer, Organization: c.Organization}, nil
 	case RecoveryKey, UserKey:
 		if c.Issuer != issuerName {
-			return "", ErrUnauthorizedAccess
+			return Identity{}, ErrUnauthorizedAccess
 		}
-		return c.Secret, nil
+		return Identity{ID: c.Secret, Organization: c.Organization}, nil
 	default:
-		return "", ErrUnauthorizedAccess
+		return Identity{}, ErrUnauthorizedAccess
 	}
 }
 
@@ -147,11 +148,14 @@ func (svc service) tmpKey(issuer string, duration time.Duration, key Key) (Key,
 }
 
 func (svc service) userKey(ctx context.Context, issuer string, key Key) (Key, error) {
-	email, err := svc.login(issuer)
+	login, err := svc.login(issuer)
 	if err != nil {
 		return Key{}, errors.Wrap(errIssueUser, err)
 	}
-	key.Issuer = email
+	key.Issuer = login.Secret
+	// API key acts on behalf of the organization active at the time of
+	// issuing it.
+	key.Organization = login.Organization
 
 	id, err := svc.idp.ID()
 	if err != nil {
@@ -172,18 +176,18 @@ func (svc service) userKey(ctx context.Context, issuer string, key Key) (Key, er
 	return key, nil
 }
 
-func (svc service) login(token string) (string, error) {
+func (svc service) login(token string) (Key, error) {
 	c, err := svc.tokenizer.Parse(token)
 	if err != nil {
-		return "", err
+		return Key{}, err
 	}
 	// Only user key token is valid for login.
 	if c.Type != UserKey {
-		return "", ErrUnauthorizedAccess
+		return Key{}, ErrUnauthorizedAccess
 	}
 
 	if c.Secret == "" {
-		return "", ErrUnauthorizedAccess
+		return Key{}, ErrUnauthorizedAccess
 	}
-	return c.Secret, nil
+	return c, nil
 }
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/vietquy/alpha/bootstrap"
+)
+
+func addEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg := bootstrap.Config{
+			ExternalID:  req.ExternalID,
+			ExternalKey: req.ExternalKey,
+			Name:        req.Name,
+			Projects:    req.Projects,
+			Content:     req.Content,
+		}
+
+		saved, err := svc.Add(ctx, req.token, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toConfigRes(saved)
+		res.created = true
+
+		return res, nil
+	}
+}
+
+func viewEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg, err := svc.View(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toConfigRes(cfg), nil
+	}
+}
+
+func listEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.List(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := configsPageRes{
+			Total:   page.Total,
+			Offset:  page.Offset,
+			Limit:   page.Limit,
+			Configs: []configRes{},
+		}
+		for _, cfg := range page.Configs {
+			res.Configs = append(res.Configs, toConfigRes(cfg))
+		}
+
+		return res, nil
+	}
+}
+
+func updateEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg := bootstrap.Config{
+			ThingID: req.id,
+			Name:    req.Name,
+			Content: req.Content,
+		}
+
+		if err := svc.Update(ctx, req.token, cfg); err != nil {
+			return nil, err
+		}
+
+		return updateRes{}, nil
+	}
+}
+
+func changeStateEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(changeStateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ChangeState(ctx, req.token, req.id, *req.Enabled); err != nil {
+			return nil, err
+		}
+
+		return updateRes{}, nil
+	}
+}
+
+func removeEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Remove(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func bootstrapEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(bootstrapReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg, err := svc.Bootstrap(ctx, req.key, req.externalID)
+		if err != nil {
+			return nil, err
+		}
+
+		res := bootstrapRes{
+			ThingID:  cfg.ThingID,
+			ThingKey: cfg.ThingKey,
+			Projects: cfg.Projects,
+			Content:  cfg.Content,
+		}
+		if res.Projects == nil {
+			res.Projects = []string{}
+		}
+
+		return res, nil
+	}
+}
+
+func toConfigRes(cfg bootstrap.Config) configRes {
+	res := configRes{
+		ThingID:     cfg.ThingID,
+		ThingKey:    cfg.ThingKey,
+		Name:        cfg.Name,
+		ExternalID:  cfg.ExternalID,
+		ExternalKey: cfg.ExternalKey,
+		Projects:    cfg.Projects,
+		Content:     cfg.Content,
+		Enabled:     cfg.Enabled,
+	}
+	if res.Projects == nil {
+		res.Projects = []string{}
+	}
+
+	return res
+}
@@ -0,0 +1,133 @@
+package api
+
+import "github.com/vietquy/alpha/bootstrap"
+
+const (
+	maxLimitSize = 100
+	maxNameSize  = 1024
+	maxExtSize   = 1024
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type addReq struct {
+	token       string
+	ExternalID  string   `json:"external_id"`
+	ExternalKey string   `json:"external_key"`
+	Name        string   `json:"name,omitempty"`
+	Projects    []string `json:"projects,omitempty"`
+	Content     string   `json:"content,omitempty"`
+}
+
+func (req addReq) validate() error {
+	if req.token == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.ExternalID == "" || req.ExternalKey == "" {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	if len(req.ExternalID) > maxExtSize || len(req.ExternalKey) > maxExtSize || len(req.Name) > maxNameSize {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	for _, p := range req.Projects {
+		if p == "" {
+			return bootstrap.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type viewReq struct {
+	token string
+	id    string
+}
+
+func (req viewReq) validate() error {
+	if req.token == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type updateReq struct {
+	token   string
+	id      string
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+func (req updateReq) validate() error {
+	if req.token == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || len(req.Name) > maxNameSize {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type changeStateReq struct {
+	token   string
+	id      string
+	Enabled *bool `json:"enabled"`
+}
+
+func (req changeStateReq) validate() error {
+	if req.token == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.Enabled == nil {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type bootstrapReq struct {
+	key        string
+	externalID string
+}
+
+func (req bootstrapReq) validate() error {
+	if req.key == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.externalID == "" {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
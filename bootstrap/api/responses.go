@@ -0,0 +1,120 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vietquy/alpha"
+)
+
+var (
+	_ alpha.Response = (*configRes)(nil)
+	_ alpha.Response = (*configsPageRes)(nil)
+	_ alpha.Response = (*updateRes)(nil)
+	_ alpha.Response = (*removeRes)(nil)
+	_ alpha.Response = (*bootstrapRes)(nil)
+)
+
+type configRes struct {
+	ThingID     string   `json:"thing_id"`
+	ThingKey    string   `json:"thing_key"`
+	Name        string   `json:"name,omitempty"`
+	ExternalID  string   `json:"external_id"`
+	ExternalKey string   `json:"external_key"`
+	Projects    []string `json:"projects"`
+	Content     string   `json:"content,omitempty"`
+	Enabled     bool     `json:"enabled"`
+	created     bool
+}
+
+func (res configRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res configRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/configs/%s", res.ThingID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res configRes) Empty() bool {
+	return false
+}
+
+type configsPageRes struct {
+	Total   uint64      `json:"total"`
+	Offset  uint64      `json:"offset"`
+	Limit   uint64      `json:"limit"`
+	Configs []configRes `json:"configs"`
+}
+
+func (res configsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res configsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res configsPageRes) Empty() bool {
+	return false
+}
+
+type updateRes struct{}
+
+func (res updateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type bootstrapRes struct {
+	ThingID  string   `json:"thing_id"`
+	ThingKey string   `json:"thing_key"`
+	Projects []string `json:"projects"`
+	Content  string   `json:"content,omitempty"`
+}
+
+func (res bootstrapRes) Code() int {
+	return http.StatusOK
+}
+
+func (res bootstrapRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res bootstrapRes) Empty() bool {
+	return false
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
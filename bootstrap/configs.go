@@ -0,0 +1,58 @@
+package bootstrap
+
+import "context"
+
+// Config represents the bootstrap configuration of the device. The device
+// is identified by its external ID (e.g. MAC address or serial number) and
+// authenticated using the external key shared with the operator. Once
+// bootstrapped, the device uses the credentials of the thing created for
+// it, and the projects the thing is connected to.
+type Config struct {
+	ThingID     string
+	Owner       string
+	Name        string
+	ThingKey    string
+	ExternalID  string
+	ExternalKey string
+	Projects    []string
+	Content     string
+	Enabled     bool
+}
+
+// ConfigsPage contains page related metadata as well as the list of configs
+// that belong to this page.
+type ConfigsPage struct {
+	Total   uint64
+	Offset  uint64
+	Limit   uint64
+	Configs []Config
+}
+
+// ConfigRepository specifies a config persistence API.
+type ConfigRepository interface {
+	// Save persists the config. ErrConflict is returned if the config with
+	// the same external ID already exists.
+	Save(ctx context.Context, cfg Config) error
+
+	// RetrieveByID retrieves the config of the thing having the provided
+	// identifier, that is owned by the specified user.
+	RetrieveByID(ctx context.Context, owner, id string) (Config, error)
+
+	// RetrieveByExternalID retrieves the config having the provided
+	// external ID.
+	RetrieveByExternalID(ctx context.Context, externalID string) (Config, error)
+
+	// RetrieveAll retrieves the subset of configs owned by the specified
+	// user.
+	RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (ConfigsPage, error)
+
+	// Update updates the name and the content of the existing config.
+	Update(ctx context.Context, cfg Config) error
+
+	// ChangeState enables or disables the existing config.
+	ChangeState(ctx context.Context, owner, id string, enabled bool) error
+
+	// Remove removes the config of the thing having the provided identifier,
+	// that is owned by the specified user.
+	Remove(ctx context.Context, owner, id string) error
+}
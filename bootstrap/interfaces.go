@@ -0,0 +1,28 @@
+package bootstrap
+
+import "context"
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
+
+// ThingsService specifies an API for managing the things of the user in the
+// things service. Each request is performed on behalf of the user
+// identified by the provided token, so that the created things are owned by
+// the user organization.
+type ThingsService interface {
+	// CreateThing creates the thing having the provided name and key, and
+	// returns its ID.
+	CreateThing(ctx context.Context, token, name, key string) (string, error)
+
+	// RemoveThing removes the thing having the provided ID.
+	RemoveThing(ctx context.Context, token, id string) error
+
+	// Connect connects the thing to each of the projects.
+	Connect(ctx context.Context, token, thingID string, projects []string) error
+
+	// Disconnect disconnects the thing from each of the projects.
+	Disconnect(ctx context.Context, token, thingID string, projects []string) error
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/bootstrap"
+	"github.com/vietquy/alpha/errors"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var (
+	errSaveDB     = errors.New("Save config to DB failed")
+	errRetrieveDB = errors.New("Retrieving config from DB failed")
+	errUpdateDB   = errors.New("Update config in DB failed")
+	errRemoveDB   = errors.New("Remove config from DB failed")
+)
+
+var _ bootstrap.ConfigRepository = (*configRepository)(nil)
+
+type configRepository struct {
+	db *sqlx.DB
+}
+
+// NewConfigRepository instantiates a PostgreSQL implementation of config
+// repository.
+func NewConfigRepository(db *sqlx.DB) bootstrap.ConfigRepository {
+	return &configRepository{
+		db: db,
+	}
+}
+
+func (cr configRepository) Save(ctx context.Context, cfg bootstrap.Config) error {
+	q := `INSERT INTO configs (thing_id, owner, name, thing_key, external_id, external_key, projects, content, enabled)
+	      VALUES (:thing_id, :owner, :name, :thing_key, :external_id, :external_key, :projects, :content, :enabled)`
+
+	if _, err := cr.db.NamedExecContext(ctx, q, toDBConfig(cfg)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(bootstrap.ErrConflict, err)
+		}
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	return nil
+}
+
+func (cr configRepository) RetrieveByID(ctx context.Context, owner, id string) (bootstrap.Config, error) {
+	q := `SELECT thing_id, owner, name, thing_key, external_id, external_key, projects, content, enabled
+	      FROM configs WHERE thing_id = $1 AND owner = $2`
+
+	return cr.retrieve(ctx, q, id, owner)
+}
+
+func (cr configRepository) RetrieveByExternalID(ctx context.Context, externalID string) (bootstrap.Config, error) {
+	q := `SELECT thing_id, owner, name, thing_key, external_id, external_key, projects, content, enabled
+	      FROM configs WHERE external_id = $1`
+
+	return cr.retrieve(ctx, q, externalID)
+}
+
+func (cr configRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (bootstrap.ConfigsPage, error) {
+	q := `SELECT thing_id, owner, name, thing_key, external_id, external_key, projects, content, enabled
+	      FROM configs WHERE owner = $1 ORDER BY thing_id LIMIT $2 OFFSET $3`
+
+	rows, err := cr.db.QueryxContext(ctx, q, owner, limit, offset)
+	if err != nil {
+		return bootstrap.ConfigsPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	configs := []bootstrap.Config{}
+	for rows.Next() {
+		dbc := dbConfig{}
+		if err := rows.StructScan(&dbc); err != nil {
+			return bootstrap.ConfigsPage{}, errors.Wrap(errRetrieveDB, err)
+		}
+		configs = append(configs, toConfig(dbc))
+	}
+
+	var total uint64
+	if err := cr.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM configs WHERE owner = $1`, owner).Scan(&total); err != nil {
+		return bootstrap.ConfigsPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	page := bootstrap.ConfigsPage{
+		Total:   total,
+		Offset:  offset,
+		Limit:   limit,
+		Configs: configs,
+	}
+
+	return page, nil
+}
+
+func (cr configRepository) Update(ctx context.Context, cfg bootstrap.Config) error {
+	q := `UPDATE configs SET name = :name, content = :content WHERE thing_id = :thing_id AND owner = :owner`
+
+	res, err := cr.db.NamedExecContext(ctx, q, toDBConfig(cfg))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(bootstrap.ErrNotFound, err)
+		}
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	return affected(res, errUpdateDB)
+}
+
+func (cr configRepository) ChangeState(ctx context.Context, owner, id string, enabled bool) error {
+	q := `UPDATE configs SET enabled = $1 WHERE thing_id = $2 AND owner = $3`
+
+	res, err := cr.db.ExecContext(ctx, q, enabled, id, owner)
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	return affected(res, errUpdateDB)
+}
+
+func (cr configRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM configs WHERE thing_id = $1 AND owner = $2`
+
+	if _, err := cr.db.ExecContext(ctx, q, id, owner); err != nil {
+		return errors.Wrap(errRemoveDB, err)
+	}
+
+	return nil
+}
+
+func (cr configRepository) retrieve(ctx context.Context, q string, args ...interface{}) (bootstrap.Config, error) {
+	dbc := dbConfig{}
+	if err := cr.db.QueryRowxContext(ctx, q, args...).StructScan(&dbc); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return bootstrap.Config{}, errors.Wrap(bootstrap.ErrNotFound, err)
+		}
+		return bootstrap.Config{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toConfig(dbc), nil
+}
+
+// affected returns ErrNotFound if the statement didn't affect any config.
+func affected(res sql.Result, wrapper error) error {
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(wrapper, err)
+	}
+
+	if cnt == 0 {
+		return bootstrap.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbConfig struct {
+	ThingID     string         `db:"thing_id"`
+	Owner       string         `db:"owner"`
+	Name        sql.NullString `db:"name"`
+	ThingKey    string         `db:"thing_key"`
+	ExternalID  string         `db:"external_id"`
+	ExternalKey string         `db:"external_key"`
+	Projects    pq.StringArray `db:"projects"`
+	Content     sql.NullString `db:"content"`
+	Enabled     bool           `db:"enabled"`
+}
+
+func toDBConfig(cfg bootstrap.Config) dbConfig {
+	return dbConfig{
+		ThingID:     cfg.ThingID,
+		Owner:       cfg.Owner,
+		Name:        sql.NullString{String: cfg.Name, Valid: cfg.Name != ""},
+		ThingKey:    cfg.ThingKey,
+		ExternalID:  cfg.ExternalID,
+		ExternalKey: cfg.ExternalKey,
+		Projects:    cfg.Projects,
+		Content:     sql.NullString{String: cfg.Content, Valid: cfg.Content != ""},
+		Enabled:     cfg.Enabled,
+	}
+}
+
+func toConfig(dbc dbConfig) bootstrap.Config {
+	return bootstrap.Config{
+		ThingID:     dbc.ThingID,
+		Owner:       dbc.Owner,
+		Name:        dbc.Name.String,
+		ThingKey:    dbc.ThingKey,
+		ExternalID:  dbc.ExternalID,
+		ExternalKey: dbc.ExternalKey,
+		Projects:    dbc.Projects,
+		Content:     dbc.Content.String,
+		Enabled:     dbc.Enabled,
+	}
+}
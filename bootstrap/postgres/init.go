@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host string
+	Port string
+	User string
+	Pass string
+	Name string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "bootstrap_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS configs (
+						thing_id     UUID PRIMARY KEY,
+						owner        VARCHAR(254) NOT NULL,
+						name         VARCHAR(1024),
+						thing_key    VARCHAR(4096) NOT NULL,
+						external_id  VARCHAR(1024) UNIQUE NOT NULL,
+						external_key VARCHAR(1024) NOT NULL,
+						projects     TEXT[],
+						content      TEXT,
+						enabled      BOOLEAN NOT NULL DEFAULT TRUE
+					)`,
+					`CREATE INDEX IF NOT EXISTS configs_owner_idx ON configs (owner)`,
+				},
+				Down: []string{"DROP TABLE configs"},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
@@ -0,0 +1,236 @@
+package bootstrap
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/authn"
+	"github.com/vietquy/alpha/errors"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrConflict indicates that the config with the same external ID
+	// already exists.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrDisabled indicates the bootstrap attempt using the disabled config.
+	ErrDisabled = errors.New("config is disabled")
+
+	// ErrThings indicates failed communication with the things service.
+	ErrThings = errors.New("things service request failed")
+
+	// ErrAddConfig indicates error in adding the config.
+	ErrAddConfig = errors.New("add config failed")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Add creates the thing for the device and adds its config to the user
+	// identified by the provided key. The created config is enabled, so the
+	// thing is connected to the config projects.
+	Add(ctx context.Context, token string, cfg Config) (Config, error)
+
+	// View retrieves the config of the thing identified by the provided ID,
+	// that belongs to the user identified by the provided key.
+	View(ctx context.Context, token, id string) (Config, error)
+
+	// List retrieves the subset of configs that belong to the user
+	// identified by the provided key.
+	List(ctx context.Context, token string, offset, limit uint64) (ConfigsPage, error)
+
+	// Update updates the name and the content of the config, that belongs to
+	// the user identified by the provided key.
+	Update(ctx context.Context, token string, cfg Config) error
+
+	// ChangeState enables or disables the config, connecting the thing to
+	// the config projects or disconnecting it from them respectively.
+	ChangeState(ctx context.Context, token, id string, enabled bool) error
+
+	// Remove removes the config along with its thing.
+	Remove(ctx context.Context, token, id string) error
+
+	// Bootstrap returns the enabled config of the device identified by the
+	// external ID, provided that the external key matches.
+	Bootstrap(ctx context.Context, externalKey, externalID string) (Config, error)
+}
+
+var _ Service = (*bootstrapService)(nil)
+
+type bootstrapService struct {
+	auth    alpha.AuthNServiceClient
+	configs ConfigRepository
+	things  ThingsService
+	idp     IdentityProvider
+}
+
+// New instantiates the bootstrap service implementation.
+func New(auth alpha.AuthNServiceClient, configs ConfigRepository, things ThingsService, idp IdentityProvider) Service {
+	return &bootstrapService{
+		auth:    auth,
+		configs: configs,
+		things:  things,
+		idp:     idp,
+	}
+}
+
+func (bs bootstrapService) Add(ctx context.Context, token string, cfg Config) (Config, error) {
+	owner, err := bs.identify(ctx, token)
+	if err != nil {
+		return Config{}, err
+	}
+
+	key, err := bs.idp.ID()
+	if err != nil {
+		return Config{}, errors.Wrap(ErrAddConfig, err)
+	}
+
+	id, err := bs.things.CreateThing(ctx, token, cfg.Name, key)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg.ThingID = id
+	cfg.ThingKey = key
+	cfg.Owner = owner
+	cfg.Enabled = true
+
+	if err := bs.add(ctx, token, cfg); err != nil {
+		// The thing is useless without the config, so it is removed,
+		// along with its connections.
+		if rmErr := bs.things.RemoveThing(ctx, token, id); rmErr != nil {
+			return Config{}, errors.Wrap(err, rmErr)
+		}
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func (bs bootstrapService) add(ctx context.Context, token string, cfg Config) error {
+	if len(cfg.Projects) > 0 {
+		if err := bs.things.Connect(ctx, token, cfg.ThingID, cfg.Projects); err != nil {
+			return err
+		}
+	}
+
+	return bs.configs.Save(ctx, cfg)
+}
+
+func (bs bootstrapService) View(ctx context.Context, token, id string) (Config, error) {
+	owner, err := bs.identify(ctx, token)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return bs.configs.RetrieveByID(ctx, owner, id)
+}
+
+func (bs bootstrapService) List(ctx context.Context, token string, offset, limit uint64) (ConfigsPage, error) {
+	owner, err := bs.identify(ctx, token)
+	if err != nil {
+		return ConfigsPage{}, err
+	}
+
+	return bs.configs.RetrieveAll(ctx, owner, offset, limit)
+}
+
+func (bs bootstrapService) Update(ctx context.Context, token string, cfg Config) error {
+	owner, err := bs.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	cfg.Owner = owner
+	return bs.configs.Update(ctx, cfg)
+}
+
+func (bs bootstrapService) ChangeState(ctx context.Context, token, id string, enabled bool) error {
+	owner, err := bs.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := bs.configs.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Enabled == enabled {
+		return nil
+	}
+
+	if len(cfg.Projects) > 0 {
+		switch enabled {
+		case true:
+			err = bs.things.Connect(ctx, token, cfg.ThingID, cfg.Projects)
+		default:
+			err = bs.things.Disconnect(ctx, token, cfg.ThingID, cfg.Projects)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return bs.configs.ChangeState(ctx, owner, id, enabled)
+}
+
+func (bs bootstrapService) Remove(ctx context.Context, token, id string) error {
+	owner, err := bs.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := bs.configs.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	if err := bs.things.RemoveThing(ctx, token, cfg.ThingID); err != nil && !errors.Contains(err, ErrNotFound) {
+		return err
+	}
+
+	return bs.configs.Remove(ctx, owner, id)
+}
+
+func (bs bootstrapService) Bootstrap(ctx context.Context, externalKey, externalID string) (Config, error) {
+	cfg, err := bs.configs.RetrieveByExternalID(ctx, externalID)
+	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return Config{}, ErrUnauthorizedAccess
+		}
+		return Config{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(cfg.ExternalKey), []byte(externalKey)) != 1 {
+		return Config{}, ErrUnauthorizedAccess
+	}
+
+	if !cfg.Enabled {
+		return Config{}, ErrDisabled
+	}
+
+	return cfg, nil
+}
+
+// identify returns the owner of the configs of the user identified by the
+// provided token, see authn.Owner.
+func (bs bootstrapService) identify(ctx context.Context, token string) (string, error) {
+	res, err := bs.auth.Identify(ctx, &alpha.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return authn.Owner(res.GetValue(), res.GetOrganization()), nil
+}
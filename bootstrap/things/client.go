@@ -0,0 +1,134 @@
+// Package things provides the client of the things service HTTP API.
+package things
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vietquy/alpha/bootstrap"
+	"github.com/vietquy/alpha/errors"
+)
+
+const (
+	contentType   = "application/json"
+	thingsPrefix  = "/things/"
+	locationHdr   = "Location"
+	authorization = "Authorization"
+)
+
+var _ bootstrap.ThingsService = (*client)(nil)
+
+type client struct {
+	url  string
+	http *http.Client
+}
+
+// NewClient instantiates the client of the things service HTTP API served
+// at the provided URL.
+func NewClient(url string, timeout time.Duration) bootstrap.ThingsService {
+	return &client{
+		url:  strings.TrimSuffix(url, "/"),
+		http: &http.Client{Timeout: timeout},
+	}
+}
+
+type thingReq struct {
+	Name string `json:"name,omitempty"`
+	Key  string `json:"key"`
+}
+
+type connectionsReq struct {
+	ProjectIDs []string `json:"project_ids"`
+	ThingIDs   []string `json:"thing_ids"`
+}
+
+func (c client) CreateThing(ctx context.Context, token, name, key string) (string, error) {
+	res, err := c.send(ctx, http.MethodPost, "/things", token, thingReq{Name: name, Key: key}, http.StatusCreated)
+	if err != nil {
+		return "", err
+	}
+
+	id := strings.TrimPrefix(res.Header.Get(locationHdr), thingsPrefix)
+	if id == "" {
+		return "", errors.Wrap(bootstrap.ErrThings, errors.New("missing thing location"))
+	}
+
+	return id, nil
+}
+
+func (c client) RemoveThing(ctx context.Context, token, id string) error {
+	_, err := c.send(ctx, http.MethodDelete, thingsPrefix+id, token, nil, http.StatusNoContent)
+	return err
+}
+
+func (c client) Connect(ctx context.Context, token, thingID string, projects []string) error {
+	req := connectionsReq{
+		ProjectIDs: projects,
+		ThingIDs:   []string{thingID},
+	}
+
+	_, err := c.send(ctx, http.MethodPost, "/connect", token, req, http.StatusOK)
+	return err
+}
+
+func (c client) Disconnect(ctx context.Context, token, thingID string, projects []string) error {
+	req := connectionsReq{
+		ProjectIDs: projects,
+		ThingIDs:   []string{thingID},
+	}
+
+	// Projects the thing is not connected to are reported by the things
+	// service, but the thing ends up disconnected from all of them anyway.
+	_, err := c.send(ctx, http.MethodPost, "/disconnect", token, req, http.StatusOK)
+	return err
+}
+
+// send sends the request to the things service and returns the response if
+// its status matches the expected one. The response body is discarded.
+func (c client) send(ctx context.Context, method, path, token string, body interface{}, status int) (*http.Response, error) {
+	var data []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(bootstrap.ErrThings, err)
+		}
+		data = b
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(bootstrap.ErrThings, err)
+	}
+	req.Header.Set(authorization, token)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(bootstrap.ErrThings, err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode == status {
+		return res, nil
+	}
+
+	switch res.StatusCode {
+	case http.StatusBadRequest:
+		return nil, bootstrap.ErrMalformedEntity
+	case http.StatusForbidden:
+		return nil, bootstrap.ErrUnauthorizedAccess
+	case http.StatusNotFound:
+		return nil, bootstrap.ErrNotFound
+	case http.StatusUnprocessableEntity:
+		return nil, bootstrap.ErrConflict
+	default:
+		return nil, errors.Wrap(bootstrap.ErrThings, errors.New(fmt.Sprintf("unexpected status %d", res.StatusCode)))
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/vietquy/alpha/certs"
+)
+
+func issueCertEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cert, err := svc.IssueCert(ctx, req.token, req.ThingID, req.ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toCertRes(cert)
+		res.created = true
+
+		return res, nil
+	}
+}
+
+func viewCertEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cert, err := svc.ViewCert(ctx, req.token, req.serial)
+		if err != nil {
+			return nil, err
+		}
+
+		return toCertRes(cert), nil
+	}
+}
+
+func listCertsEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListCerts(ctx, req.token, req.thingID, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := certsPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Certs:  []certRes{},
+		}
+		for _, cert := range page.Certs {
+			res.Certs = append(res.Certs, toCertRes(cert))
+		}
+
+		return res, nil
+	}
+}
+
+func revokeCertEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cert, err := svc.RevokeCert(ctx, req.token, req.serial)
+		if err != nil {
+			return nil, err
+		}
+
+		return toCertRes(cert), nil
+	}
+}
+
+func crlEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		crl, err := svc.CRL(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return crlRes(crl), nil
+	}
+}
+
+func toCertRes(cert certs.Cert) certRes {
+	res := certRes{
+		Serial:     cert.Serial,
+		ThingID:    cert.ThingID,
+		Expire:     cert.Expire,
+		ClientCert: cert.ClientCert,
+		ClientKey:  cert.ClientKey,
+		IssuingCA:  cert.IssuingCA,
+	}
+	if cert.Revoked() {
+		res.RevokedAt = &cert.RevokedAt
+	}
+
+	return res
+}
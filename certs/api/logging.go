@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vietquy/alpha/certs"
+	log "github.com/vietquy/alpha/logger"
+)
+
+var _ certs.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    certs.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc certs.Service, logger log.Logger) certs.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) IssueCert(ctx context.Context, token, thingID string, ttl time.Duration) (cert certs.Cert, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method issue_cert for thing %s and serial %s took %s to complete", thingID, cert.Serial, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.IssueCert(ctx, token, thingID, ttl)
+}
+
+func (lm *loggingMiddleware) ViewCert(ctx context.Context, token, serial string) (_ certs.Cert, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_cert for serial %s took %s to complete", serial, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewCert(ctx, token, serial)
+}
+
+func (lm *loggingMiddleware) ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (_ certs.CertsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_certs for thing %s, offset %d and limit %d took %s to complete", thingID, offset, limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListCerts(ctx, token, thingID, offset, limit)
+}
+
+func (lm *loggingMiddleware) RevokeCert(ctx context.Context, token, serial string) (_ certs.Cert, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_cert for serial %s took %s to complete", serial, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeCert(ctx, token, serial)
+}
+
+func (lm *loggingMiddleware) CRL(ctx context.Context) (_ []byte, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method crl took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CRL(ctx)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"time"
+
+	"github.com/vietquy/alpha/certs"
+)
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type issueReq struct {
+	token   string
+	ttl     time.Duration
+	ThingID string `json:"thing_id"`
+	TTL     string `json:"ttl,omitempty"`
+}
+
+func (req issueReq) validate() error {
+	if req.token == "" {
+		return certs.ErrUnauthorizedAccess
+	}
+
+	if req.ThingID == "" || req.ttl < 0 {
+		return certs.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewReq struct {
+	token  string
+	serial string
+}
+
+func (req viewReq) validate() error {
+	if req.token == "" {
+		return certs.ErrUnauthorizedAccess
+	}
+
+	if req.serial == "" {
+		return certs.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token   string
+	thingID string
+	offset  uint64
+	limit   uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return certs.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return certs.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vietquy/alpha"
+)
+
+var (
+	_ alpha.Response = (*certRes)(nil)
+	_ alpha.Response = (*certsPageRes)(nil)
+)
+
+type certRes struct {
+	Serial     string     `json:"serial"`
+	ThingID    string     `json:"thing_id"`
+	Expire     time.Time  `json:"expire"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ClientCert string     `json:"client_cert,omitempty"`
+	ClientKey  string     `json:"client_key,omitempty"`
+	IssuingCA  string     `json:"issuing_ca,omitempty"`
+	created    bool
+}
+
+func (res certRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res certRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/certs/%s", res.Serial),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res certRes) Empty() bool {
+	return false
+}
+
+type certsPageRes struct {
+	Total  uint64    `json:"total"`
+	Offset uint64    `json:"offset"`
+	Limit  uint64    `json:"limit"`
+	Certs  []certRes `json:"certs"`
+}
+
+func (res certsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res certsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res certsPageRes) Empty() bool {
+	return false
+}
+
+type crlRes []byte
+
+type errorRes struct {
+	Err string `json:"error"`
+}
@@ -0,0 +1,58 @@
+package certs
+
+import (
+	"context"
+	"time"
+)
+
+// Cert represents the client certificate issued for the thing. The thing is
+// identified by the common name of the certificate, which holds its ID.
+type Cert struct {
+	Serial    string
+	ThingID   string
+	Owner     string
+	Expire    time.Time
+	RevokedAt time.Time
+
+	// The PEM encoded certificate, its private key and the certificate of
+	// the issuing CA are available only when the certificate is issued,
+	// since the private key is not persisted.
+	ClientCert string
+	ClientKey  string
+	IssuingCA  string
+}
+
+// Revoked returns true if the certificate is revoked.
+func (c Cert) Revoked() bool {
+	return !c.RevokedAt.IsZero()
+}
+
+// CertsPage contains page related metadata as well as the list of certs
+// that belong to this page.
+type CertsPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Certs  []Cert
+}
+
+// CertRepository specifies a cert persistence API.
+type CertRepository interface {
+	// Save persists the cert.
+	Save(ctx context.Context, cert Cert) error
+
+	// RetrieveBySerial retrieves the cert having the provided serial number,
+	// that is owned by the specified user.
+	RetrieveBySerial(ctx context.Context, owner, serial string) (Cert, error)
+
+	// RetrieveAll retrieves the subset of certs owned by the specified user.
+	// If the thing ID is provided, only the certs of the thing are retrieved.
+	RetrieveAll(ctx context.Context, owner, thingID string, offset, limit uint64) (CertsPage, error)
+
+	// Revoke marks the cert having the provided serial number as revoked at
+	// the provided time.
+	Revoke(ctx context.Context, owner, serial string, at time.Time) error
+
+	// RetrieveRevoked retrieves all revoked certs that are not expired yet.
+	RetrieveRevoked(ctx context.Context) ([]Cert, error)
+}
@@ -0,0 +1,28 @@
+package certs
+
+import (
+	"context"
+	"time"
+)
+
+// PKI specifies an API for issuing the client certificates signed by the
+// configured CA.
+type PKI interface {
+	// Issue issues the certificate having the provided common name, valid
+	// for the provided duration. The default validity is used if the
+	// duration is zero. The returned cert contains its serial number, expiry
+	// time, and the PEM encoded certificate, private key and issuing CA.
+	Issue(commonName string, ttl time.Duration) (Cert, error)
+
+	// CRL creates the PEM encoded certificate revocation list, signed by the
+	// CA, containing the provided revoked certs.
+	CRL(revoked []Cert) ([]byte, error)
+}
+
+// ThingsService specifies an API for accessing the things of the user in the
+// things service, on behalf of the user identified by the provided token.
+type ThingsService interface {
+	// ViewThing checks that the thing having the provided ID exists and is
+	// accessible by the user.
+	ViewThing(ctx context.Context, token, id string) error
+}
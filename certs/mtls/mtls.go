@@ -0,0 +1,197 @@
+// Package mtls provides the TLS configuration of the protocol adapters that
+// authenticate things using the client certificates issued by the certs
+// service.
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/logger"
+)
+
+const (
+	crlTimeout = 5 * time.Second
+	// crlRetry is the interval of fetching the stale list, which is shorter
+	// than the refresh period, but still keeps the handshakes from waiting
+	// for the unreachable endpoint one after another.
+	crlRetry = 30 * time.Second
+)
+
+var (
+	// ErrLoadCerts indicates failure to load the server certificate or the
+	// client CA certificates.
+	ErrLoadCerts = errors.New("failed to load certificates")
+
+	// ErrRevoked indicates that the client certificate is revoked.
+	ErrRevoked = errors.New("client certificate is revoked")
+
+	// ErrStaleCRL indicates that the client certificate can't be checked,
+	// since the certificate revocation list is past its next update.
+	ErrStaleCRL = errors.New("certificate revocation list is stale")
+
+	errNoCACerts = errors.New("no CA certificates found")
+	errFetchCRL  = errors.New("failed to fetch certificate revocation list")
+)
+
+// Config defines the options of the TLS server authenticating things by the
+// client certificates.
+type Config struct {
+	// CertFile and KeyFile are the PEM encoded server certificate and key.
+	CertFile string
+	KeyFile  string
+
+	// CAFile contains the PEM encoded certificates of the CAs signing the
+	// client certificates.
+	CAFile string
+
+	// CRLURL is the optional URL of the certificate revocation list, fetched
+	// once the CRLRefresh period elapses. Client certificates are rejected
+	// once the list is past its next update, or past the refresh period if
+	// the list doesn't specify the next update. Without the list, revoked
+	// client certificates are accepted until they expire.
+	CRLURL     string
+	CRLRefresh time.Duration
+}
+
+// ServerConfig returns the TLS configuration verifying the client
+// certificates if provided. Clients that don't provide the certificate are
+// still accepted, so that they can authenticate using the thing key.
+func ServerConfig(cfg Config, logger logger.Logger) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(ErrLoadCerts, err)
+	}
+
+	pem, err := ioutil.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, errors.Wrap(ErrLoadCerts, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.Wrap(ErrLoadCerts, errNoCACerts)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.CRLURL == "" {
+		logger.Warn("Certificate revocation list URL is not set, revoked client certificates are accepted until they expire")
+		return tlsCfg, nil
+	}
+
+	crl := &revocationList{
+		url:     cfg.CRLURL,
+		refresh: cfg.CRLRefresh,
+		http:    &http.Client{Timeout: crlTimeout},
+		logger:  logger,
+		revoked: map[string]bool{},
+	}
+	tlsCfg.VerifyPeerCertificate = crl.verify
+
+	return tlsCfg, nil
+}
+
+// ThingID returns the ID of the thing authenticated by the verified client
+// certificate, or an empty string if the client didn't provide one.
+func ThingID(cs *tls.ConnectionState) string {
+	if cs == nil || len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	return cs.VerifiedChains[0][0].Subject.CommonName
+}
+
+// revocationList keeps the serial numbers of the revoked certificates. The
+// list is refreshed lazily on the client handshake. If the refresh fails,
+// the previously fetched list is used until it becomes stale.
+type revocationList struct {
+	url     string
+	refresh time.Duration
+	http    *http.Client
+	logger  logger.Logger
+
+	mu      sync.Mutex
+	fetched time.Time
+	// nextUpdate is the time the list becomes stale.
+	nextUpdate time.Time
+	revoked    map[string]bool
+}
+
+func (rl *revocationList) verify(_ [][]byte, chains [][]*x509.Certificate) error {
+	if len(chains) == 0 || len(chains[0]) < 2 {
+		return nil
+	}
+	cert, issuer := chains[0][0], chains[0][1]
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	stale := !time.Now().Before(rl.nextUpdate)
+	if time.Since(rl.fetched) > rl.refresh || (stale && time.Since(rl.fetched) > crlRetry) {
+		if err := rl.fetch(issuer); err != nil {
+			rl.logger.Warn(fmt.Sprintf("Failed to refresh certificate revocation list: %s", err))
+		}
+		rl.fetched = time.Now()
+	}
+
+	if !time.Now().Before(rl.nextUpdate) {
+		return ErrStaleCRL
+	}
+
+	if rl.revoked[cert.SerialNumber.String()] {
+		return ErrRevoked
+	}
+
+	return nil
+}
+
+func (rl *revocationList) fetch(issuer *x509.Certificate) error {
+	res, err := rl.http.Get(rl.url)
+	if err != nil {
+		return errors.Wrap(errFetchCRL, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Wrap(errFetchCRL, errors.New(fmt.Sprintf("unexpected status %d", res.StatusCode)))
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(errFetchCRL, err)
+	}
+
+	crl, err := x509.ParseCRL(body)
+	if err != nil {
+		return errors.Wrap(errFetchCRL, err)
+	}
+
+	if err := issuer.CheckCRLSignature(crl); err != nil {
+		return errors.Wrap(errFetchCRL, err)
+	}
+
+	revoked := map[string]bool{}
+	for _, rc := range crl.TBSCertList.RevokedCertificates {
+		revoked[rc.SerialNumber.String()] = true
+	}
+	rl.revoked = revoked
+
+	rl.nextUpdate = crl.TBSCertList.NextUpdate
+	if rl.nextUpdate.IsZero() {
+		rl.nextUpdate = time.Now().Add(rl.refresh)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package pki provides the PKI issuing the client certificates signed by the
+// CA loaded from the PEM encoded files.
+package pki
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/vietquy/alpha/certs"
+	"github.com/vietquy/alpha/errors"
+)
+
+const (
+	certType = "CERTIFICATE"
+	keyType  = "RSA PRIVATE KEY"
+	crlType  = "X509 CRL"
+
+	serialBits = 128
+	crlTTL     = 24 * time.Hour
+)
+
+var (
+	// ErrLoadCA indicates failure to load the CA certificate and key.
+	ErrLoadCA = errors.New("failed to load CA")
+
+	errInvalidKey = errors.New("CA private key can not be used for signing")
+)
+
+var _ certs.PKI = (*pki)(nil)
+
+type pki struct {
+	ca      *x509.Certificate
+	caKey   crypto.Signer
+	caPEM   string
+	ttl     time.Duration
+	rsaBits int
+}
+
+// Load loads the CA from the PEM encoded certificate and private key files.
+// The issued certificates are valid for the provided default duration and
+// use RSA keys of the provided size.
+func Load(certFile, keyFile string, ttl time.Duration, rsaBits int) (certs.PKI, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(ErrLoadCA, err)
+	}
+
+	ca, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrap(ErrLoadCA, err)
+	}
+
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.Wrap(ErrLoadCA, errInvalidKey)
+	}
+
+	p := &pki{
+		ca:      ca,
+		caKey:   key,
+		caPEM:   string(pem.EncodeToMemory(&pem.Block{Type: certType, Bytes: ca.Raw})),
+		ttl:     ttl,
+		rsaBits: rsaBits,
+	}
+
+	return p, nil
+}
+
+func (p pki) Issue(commonName string, ttl time.Duration) (certs.Cert, error) {
+	if ttl == 0 {
+		ttl = p.ttl
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBits))
+	if err != nil {
+		return certs.Cert{}, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, p.rsaBits)
+	if err != nil {
+		return certs.Cert{}, err
+	}
+
+	now := time.Now().UTC()
+	tmpl := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName: commonName,
+		},
+		NotBefore:   now,
+		NotAfter:    now.Add(ttl),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, p.ca, &key.PublicKey, p.caKey)
+	if err != nil {
+		return certs.Cert{}, err
+	}
+
+	cert := certs.Cert{
+		Serial:     serial.Text(16),
+		Expire:     tmpl.NotAfter,
+		ClientCert: string(pem.EncodeToMemory(&pem.Block{Type: certType, Bytes: der})),
+		ClientKey:  string(pem.EncodeToMemory(&pem.Block{Type: keyType, Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		IssuingCA:  p.caPEM,
+	}
+
+	return cert, nil
+}
+
+func (p pki) CRL(revoked []certs.Cert) ([]byte, error) {
+	now := time.Now().UTC()
+	tmpl := x509.RevocationList{
+		Number:     big.NewInt(now.Unix()),
+		ThisUpdate: now,
+		NextUpdate: now.Add(crlTTL),
+	}
+
+	for _, c := range revoked {
+		serial, ok := new(big.Int).SetString(c.Serial, 16)
+		if !ok {
+			continue
+		}
+		tmpl.RevokedCertificates = append(tmpl.RevokedCertificates, pkix.RevokedCertificate{
+			SerialNumber:   serial,
+			RevocationTime: c.RevokedAt,
+		})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &tmpl, p.ca, p.caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: crlType, Bytes: der}), nil
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/certs"
+	"github.com/vietquy/alpha/errors"
+)
+
+const errInvalid = "invalid_text_representation"
+
+var (
+	errSaveDB     = errors.New("Save cert to DB failed")
+	errRetrieveDB = errors.New("Retrieving cert from DB failed")
+	errRevokeDB   = errors.New("Revoke cert in DB failed")
+)
+
+var _ certs.CertRepository = (*certRepository)(nil)
+
+type certRepository struct {
+	db *sqlx.DB
+}
+
+// NewCertRepository instantiates a PostgreSQL implementation of cert
+// repository.
+func NewCertRepository(db *sqlx.DB) certs.CertRepository {
+	return &certRepository{
+		db: db,
+	}
+}
+
+func (cr certRepository) Save(ctx context.Context, cert certs.Cert) error {
+	q := `INSERT INTO certs (serial, thing_id, owner, expire) VALUES (:serial, :thing_id, :owner, :expire)`
+
+	if _, err := cr.db.NamedExecContext(ctx, q, toDBCert(cert)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(certs.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	return nil
+}
+
+func (cr certRepository) RetrieveBySerial(ctx context.Context, owner, serial string) (certs.Cert, error) {
+	q := `SELECT serial, thing_id, owner, expire, revoked_at FROM certs WHERE serial = $1 AND owner = $2`
+
+	dbc := dbCert{}
+	if err := cr.db.QueryRowxContext(ctx, q, serial, owner).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return certs.Cert{}, errors.Wrap(certs.ErrNotFound, err)
+		}
+		return certs.Cert{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toCert(dbc), nil
+}
+
+func (cr certRepository) RetrieveAll(ctx context.Context, owner, thingID string, offset, limit uint64) (certs.CertsPage, error) {
+	filter := ""
+	params := map[string]interface{}{
+		"owner":    owner,
+		"thing_id": thingID,
+		"limit":    limit,
+		"offset":   offset,
+	}
+	if thingID != "" {
+		filter = " AND thing_id = :thing_id"
+	}
+
+	q := `SELECT serial, thing_id, owner, expire, revoked_at FROM certs
+	      WHERE owner = :owner` + filter + ` ORDER BY expire DESC, serial LIMIT :limit OFFSET :offset`
+
+	page := certs.CertsPage{
+		Offset: offset,
+		Limit:  limit,
+		Certs:  []certs.Cert{},
+	}
+
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		// Certs can't be issued for the thing having the invalid ID.
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return page, nil
+		}
+		return certs.CertsPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		dbc := dbCert{}
+		if err := rows.StructScan(&dbc); err != nil {
+			return certs.CertsPage{}, errors.Wrap(errRetrieveDB, err)
+		}
+		page.Certs = append(page.Certs, toCert(dbc))
+	}
+
+	cq := `SELECT COUNT(*) FROM certs WHERE owner = :owner` + filter
+	total, err := total(ctx, cr.db, cq, params)
+	if err != nil {
+		return certs.CertsPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+	page.Total = total
+
+	return page, nil
+}
+
+func (cr certRepository) Revoke(ctx context.Context, owner, serial string, at time.Time) error {
+	q := `UPDATE certs SET revoked_at = $1 WHERE serial = $2 AND owner = $3`
+
+	res, err := cr.db.ExecContext(ctx, q, at, serial, owner)
+	if err != nil {
+		return errors.Wrap(errRevokeDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errRevokeDB, err)
+	}
+
+	if cnt == 0 {
+		return certs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr certRepository) RetrieveRevoked(ctx context.Context) ([]certs.Cert, error) {
+	q := `SELECT serial, thing_id, owner, expire, revoked_at FROM certs
+	      WHERE revoked_at IS NOT NULL AND expire > NOW() ORDER BY revoked_at`
+
+	rows, err := cr.db.QueryxContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	revoked := []certs.Cert{}
+	for rows.Next() {
+		dbc := dbCert{}
+		if err := rows.StructScan(&dbc); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+		revoked = append(revoked, toCert(dbc))
+	}
+
+	return revoked, nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+type dbCert struct {
+	Serial    string       `db:"serial"`
+	ThingID   string       `db:"thing_id"`
+	Owner     string       `db:"owner"`
+	Expire    time.Time    `db:"expire"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+}
+
+func toDBCert(cert certs.Cert) dbCert {
+	return dbCert{
+		Serial:    cert.Serial,
+		ThingID:   cert.ThingID,
+		Owner:     cert.Owner,
+		Expire:    cert.Expire,
+		RevokedAt: sql.NullTime{Time: cert.RevokedAt, Valid: cert.Revoked()},
+	}
+}
+
+func toCert(dbc dbCert) certs.Cert {
+	return certs.Cert{
+		Serial:    dbc.Serial,
+		ThingID:   dbc.ThingID,
+		Owner:     dbc.Owner,
+		Expire:    dbc.Expire,
+		RevokedAt: dbc.RevokedAt.Time,
+	}
+}
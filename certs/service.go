@@ -0,0 +1,168 @@
+package certs
+
+import (
+	"context"
+	"time"
+
+	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/authn"
+	"github.com/vietquy/alpha/errors"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrThings indicates failed communication with the things service.
+	ErrThings = errors.New("things service request failed")
+
+	// ErrIssueCert indicates error in issuing the certificate.
+	ErrIssueCert = errors.New("issue certificate failed")
+
+	// ErrCRL indicates error in creating the certificate revocation list.
+	ErrCRL = errors.New("create certificate revocation list failed")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// IssueCert issues the client certificate for the thing having the
+	// provided ID, that is accessible by the user identified by the provided
+	// key. The certificate is valid for the provided duration, or for the
+	// default duration if zero.
+	IssueCert(ctx context.Context, token, thingID string, ttl time.Duration) (Cert, error)
+
+	// ViewCert retrieves the cert having the provided serial number, that
+	// belongs to the user identified by the provided key.
+	ViewCert(ctx context.Context, token, serial string) (Cert, error)
+
+	// ListCerts retrieves the subset of certs that belong to the user
+	// identified by the provided key. If the thing ID is provided, only the
+	// certs of the thing are retrieved.
+	ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (CertsPage, error)
+
+	// RevokeCert revokes the cert having the provided serial number, that
+	// belongs to the user identified by the provided key.
+	RevokeCert(ctx context.Context, token, serial string) (Cert, error)
+
+	// CRL returns the PEM encoded certificate revocation list, containing
+	// all revoked certs that are not expired yet.
+	CRL(ctx context.Context) ([]byte, error)
+}
+
+var _ Service = (*certsService)(nil)
+
+type certsService struct {
+	auth   alpha.AuthNServiceClient
+	certs  CertRepository
+	things ThingsService
+	pki    PKI
+}
+
+// New instantiates the certs service implementation.
+func New(auth alpha.AuthNServiceClient, certs CertRepository, things ThingsService, pki PKI) Service {
+	return &certsService{
+		auth:   auth,
+		certs:  certs,
+		things: things,
+		pki:    pki,
+	}
+}
+
+func (cs certsService) IssueCert(ctx context.Context, token, thingID string, ttl time.Duration) (Cert, error) {
+	owner, err := cs.identify(ctx, token)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	if err := cs.things.ViewThing(ctx, token, thingID); err != nil {
+		return Cert{}, err
+	}
+
+	cert, err := cs.pki.Issue(thingID, ttl)
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrIssueCert, err)
+	}
+
+	cert.ThingID = thingID
+	cert.Owner = owner
+	if err := cs.certs.Save(ctx, cert); err != nil {
+		return Cert{}, err
+	}
+
+	return cert, nil
+}
+
+func (cs certsService) ViewCert(ctx context.Context, token, serial string) (Cert, error) {
+	owner, err := cs.identify(ctx, token)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	return cs.certs.RetrieveBySerial(ctx, owner, serial)
+}
+
+func (cs certsService) ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (CertsPage, error) {
+	owner, err := cs.identify(ctx, token)
+	if err != nil {
+		return CertsPage{}, err
+	}
+
+	return cs.certs.RetrieveAll(ctx, owner, thingID, offset, limit)
+}
+
+func (cs certsService) RevokeCert(ctx context.Context, token, serial string) (Cert, error) {
+	owner, err := cs.identify(ctx, token)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	cert, err := cs.certs.RetrieveBySerial(ctx, owner, serial)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	// Revoking the revoked cert keeps its original revocation time.
+	if cert.Revoked() {
+		return cert, nil
+	}
+
+	cert.RevokedAt = time.Now().UTC()
+	if err := cs.certs.Revoke(ctx, owner, serial, cert.RevokedAt); err != nil {
+		return Cert{}, err
+	}
+
+	return cert, nil
+}
+
+func (cs certsService) CRL(ctx context.Context) ([]byte, error) {
+	revoked, err := cs.certs.RetrieveRevoked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	crl, err := cs.pki.CRL(revoked)
+	if err != nil {
+		return nil, errors.Wrap(ErrCRL, err)
+	}
+
+	return crl, nil
+}
+
+// identify returns the owner of the certs of the user identified by the
+// provided token, see authn.Owner.
+func (cs certsService) identify(ctx context.Context, token string) (string, error) {
+	res, err := cs.auth.Identify(ctx, &alpha.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return authn.Owner(res.GetValue(), res.GetOrganization()), nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vietquy/alpha"
+	authapi "github.com/vietquy/alpha/authn/api/grpc"
+	"github.com/vietquy/alpha/certs"
+	"github.com/vietquy/alpha/certs/api"
+	"github.com/vietquy/alpha/certs/pki"
+	"github.com/vietquy/alpha/certs/postgres"
+	"github.com/vietquy/alpha/logger"
+	thingsclient "github.com/vietquy/alpha/things/client"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel      = "error"
+	defDBHost        = "localhost"
+	defDBPort        = "5432"
+	defDBUser        = "alpha"
+	defDBPass        = "alpha"
+	defDB            = "certs"
+	defHTTPPort      = "8204"
+	defThingsURL     = "http://localhost:8182"
+	defThingsTimeout = "5" // in seconds
+	defAuthnURL      = "localhost:8181"
+	defAuthnTimeout  = "1" // in seconds
+	defCACert        = "ca.crt"
+	defCAKey         = "ca.key"
+	defCertTTL       = "2160h"
+	defRSABits       = "2048"
+
+	envLogLevel      = "AP_CERTS_LOG_LEVEL"
+	envDBHost        = "AP_CERTS_DB_HOST"
+	envDBPort        = "AP_CERTS_DB_PORT"
+	envDBUser        = "AP_CERTS_DB_USER"
+	envDBPass        = "AP_CERTS_DB_PASS"
+	envDB            = "AP_CERTS_DB"
+	envHTTPPort      = "AP_CERTS_HTTP_PORT"
+	envThingsURL     = "AP_CERTS_THINGS_URL"
+	envThingsTimeout = "AP_CERTS_THINGS_TIMEOUT"
+	envAuthnURL      = "AP_AUTHN_GRPC_URL"
+	envAuthnTimeout  = "AP_AUTHN_GRPC_TIMEOUT"
+	envCACert        = "AP_CERTS_SIGN_CA_PATH"
+	envCAKey         = "AP_CERTS_SIGN_CA_KEY_PATH"
+	envCertTTL       = "AP_CERTS_SIGN_TTL"
+	envRSABits       = "AP_CERTS_SIGN_RSA_BITS"
+)
+
+type config struct {
+	logLevel      string
+	dbConfig      postgres.Config
+	httpPort      string
+	thingsURL     string
+	thingsTimeout time.Duration
+	authnURL      string
+	authnTimeout  time.Duration
+	caCert        string
+	caKey         string
+	certTTL       time.Duration
+	rsaBits       int
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	auth, close := connectToAuthn(cfg, logger)
+	if close != nil {
+		defer close()
+	}
+
+	svc := newService(db, auth, cfg, logger)
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Certs service terminated: %s", err))
+}
+
+func loadConfig() config {
+	authnTimeout, err := strconv.ParseInt(alpha.Env(envAuthnTimeout, defAuthnTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthnTimeout, err.Error())
+	}
+
+	thingsTimeout, err := strconv.ParseInt(alpha.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	certTTL, err := time.ParseDuration(alpha.Env(envCertTTL, defCertTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCertTTL, err.Error())
+	}
+
+	rsaBits, err := strconv.Atoi(alpha.Env(envRSABits, defRSABits))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRSABits, err.Error())
+	}
+
+	dbConfig := postgres.Config{
+		Host: alpha.Env(envDBHost, defDBHost),
+		Port: alpha.Env(envDBPort, defDBPort),
+		User: alpha.Env(envDBUser, defDBUser),
+		Pass: alpha.Env(envDBPass, defDBPass),
+		Name: alpha.Env(envDB, defDB),
+	}
+
+	return config{
+		logLevel:      alpha.Env(envLogLevel, defLogLevel),
+		dbConfig:      dbConfig,
+		httpPort:      alpha.Env(envHTTPPort, defHTTPPort),
+		thingsURL:     alpha.Env(envThingsURL, defThingsURL),
+		thingsTimeout: time.Duration(thingsTimeout) * time.Second,
+		authnURL:      alpha.Env(envAuthnURL, defAuthnURL),
+		authnTimeout:  time.Duration(authnTimeout) * time.Second,
+		caCert:        alpha.Env(envCACert, defCACert),
+		caKey:         alpha.Env(envCAKey, defCAKey),
+		certTTL:       certTTL,
+		rsaBits:       rsaBits,
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+
+	return db
+}
+
+func connectToAuthn(cfg config, logger logger.Logger) (alpha.AuthNServiceClient, func() error) {
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithInsecure())
+	logger.Info("gRPC communication is not encrypted")
+
+	conn, err := grpc.Dial(cfg.authnURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to authn service: %s", err))
+		os.Exit(1)
+	}
+
+	return authapi.NewClient(conn, cfg.authnTimeout), conn.Close
+}
+
+func newService(db *sqlx.DB, auth alpha.AuthNServiceClient, c config, logger logger.Logger) certs.Service {
+	certRepo := postgres.NewCertRepository(db)
+	ths := thingsclient.New(c.thingsURL, c.thingsTimeout, thingsclient.Errors{
+		Unauthorized: certs.ErrUnauthorizedAccess,
+		NotFound:     certs.ErrNotFound,
+		Failed:       certs.ErrThings,
+	})
+
+	p, err := pki.Load(c.caCert, c.caKey, c.certTTL, c.rsaBits)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load CA: %s", err))
+		os.Exit(1)
+	}
+
+	svc := certs.New(auth, certRepo, ths, p)
+	svc = api.LoggingMiddleware(svc, logger)
+
+	return svc
+}
+
+func startHTTPServer(svc certs.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Certs service started using http, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
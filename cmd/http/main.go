@@ -11,10 +11,11 @@ import (
 	"time"
 
 	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/certs/mtls"
 	adapter "github.com/vietquy/alpha/http"
 	"github.com/vietquy/alpha/http/api"
 	"github.com/vietquy/alpha/logger"
-	"github.com/vietquy/alpha/messaging/nats"
+	"github.com/vietquy/alpha/messaging/broker"
 	thingsapi "github.com/vietquy/alpha/things/api/grpc"
 	"google.golang.org/grpc"
 )
@@ -22,23 +23,32 @@ import (
 const (
 	defLogLevel          = "error"
 	defPort              = "8180"
-	defNatsURL           = "nats://localhost:4222"
 	defThingsAuthURL     = "localhost:8181"
 	defThingsAuthTimeout = "1" // in seconds
+	defServerCert        = ""
+	defServerKey         = ""
+	defClientCACerts     = ""
+	defClientCRLURL      = ""
+	defClientCRLRefresh  = "60" // in seconds
 
 	envLogLevel          = "AP_HTTP_ADAPTER_LOG_LEVEL"
 	envPort              = "AP_HTTP_ADAPTER_PORT"
-	envNatsURL           = "AP_NATS_URL"
 	envThingsAuthURL     = "AP_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "AP_THINGS_AUTH_GRPC_TIMEOUT"
+	envServerCert        = "AP_HTTP_ADAPTER_SERVER_CERT"
+	envServerKey         = "AP_HTTP_ADAPTER_SERVER_KEY"
+	envClientCACerts     = "AP_HTTP_ADAPTER_CLIENT_CA_CERTS"
+	envClientCRLURL      = "AP_HTTP_ADAPTER_CLIENT_CRL_URL"
+	envClientCRLRefresh  = "AP_HTTP_ADAPTER_CLIENT_CRL_REFRESH"
 )
 
 type config struct {
-	natsURL           string
+	broker            broker.Config
 	logLevel          string
 	port              string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	tlsConfig         mtls.Config
 }
 
 func main() {
@@ -53,9 +63,9 @@ func main() {
 	defer conn.Close()
 
 
-	pub, err := nats.NewPublisher(cfg.natsURL)
+	pub, err := broker.NewPublisher(cfg.broker, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer pub.Close()
@@ -67,11 +77,7 @@ func main() {
 
 	errs := make(chan error, 2)
 
-	go func() {
-		p := fmt.Sprintf(":%s", cfg.port)
-		logger.Info(fmt.Sprintf("HTTP adapter service started on port %s", cfg.port))
-		errs <- http.ListenAndServe(p, api.MakeHandler(svc))
-	}()
+	go startHTTPServer(svc, cfg, logger, errs)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -89,12 +95,31 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	crlRefresh, err := strconv.ParseInt(alpha.Env(envClientCRLRefresh, defClientCRLRefresh), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envClientCRLRefresh, err.Error())
+	}
+
+	brokerConfig, err := broker.LoadConfig()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	tlsConfig := mtls.Config{
+		CertFile:   alpha.Env(envServerCert, defServerCert),
+		KeyFile:    alpha.Env(envServerKey, defServerKey),
+		CAFile:     alpha.Env(envClientCACerts, defClientCACerts),
+		CRLURL:     alpha.Env(envClientCRLURL, defClientCRLURL),
+		CRLRefresh: time.Duration(crlRefresh) * time.Second,
+	}
+
 	return config{
-		natsURL:           alpha.Env(envNatsURL, defNatsURL),
+		broker:            brokerConfig,
 		logLevel:          alpha.Env(envLogLevel, defLogLevel),
 		port:              alpha.Env(envPort, defPort),
 		thingsAuthURL:     alpha.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: time.Duration(timeout) * time.Second,
+		tlsConfig:         tlsConfig,
 	}
 }
 
@@ -112,3 +137,26 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	}
 	return conn
 }
+
+func startHTTPServer(svc adapter.Service, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.port)
+	if cfg.tlsConfig.CertFile == "" {
+		logger.Info(fmt.Sprintf("HTTP adapter service started on port %s", cfg.port))
+		errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+		return
+	}
+
+	tlsCfg, err := mtls.ServerConfig(cfg.tlsConfig, logger)
+	if err != nil {
+		errs <- err
+		return
+	}
+
+	server := &http.Server{
+		Addr:      p,
+		Handler:   api.MakeHandler(svc),
+		TLSConfig: tlsCfg,
+	}
+	logger.Info(fmt.Sprintf("HTTP adapter service started using https with client certificates on port %s", cfg.port))
+	errs <- server.ListenAndServeTLS("", "")
+}
@@ -11,15 +11,16 @@ import (
 	"time"
 
 	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/certs/mtls"
 	mflog "github.com/vietquy/alpha/logger"
 	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/messaging/broker"
 	mqttpub "github.com/vietquy/alpha/messaging/mqtt"
-	"github.com/vietquy/alpha/messaging/nats"
 	"github.com/vietquy/alpha/mqtt"
-	thingsapi "github.com/vietquy/alpha/things/api/grpc"
 	mp "github.com/vietquy/alpha/mqtt/proxy/mqtt"
 	"github.com/vietquy/alpha/mqtt/proxy/session"
 	ws "github.com/vietquy/alpha/mqtt/proxy/websocket"
+	thingsapi "github.com/vietquy/alpha/things/api/grpc"
 	"google.golang.org/grpc"
 )
 
@@ -28,17 +29,23 @@ const (
 	defLogLevel = "error"
 	envLogLevel = "AP_MQTT_ADAPTER_LOG_LEVEL"
 	// MQTT
-	defMQTTHost             = "0.0.0.0"
-	defMQTTPort             = "1883"
-	defMQTTTargetHost       = "0.0.0.0"
-	defMQTTTargetPort       = "1883"
-	defMQTTForwarderTimeout = "30" // in seconds
-
-	envMQTTHost             = "AP_MQTT_ADAPTER_MQTT_HOST"
-	envMQTTPort             = "AP_MQTT_ADAPTER_MQTT_PORT"
-	envMQTTTargetHost       = "AP_MQTT_ADAPTER_MQTT_TARGET_HOST"
-	envMQTTTargetPort       = "AP_MQTT_ADAPTER_MQTT_TARGET_PORT"
-	envMQTTForwarderTimeout = "AP_MQTT_ADAPTER_FORWARDER_TIMEOUT"
+	defMQTTHost                 = "0.0.0.0"
+	defMQTTPort                 = "1883"
+	defMQTTTargetHost           = "0.0.0.0"
+	defMQTTTargetPort           = "1883"
+	defMQTTForwarderTimeout     = "30" // in seconds
+	defForwarderRetryAttempts   = "3"
+	defForwarderRetryBackoff    = "100ms"
+	defForwarderRetryMaxBackoff = "1s"
+
+	envMQTTHost                 = "AP_MQTT_ADAPTER_MQTT_HOST"
+	envMQTTPort                 = "AP_MQTT_ADAPTER_MQTT_PORT"
+	envMQTTTargetHost           = "AP_MQTT_ADAPTER_MQTT_TARGET_HOST"
+	envMQTTTargetPort           = "AP_MQTT_ADAPTER_MQTT_TARGET_PORT"
+	envMQTTForwarderTimeout     = "AP_MQTT_ADAPTER_FORWARDER_TIMEOUT"
+	envForwarderRetryAttempts   = "AP_MQTT_ADAPTER_FORWARDER_RETRY_ATTEMPTS"
+	envForwarderRetryBackoff    = "AP_MQTT_ADAPTER_FORWARDER_RETRY_BACKOFF"
+	envForwarderRetryMaxBackoff = "AP_MQTT_ADAPTER_FORWARDER_RETRY_MAX_BACKOFF"
 	// HTTP
 	defHTTPHost       = "0.0.0.0"
 	defHTTPPort       = "8080"
@@ -58,8 +65,17 @@ const (
 	envThingsAuthURL     = "AP_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "AP_THINGS_AUTH_GRPC_TIMMEOUT"
 	// Nats
-	defNatsURL = "nats://localhost:4222"
-	envNatsURL = "AP_NATS_URL"
+	// TLS
+	defServerCert       = ""
+	defServerKey        = ""
+	defClientCACerts    = ""
+	defClientCRLURL     = ""
+	defClientCRLRefresh = "60" // in seconds
+	envServerCert       = "AP_MQTT_ADAPTER_SERVER_CERT"
+	envServerKey        = "AP_MQTT_ADAPTER_SERVER_KEY"
+	envClientCACerts    = "AP_MQTT_ADAPTER_CLIENT_CA_CERTS"
+	envClientCRLURL     = "AP_MQTT_ADAPTER_CLIENT_CRL_URL"
+	envClientCRLRefresh = "AP_MQTT_ADAPTER_CLIENT_CRL_REFRESH"
 )
 
 type config struct {
@@ -68,6 +84,7 @@ type config struct {
 	mqttTargetHost       string
 	mqttTargetPort       string
 	mqttForwarderTimeout time.Duration
+	forwarderRetry       messaging.RetryPolicy
 	httpHost             string
 	httpPort             string
 	httpScheme           string
@@ -78,7 +95,8 @@ type config struct {
 	thingsURL            string
 	thingsAuthURL        string
 	thingsAuthTimeout    time.Duration
-	natsURL              string
+	broker               broker.Config
+	tlsConfig            mtls.Config
 }
 
 func main() {
@@ -94,9 +112,9 @@ func main() {
 
 	cc := thingsapi.NewClient(conn, cfg.thingsAuthTimeout)
 
-	nps, err := nats.NewPubSub(cfg.natsURL, "mqtt", logger)
+	nps, err := broker.NewPubSub(cfg.broker, "mqtt", "mqtt", logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer nps.Close()
@@ -105,15 +123,21 @@ func main() {
 		logger.Error(fmt.Sprintf("Failed to create MQTT publisher: %s", err))
 		os.Exit(1)
 	}
-	fwd := mqtt.NewForwarder(nats.SubjectAllProjects, logger)
-	if err := fwd.Forward(nps, mp); err != nil {
-		logger.Error(fmt.Sprintf("Failed to forward NATS messages: %s", err))
+	dlp, err := broker.NewDeadLetterPublisher(cfg.broker, "mqtt")
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
+		os.Exit(1)
+	}
+	defer dlp.Close()
+	fwd := mqtt.NewForwarder(messaging.SubjectAllProjects, logger)
+	if err := fwd.Forward(messaging.NewRetrySubscriber(nps, cfg.forwarderRetry, dlp), mp); err != nil {
+		logger.Error(fmt.Sprintf("Failed to forward messages: %s", err))
 		os.Exit(1)
 	}
 
-	np, err := nats.NewPublisher(cfg.natsURL)
+	np, err := broker.NewPublisher(cfg.broker, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer np.Close()
@@ -150,12 +174,52 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	crlRefresh, err := strconv.ParseInt(alpha.Env(envClientCRLRefresh, defClientCRLRefresh), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envClientCRLRefresh, err.Error())
+	}
+
+	tlsConfig := mtls.Config{
+		CertFile:   alpha.Env(envServerCert, defServerCert),
+		KeyFile:    alpha.Env(envServerKey, defServerKey),
+		CAFile:     alpha.Env(envClientCACerts, defClientCACerts),
+		CRLURL:     alpha.Env(envClientCRLURL, defClientCRLURL),
+		CRLRefresh: time.Duration(crlRefresh) * time.Second,
+	}
+
+	brokerConfig, err := broker.LoadConfig()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	retryAttempts, err := strconv.Atoi(alpha.Env(envForwarderRetryAttempts, defForwarderRetryAttempts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envForwarderRetryAttempts, err.Error())
+	}
+
+	retryBackoff, err := time.ParseDuration(alpha.Env(envForwarderRetryBackoff, defForwarderRetryBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envForwarderRetryBackoff, err.Error())
+	}
+
+	retryMaxBackoff, err := time.ParseDuration(alpha.Env(envForwarderRetryMaxBackoff, defForwarderRetryMaxBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envForwarderRetryMaxBackoff, err.Error())
+	}
+
+	retry := messaging.RetryPolicy{
+		Attempts:   retryAttempts,
+		Backoff:    retryBackoff,
+		MaxBackoff: retryMaxBackoff,
+	}
+
 	return config{
 		mqttHost:             alpha.Env(envMQTTHost, defMQTTHost),
 		mqttPort:             alpha.Env(envMQTTPort, defMQTTPort),
 		mqttTargetHost:       alpha.Env(envMQTTTargetHost, defMQTTTargetHost),
 		mqttTargetPort:       alpha.Env(envMQTTTargetPort, defMQTTTargetPort),
 		mqttForwarderTimeout: time.Duration(mqttTimeout) * time.Second,
+		forwarderRetry:       retry,
 		httpHost:             alpha.Env(envHTTPHost, defHTTPHost),
 		httpPort:             alpha.Env(envHTTPPort, defHTTPPort),
 		httpScheme:           alpha.Env(envHTTPScheme, defHTTPScheme),
@@ -165,12 +229,12 @@ func loadConfig() config {
 		thingsAuthURL:        alpha.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout:    time.Duration(authTimeout) * time.Second,
 		thingsURL:            alpha.Env(envThingsAuthURL, defThingsAuthURL),
-		natsURL:              alpha.Env(envNatsURL, defNatsURL),
+		broker:               brokerConfig,
 		logLevel:             alpha.Env(envLogLevel, defLogLevel),
+		tlsConfig:            tlsConfig,
 	}
 }
 
-
 func connectToThings(cfg config, logger mflog.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 
@@ -190,7 +254,17 @@ func proxyMQTT(cfg config, logger mflog.Logger, handler session.Handler, errs ch
 	target := fmt.Sprintf("%s:%s", cfg.mqttTargetHost, cfg.mqttTargetPort)
 	mp := mp.New(address, target, handler, logger)
 
-	errs <- mp.Proxy()
+	if cfg.tlsConfig.CertFile == "" {
+		errs <- mp.Proxy()
+		return
+	}
+
+	tlsCfg, err := mtls.ServerConfig(cfg.tlsConfig, logger)
+	if err != nil {
+		errs <- err
+		return
+	}
+	errs <- mp.ProxyTLS(tlsCfg)
 }
 func proxyWS(cfg config, logger mflog.Logger, handler session.Handler, errs chan error) {
 	target := fmt.Sprintf("%s:%s", cfg.httpTargetHost, cfg.httpTargetPort)
@@ -198,5 +272,19 @@ func proxyWS(cfg config, logger mflog.Logger, handler session.Handler, errs chan
 	http.Handle("/mqtt", wp.Handler())
 
 	p := fmt.Sprintf(":%s", cfg.httpPort)
-	errs <- http.ListenAndServe(p, nil)
+	if cfg.tlsConfig.CertFile == "" {
+		errs <- http.ListenAndServe(p, nil)
+		return
+	}
+
+	tlsCfg, err := mtls.ServerConfig(cfg.tlsConfig, logger)
+	if err != nil {
+		errs <- err
+		return
+	}
+	server := &http.Server{
+		Addr:      p,
+		TLSConfig: tlsCfg,
+	}
+	errs <- server.ListenAndServeTLS("", "")
 }
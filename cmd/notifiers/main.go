@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vietquy/alpha"
+	authapi "github.com/vietquy/alpha/authn/api/grpc"
+	"github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/messaging/broker"
+	"github.com/vietquy/alpha/notifiers"
+	"github.com/vietquy/alpha/notifiers/api"
+	"github.com/vietquy/alpha/notifiers/postgres"
+	"github.com/vietquy/alpha/notifiers/smtp"
+	thingsclient "github.com/vietquy/alpha/things/client"
+	"github.com/vietquy/alpha/things/uuid"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel      = "error"
+	defDBHost        = "localhost"
+	defDBPort        = "5432"
+	defDBUser        = "alpha"
+	defDBPass        = "alpha"
+	defDB            = "notifiers"
+	defHTTPPort      = "8212"
+	defThingsURL     = "http://localhost:8182"
+	defThingsTimeout = "5" // in seconds
+	defAuthnURL      = "localhost:8181"
+	defAuthnTimeout  = "1" // in seconds
+	defThrottle      = "5m"
+	defSMTPHost      = "localhost"
+	defSMTPPort      = "25"
+	defSMTPUsername  = ""
+	defSMTPPassword  = ""
+	defSMTPFrom      = "alpha@localhost"
+
+	envLogLevel      = "AP_NOTIFIERS_LOG_LEVEL"
+	envDBHost        = "AP_NOTIFIERS_DB_HOST"
+	envDBPort        = "AP_NOTIFIERS_DB_PORT"
+	envDBUser        = "AP_NOTIFIERS_DB_USER"
+	envDBPass        = "AP_NOTIFIERS_DB_PASS"
+	envDB            = "AP_NOTIFIERS_DB"
+	envHTTPPort      = "AP_NOTIFIERS_HTTP_PORT"
+	envThingsURL     = "AP_NOTIFIERS_THINGS_URL"
+	envThingsTimeout = "AP_NOTIFIERS_THINGS_TIMEOUT"
+	envAuthnURL      = "AP_AUTHN_GRPC_URL"
+	envAuthnTimeout  = "AP_AUTHN_GRPC_TIMEOUT"
+	envThrottle      = "AP_NOTIFIERS_THROTTLE"
+	envSMTPHost      = "AP_NOTIFIERS_SMTP_HOST"
+	envSMTPPort      = "AP_NOTIFIERS_SMTP_PORT"
+	envSMTPUsername  = "AP_NOTIFIERS_SMTP_USERNAME"
+	envSMTPPassword  = "AP_NOTIFIERS_SMTP_PASSWORD"
+	envSMTPFrom      = "AP_NOTIFIERS_SMTP_FROM"
+)
+
+type config struct {
+	logLevel      string
+	dbConfig      postgres.Config
+	httpPort      string
+	thingsURL     string
+	thingsTimeout time.Duration
+	authnURL      string
+	authnTimeout  time.Duration
+	broker        broker.Config
+	throttle      time.Duration
+	smtpConfig    smtp.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	auth, close := connectToAuthn(cfg, logger)
+	if close != nil {
+		defer close()
+	}
+
+	pubSub, err := broker.NewPubSub(cfg.broker, "notifiers", "notifiers", logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
+		os.Exit(1)
+	}
+	defer pubSub.Close()
+
+	svc := newService(db, auth, cfg, logger)
+	if err := messaging.SubscribeAllProjects(pubSub, svc.Consume); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to messages: %s", err))
+		os.Exit(1)
+	}
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Notifiers service terminated: %s", err))
+}
+
+func loadConfig() config {
+	authnTimeout, err := strconv.ParseInt(alpha.Env(envAuthnTimeout, defAuthnTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthnTimeout, err.Error())
+	}
+
+	thingsTimeout, err := strconv.ParseInt(alpha.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	throttle, err := time.ParseDuration(alpha.Env(envThrottle, defThrottle))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThrottle, err.Error())
+	}
+
+	smtpConfig := smtp.Config{
+		Host:     alpha.Env(envSMTPHost, defSMTPHost),
+		Port:     alpha.Env(envSMTPPort, defSMTPPort),
+		Username: alpha.Env(envSMTPUsername, defSMTPUsername),
+		Password: alpha.Env(envSMTPPassword, defSMTPPassword),
+		From:     alpha.Env(envSMTPFrom, defSMTPFrom),
+	}
+
+	dbConfig := postgres.Config{
+		Host: alpha.Env(envDBHost, defDBHost),
+		Port: alpha.Env(envDBPort, defDBPort),
+		User: alpha.Env(envDBUser, defDBUser),
+		Pass: alpha.Env(envDBPass, defDBPass),
+		Name: alpha.Env(envDB, defDB),
+	}
+
+	brokerConfig, err := broker.LoadConfig()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	return config{
+		logLevel:      alpha.Env(envLogLevel, defLogLevel),
+		dbConfig:      dbConfig,
+		httpPort:      alpha.Env(envHTTPPort, defHTTPPort),
+		thingsURL:     alpha.Env(envThingsURL, defThingsURL),
+		thingsTimeout: time.Duration(thingsTimeout) * time.Second,
+		authnURL:      alpha.Env(envAuthnURL, defAuthnURL),
+		authnTimeout:  time.Duration(authnTimeout) * time.Second,
+		broker:        brokerConfig,
+		throttle:      throttle,
+		smtpConfig:    smtpConfig,
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+
+	return db
+}
+
+func connectToAuthn(cfg config, logger logger.Logger) (alpha.AuthNServiceClient, func() error) {
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithInsecure())
+	logger.Info("gRPC communication is not encrypted")
+
+	conn, err := grpc.Dial(cfg.authnURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to authn service: %s", err))
+		os.Exit(1)
+	}
+
+	return authapi.NewClient(conn, cfg.authnTimeout), conn.Close
+}
+
+func newService(db *sqlx.DB, auth alpha.AuthNServiceClient, c config, logger logger.Logger) notifiers.Service {
+	subRepo := postgres.NewSubscriptionRepository(db)
+	ths := thingsclient.New(c.thingsURL, c.thingsTimeout, thingsclient.Errors{
+		Unauthorized: notifiers.ErrUnauthorizedAccess,
+		NotFound:     notifiers.ErrNotFound,
+		Failed:       notifiers.ErrThings,
+	})
+	idp := uuid.New()
+	notifier := smtp.New(c.smtpConfig)
+
+	svc := notifiers.New(auth, subRepo, ths, idp, notifier, c.throttle)
+	svc = api.LoggingMiddleware(svc, logger)
+
+	return svc
+}
+
+func startHTTPServer(svc notifiers.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Notifiers service started using http, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -16,16 +17,22 @@ import (
 	"github.com/vietquy/alpha/reader"
 	"github.com/vietquy/alpha/reader/api"
 	"github.com/vietquy/alpha/reader/influxdb"
+	"github.com/vietquy/alpha/reader/postgres"
 	thingsapi "github.com/vietquy/alpha/things/api/grpc"
 	"google.golang.org/grpc"
 )
 
 const (
+	influxDBType   = "influxdb"
+	postgresDBType = "postgres"
+
 	defLogLevel          = "error"
 	defPort              = "8180"
+	defDBType            = influxDBType
 	defDB                = "messages"
 	defDBHost            = "localhost"
-	defDBPort            = "8086"
+	defInfluxDBPort      = "8086"
+	defPostgresDBPort    = "5432"
 	defDBUser            = "alpha"
 	defDBPass            = "alpha"
 	defThingsAuthURL     = "localhost:8181"
@@ -33,6 +40,7 @@ const (
 
 	envLogLevel          = "AP_READER_LOG_LEVEL"
 	envPort              = "AP_READER_PORT"
+	envDBType            = "AP_READER_DB_TYPE"
 	envDB                = "AP_READER_DB"
 	envDBHost            = "AP_READER_DB_HOST"
 	envDBPort            = "AP_READER_DB_PORT"
@@ -45,6 +53,7 @@ const (
 type config struct {
 	logLevel          string
 	port              string
+	dbType            string
 	dbName            string
 	dbHost            string
 	dbPort            string
@@ -55,7 +64,7 @@ type config struct {
 }
 
 func main() {
-	cfg, clientCfg := loadConfigs()
+	cfg := loadConfigs()
 	logger, err := logger.New(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
@@ -65,14 +74,12 @@ func main() {
 
 	tc := thingsapi.NewClient(conn, cfg.thingsAuthTimeout)
 
-	client, err := influxdata.NewHTTPClient(clientCfg)
+	repo, db, err := newService(cfg, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to create InfluxDB client: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to %s database: %s", cfg.dbType, err))
 		os.Exit(1)
 	}
-	defer client.Close()
-
-	repo := newService(client, cfg.dbName, logger)
+	defer db.Close()
 
 	errs := make(chan error, 2)
 	go func() {
@@ -84,18 +91,29 @@ func main() {
 	go startHTTPServer(repo, tc, cfg, logger, errs)
 
 	err = <-errs
-	logger.Error(fmt.Sprintf("InfluxDB writer service terminated: %s", err))
+	logger.Error(fmt.Sprintf("Reader service terminated: %s", err))
 }
 
-func loadConfigs() (config, influxdata.HTTPConfig) {
+func loadConfigs() config {
 	timeout, err := strconv.ParseInt(alpha.Env(envThingsAuthTimeout, defThingsAuthTimeout), 10, 64)
 	if err != nil {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
-	cfg := config{
+	dbType := alpha.Env(envDBType, defDBType)
+	defDBPort := defInfluxDBPort
+	switch dbType {
+	case influxDBType:
+	case postgresDBType:
+		defDBPort = defPostgresDBPort
+	default:
+		log.Fatalf("Invalid %s value: %s", envDBType, dbType)
+	}
+
+	return config{
 		logLevel:          alpha.Env(envLogLevel, defLogLevel),
 		port:              alpha.Env(envPort, defPort),
+		dbType:            dbType,
 		dbName:            alpha.Env(envDB, defDB),
 		dbHost:            alpha.Env(envDBHost, defDBHost),
 		dbPort:            alpha.Env(envDBPort, defDBPort),
@@ -104,14 +122,6 @@ func loadConfigs() (config, influxdata.HTTPConfig) {
 		thingsAuthURL:     alpha.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: time.Duration(timeout) * time.Second,
 	}
-
-	clientCfg := influxdata.HTTPConfig{
-		Addr:     fmt.Sprintf("http://%s:%s", cfg.dbHost, cfg.dbPort),
-		Username: cfg.dbUser,
-		Password: cfg.dbPass,
-	}
-
-	return cfg, clientCfg
 }
 
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
@@ -128,15 +138,44 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func newService(client influxdata.Client, dbName string, logger logger.Logger) reader.MessageRepository {
-	repo := influxdb.New(client, dbName)
+// newService returns the message repository of the configured database type,
+// along with the database connection to be closed once the reader is stopped.
+func newService(cfg config, logger logger.Logger) (reader.MessageRepository, io.Closer, error) {
+	var repo reader.MessageRepository
+	var db io.Closer
+	switch cfg.dbType {
+	case postgresDBType:
+		dbCfg := postgres.Config{
+			Host: cfg.dbHost,
+			Port: cfg.dbPort,
+			User: cfg.dbUser,
+			Pass: cfg.dbPass,
+			Name: cfg.dbName,
+		}
+		conn, err := postgres.Connect(dbCfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		repo, db = postgres.New(conn), conn
+	default:
+		clientCfg := influxdata.HTTPConfig{
+			Addr:     fmt.Sprintf("http://%s:%s", cfg.dbHost, cfg.dbPort),
+			Username: cfg.dbUser,
+			Password: cfg.dbPass,
+		}
+		client, err := influxdata.NewHTTPClient(clientCfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		repo, db = influxdb.New(client, cfg.dbName), client
+	}
 	repo = api.LoggingMiddleware(repo, logger)
 
-	return repo
+	return repo, db, nil
 }
 
 func startHTTPServer(repo reader.MessageRepository, tc alpha.ThingsServiceClient, cfg config, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", cfg.port)
-	logger.Info(fmt.Sprintf("InfluxDB reader service started, exposed port %s", cfg.port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, "influxdb-reader"))
+	logger.Info(fmt.Sprintf("Reader service started, exposed port %s", cfg.port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, fmt.Sprintf("%s-reader", cfg.dbType)))
 }
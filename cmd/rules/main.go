@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vietquy/alpha"
+	authapi "github.com/vietquy/alpha/authn/api/grpc"
+	"github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/messaging/broker"
+	"github.com/vietquy/alpha/rules"
+	"github.com/vietquy/alpha/rules/api"
+	"github.com/vietquy/alpha/rules/postgres"
+	"github.com/vietquy/alpha/rules/webhook"
+	thingsapi "github.com/vietquy/alpha/things/api/grpc"
+	thingsclient "github.com/vietquy/alpha/things/client"
+	"github.com/vietquy/alpha/things/uuid"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel          = "error"
+	defDBHost            = "localhost"
+	defDBPort            = "5432"
+	defDBUser            = "alpha"
+	defDBPass            = "alpha"
+	defDB                = "rules"
+	defHTTPPort          = "8208"
+	defThingsURL         = "http://localhost:8182"
+	defThingsTimeout     = "5" // in seconds
+	defAuthnURL          = "localhost:8181"
+	defAuthnTimeout      = "1" // in seconds
+	defThingsAuthURL     = "localhost:8181"
+	defThingsAuthTimeout = "1" // in seconds
+	defWebhookTimeout    = "5" // in seconds
+
+	envLogLevel          = "AP_RULES_LOG_LEVEL"
+	envDBHost            = "AP_RULES_DB_HOST"
+	envDBPort            = "AP_RULES_DB_PORT"
+	envDBUser            = "AP_RULES_DB_USER"
+	envDBPass            = "AP_RULES_DB_PASS"
+	envDB                = "AP_RULES_DB"
+	envHTTPPort          = "AP_RULES_HTTP_PORT"
+	envThingsURL         = "AP_RULES_THINGS_URL"
+	envThingsTimeout     = "AP_RULES_THINGS_TIMEOUT"
+	envAuthnURL          = "AP_AUTHN_GRPC_URL"
+	envAuthnTimeout      = "AP_AUTHN_GRPC_TIMEOUT"
+	envThingsAuthURL     = "AP_THINGS_AUTH_GRPC_URL"
+	envThingsAuthTimeout = "AP_THINGS_AUTH_GRPC_TIMEOUT"
+	envWebhookTimeout    = "AP_RULES_WEBHOOK_TIMEOUT"
+)
+
+type config struct {
+	logLevel          string
+	dbConfig          postgres.Config
+	httpPort          string
+	thingsURL         string
+	thingsTimeout     time.Duration
+	authnURL          string
+	authnTimeout      time.Duration
+	thingsAuthURL     string
+	thingsAuthTimeout time.Duration
+	broker            broker.Config
+	webhookTimeout    time.Duration
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	auth, close := connectToAuthn(cfg, logger)
+	if close != nil {
+		defer close()
+	}
+
+	access, closeThings := connectToThings(cfg, logger)
+	defer closeThings()
+
+	pubSub, err := broker.NewPubSub(cfg.broker, "rules", "rules", logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
+		os.Exit(1)
+	}
+	defer pubSub.Close()
+
+	svc := newService(db, auth, access, pubSub, cfg, logger)
+	if err := messaging.SubscribeAllProjects(pubSub, svc.Evaluate); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to messages: %s", err))
+		os.Exit(1)
+	}
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Rules service terminated: %s", err))
+}
+
+func loadConfig() config {
+	authnTimeout, err := strconv.ParseInt(alpha.Env(envAuthnTimeout, defAuthnTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthnTimeout, err.Error())
+	}
+
+	thingsTimeout, err := strconv.ParseInt(alpha.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	thingsAuthTimeout, err := strconv.ParseInt(alpha.Env(envThingsAuthTimeout, defThingsAuthTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
+	}
+
+	webhookTimeout, err := strconv.ParseInt(alpha.Env(envWebhookTimeout, defWebhookTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhookTimeout, err.Error())
+	}
+
+	dbConfig := postgres.Config{
+		Host: alpha.Env(envDBHost, defDBHost),
+		Port: alpha.Env(envDBPort, defDBPort),
+		User: alpha.Env(envDBUser, defDBUser),
+		Pass: alpha.Env(envDBPass, defDBPass),
+		Name: alpha.Env(envDB, defDB),
+	}
+
+	brokerConfig, err := broker.LoadConfig()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	return config{
+		logLevel:          alpha.Env(envLogLevel, defLogLevel),
+		dbConfig:          dbConfig,
+		httpPort:          alpha.Env(envHTTPPort, defHTTPPort),
+		thingsURL:         alpha.Env(envThingsURL, defThingsURL),
+		thingsTimeout:     time.Duration(thingsTimeout) * time.Second,
+		authnURL:          alpha.Env(envAuthnURL, defAuthnURL),
+		authnTimeout:      time.Duration(authnTimeout) * time.Second,
+		thingsAuthURL:     alpha.Env(envThingsAuthURL, defThingsAuthURL),
+		thingsAuthTimeout: time.Duration(thingsAuthTimeout) * time.Second,
+		broker:            brokerConfig,
+		webhookTimeout:    time.Duration(webhookTimeout) * time.Second,
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+
+	return db
+}
+
+func connectToAuthn(cfg config, logger logger.Logger) (alpha.AuthNServiceClient, func() error) {
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithInsecure())
+	logger.Info("gRPC communication is not encrypted")
+
+	conn, err := grpc.Dial(cfg.authnURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to authn service: %s", err))
+		os.Exit(1)
+	}
+
+	return authapi.NewClient(conn, cfg.authnTimeout), conn.Close
+}
+
+func connectToThings(cfg config, logger logger.Logger) (alpha.ThingsServiceClient, func() error) {
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithInsecure())
+	logger.Info("gRPC communication is not encrypted")
+
+	conn, err := grpc.Dial(cfg.thingsAuthURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+
+	return thingsapi.NewClient(conn, cfg.thingsAuthTimeout), conn.Close
+}
+
+func newService(db *sqlx.DB, auth alpha.AuthNServiceClient, access alpha.ThingsServiceClient, pub messaging.Publisher, c config, logger logger.Logger) rules.Service {
+	ruleRepo := postgres.NewRuleRepository(db)
+	ths := thingsclient.New(c.thingsURL, c.thingsTimeout, thingsclient.Errors{
+		Unauthorized: rules.ErrUnauthorizedAccess,
+		NotFound:     rules.ErrNotFound,
+		Failed:       rules.ErrThings,
+	})
+	idp := uuid.New()
+	webhooks := webhook.NewClient(c.webhookTimeout)
+
+	svc := rules.New(auth, ruleRepo, ths, access, idp, pub, webhooks, logger)
+	svc = api.LoggingMiddleware(svc, logger)
+
+	return svc
+}
+
+func startHTTPServer(svc rules.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Rules service started using http, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
@@ -0,0 +1,695 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	influxdata "github.com/influxdata/influxdb/client/v2"
+	"github.com/jmoiron/sqlx"
+	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/authn"
+	authnapi "github.com/vietquy/alpha/authn/api"
+	authngrpc "github.com/vietquy/alpha/authn/api/grpc"
+	authnhttp "github.com/vietquy/alpha/authn/api/http"
+	"github.com/vietquy/alpha/authn/jwt"
+	authnpg "github.com/vietquy/alpha/authn/postgres"
+	authnuuid "github.com/vietquy/alpha/authn/uuid"
+	adapter "github.com/vietquy/alpha/http"
+	adapterapi "github.com/vietquy/alpha/http/api"
+	"github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/messaging/memory"
+	mqttpub "github.com/vietquy/alpha/messaging/mqtt"
+	"github.com/vietquy/alpha/mqtt"
+	mp "github.com/vietquy/alpha/mqtt/proxy/mqtt"
+	"github.com/vietquy/alpha/mqtt/proxy/session"
+	ws "github.com/vietquy/alpha/mqtt/proxy/websocket"
+	readerapi "github.com/vietquy/alpha/reader/api"
+	readerinflux "github.com/vietquy/alpha/reader/influxdb"
+	"github.com/vietquy/alpha/things"
+	thingsapi "github.com/vietquy/alpha/things/api"
+	thingsgrpc "github.com/vietquy/alpha/things/api/grpc"
+	thingshttp "github.com/vietquy/alpha/things/api/http"
+	"github.com/vietquy/alpha/things/cache"
+	thingspg "github.com/vietquy/alpha/things/postgres"
+	thingsuuid "github.com/vietquy/alpha/things/uuid"
+	"github.com/vietquy/alpha/transformer"
+	"github.com/vietquy/alpha/transformer/senml"
+	"github.com/vietquy/alpha/users"
+	usersapi "github.com/vietquy/alpha/users/api"
+	"github.com/vietquy/alpha/users/bcrypt"
+	userspg "github.com/vietquy/alpha/users/postgres"
+	"github.com/vietquy/alpha/writer"
+	writerapi "github.com/vietquy/alpha/writer/api"
+	writerinflux "github.com/vietquy/alpha/writer/influxdb"
+	"google.golang.org/grpc"
+)
+
+// The services read the same environment variables as when they are run
+// separately, while the default ports differ so that they don't collide.
+const (
+	// Standalone
+	defLogLevel       = "error"
+	defBufferSize     = "1024"
+	defOverflowPolicy = "block"
+	envLogLevel       = "AP_STANDALONE_LOG_LEVEL"
+	envBufferSize     = "AP_STANDALONE_BUFFER_SIZE"
+	envOverflowPolicy = "AP_STANDALONE_OVERFLOW_POLICY"
+	// Authn
+	defAuthnDBHost   = "localhost"
+	defAuthnDBPort   = "5432"
+	defAuthnDBUser   = "alpha"
+	defAuthnDBPass   = "alpha"
+	defAuthnDB       = "authn"
+	defAuthnHTTPPort = "8189"
+	defAuthnGRPCPort = "8181"
+	defAuthnSecret   = "authn"
+	defAuthnTimeout  = "1" // in seconds
+	envAuthnDBHost   = "AP_AUTHN_DB_HOST"
+	envAuthnDBPort   = "AP_AUTHN_DB_PORT"
+	envAuthnDBUser   = "AP_AUTHN_DB_USER"
+	envAuthnDBPass   = "AP_AUTHN_DB_PASS"
+	envAuthnDB       = "AP_AUTHN_DB"
+	envAuthnHTTPPort = "AP_AUTHN_HTTP_PORT"
+	envAuthnGRPCPort = "AP_AUTHN_GRPC_PORT"
+	envAuthnSecret   = "AP_AUTHN_SECRET"
+	envAuthnTimeout  = "AP_AUTHN_GRPC_TIMEOUT"
+	// Users
+	defUsersDBHost   = "localhost"
+	defUsersDBPort   = "5432"
+	defUsersDBUser   = "alpha"
+	defUsersDBPass   = "alpha"
+	defUsersDB       = "users"
+	defUsersHTTPPort = "8180"
+	envUsersDBHost   = "AP_USERS_DB_HOST"
+	envUsersDBPort   = "AP_USERS_DB_PORT"
+	envUsersDBUser   = "AP_USERS_DB_USER"
+	envUsersDBPass   = "AP_USERS_DB_PASS"
+	envUsersDB       = "AP_USERS_DB"
+	envUsersHTTPPort = "AP_USERS_HTTP_PORT"
+	// Things
+	defThingsDBHost    = "localhost"
+	defThingsDBPort    = "5432"
+	defThingsDBUser    = "alpha"
+	defThingsDBPass    = "alpha"
+	defThingsDB        = "things"
+	defThingsHTTPPort  = "8182"
+	defThingsGRPCPort  = "8183"
+	defThingsCacheSize = "100000"
+	defThingsCacheTTL  = "300"   // in seconds
+	defThingsKeyGrace  = "86400" // in seconds
+	defThingsTimeout   = "1"     // in seconds
+	envThingsDBHost    = "AP_THINGS_DB_HOST"
+	envThingsDBPort    = "AP_THINGS_DB_PORT"
+	envThingsDBUser    = "AP_THINGS_DB_USER"
+	envThingsDBPass    = "AP_THINGS_DB_PASS"
+	envThingsDB        = "AP_THINGS_DB"
+	envThingsHTTPPort  = "AP_THINGS_HTTP_PORT"
+	envThingsGRPCPort  = "AP_THINGS_GRPC_PORT"
+	envThingsCacheSize = "AP_THINGS_CACHE_SIZE"
+	envThingsCacheTTL  = "AP_THINGS_CACHE_TTL"
+	envThingsKeyGrace  = "AP_THINGS_KEY_GRACE_PERIOD"
+	envThingsTimeout   = "AP_THINGS_AUTH_GRPC_TIMEOUT"
+	// HTTP adapter
+	defHTTPPort = "8185"
+	envHTTPPort = "AP_HTTP_ADAPTER_PORT"
+	// MQTT adapter
+	defMQTTHost             = "0.0.0.0"
+	defMQTTPort             = "1883"
+	defMQTTTargetHost       = "localhost"
+	defMQTTTargetPort       = "1884"
+	defMQTTForwarderTimeout = "30" // in seconds
+	defWSHost               = "0.0.0.0"
+	defWSPort               = "8080"
+	defWSScheme             = "ws"
+	defWSTargetHost         = "localhost"
+	defWSTargetPort         = "8081"
+	defWSTargetPath         = "/mqtt"
+	envMQTTHost             = "AP_MQTT_ADAPTER_MQTT_HOST"
+	envMQTTPort             = "AP_MQTT_ADAPTER_MQTT_PORT"
+	envMQTTTargetHost       = "AP_MQTT_ADAPTER_MQTT_TARGET_HOST"
+	envMQTTTargetPort       = "AP_MQTT_ADAPTER_MQTT_TARGET_PORT"
+	envMQTTForwarderTimeout = "AP_MQTT_ADAPTER_FORWARDER_TIMEOUT"
+	envWSHost               = "AP_MQTT_ADAPTER_WS_HOST"
+	envWSPort               = "AP_MQTT_ADAPTER_WS_PORT"
+	envWSScheme             = "AP_MQTT_ADAPTER_WS_SCHEMA"
+	envWSTargetHost         = "AP_MQTT_ADAPTER_WS_TARGET_HOST"
+	envWSTargetPort         = "AP_MQTT_ADAPTER_WS_TARGET_PORT"
+	envWSTargetPath         = "AP_MQTT_ADAPTER_WS_TARGET_PATH"
+	// Writer
+	defWriterDB              = "messages"
+	defWriterDBHost          = "localhost"
+	defWriterDBPort          = "8086"
+	defWriterDBUser          = "alpha"
+	defWriterDBPass          = "alpha"
+	defWriterConfig          = ""
+	defWriterTransformer     = writer.JSONTransformer
+	defWriterContentType     = senml.JSON
+	defWriterRetryAttempts   = "3"
+	defWriterRetryBackoff    = "100ms"
+	defWriterRetryMaxBackoff = "1s"
+	defWriterBatchSize       = "5000"
+	defWriterBatchTimeout    = "5" // in seconds
+	defWriterBatchQueueSize  = "100"
+	defWriterBatchWorkers    = "4"
+	defWriterBatchAttempts   = "10"
+	defWriterBatchBackoff    = "1s"
+	defWriterBatchMaxBackoff = "30s"
+	envWriterDB              = "AP_WRITER_DB"
+	envWriterDBHost          = "AP_WRITER_DB_HOST"
+	envWriterDBPort          = "AP_WRITER_DB_PORT"
+	envWriterDBUser          = "AP_WRITER_DB_USER"
+	envWriterDBPass          = "AP_WRITER_DB_PASS"
+	envWriterConfig          = "AP_WRITER_CONFIG"
+	envWriterTransformer     = "AP_WRITER_TRANSFORMER"
+	envWriterContentType     = "AP_WRITER_CONTENT_TYPE"
+	envWriterRetryAttempts   = "AP_WRITER_RETRY_ATTEMPTS"
+	envWriterRetryBackoff    = "AP_WRITER_RETRY_BACKOFF"
+	envWriterRetryMaxBackoff = "AP_WRITER_RETRY_MAX_BACKOFF"
+	envWriterBatchSize       = "AP_WRITER_BATCH_SIZE"
+	envWriterBatchTimeout    = "AP_WRITER_BATCH_TIMEOUT"
+	envWriterBatchQueueSize  = "AP_WRITER_BATCH_QUEUE_SIZE"
+	envWriterBatchWorkers    = "AP_WRITER_BATCH_WORKERS"
+	envWriterBatchAttempts   = "AP_WRITER_BATCH_RETRY_ATTEMPTS"
+	envWriterBatchBackoff    = "AP_WRITER_BATCH_RETRY_BACKOFF"
+	envWriterBatchMaxBackoff = "AP_WRITER_BATCH_RETRY_MAX_BACKOFF"
+	// Reader
+	defReaderPort   = "8905"
+	defReaderDB     = "messages"
+	defReaderDBHost = "localhost"
+	defReaderDBPort = "8086"
+	defReaderDBUser = "alpha"
+	defReaderDBPass = "alpha"
+	envReaderPort   = "AP_READER_PORT"
+	envReaderDB     = "AP_READER_DB"
+	envReaderDBHost = "AP_READER_DB_HOST"
+	envReaderDBPort = "AP_READER_DB_PORT"
+	envReaderDBUser = "AP_READER_DB_USER"
+	envReaderDBPass = "AP_READER_DB_PASS"
+
+	readerName = "influxdb-reader"
+)
+
+type config struct {
+	logLevel       string
+	bufferSize     int
+	overflowPolicy memory.OverflowPolicy
+	authn          authnConfig
+	users          usersConfig
+	things         thingsConfig
+	httpPort       string
+	mqtt           mqttConfig
+	writer         writerConfig
+	readerPort     string
+	reader         influxdata.HTTPConfig
+	readerDB       string
+}
+
+type writerConfig struct {
+	dbConfig    influxdata.HTTPConfig
+	db          string
+	configPath  string
+	transformer transformer.Transformer
+	retry       messaging.RetryPolicy
+	batch       writer.BatchConfig
+}
+
+type authnConfig struct {
+	dbConfig authnpg.Config
+	httpPort string
+	grpcPort string
+	secret   string
+	timeout  time.Duration
+}
+
+type usersConfig struct {
+	dbConfig userspg.Config
+	httpPort string
+}
+
+type thingsConfig struct {
+	dbConfig  thingspg.Config
+	httpPort  string
+	grpcPort  string
+	cacheSize int
+	cacheTTL  time.Duration
+	keyGrace  time.Duration
+	timeout   time.Duration
+}
+
+type mqttConfig struct {
+	host             string
+	port             string
+	targetHost       string
+	targetPort       string
+	forwarderTimeout time.Duration
+	wsHost           string
+	wsPort           string
+	wsScheme         string
+	wsTargetHost     string
+	wsTargetPort     string
+	wsTargetPath     string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	broker := memory.NewBroker(cfg.bufferSize, cfg.overflowPolicy, logger)
+	defer broker.Close()
+
+	errs := make(chan error, 10)
+
+	authnDB, err := authnpg.Connect(cfg.authn.dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to authn postgres: %s", err))
+		os.Exit(1)
+	}
+	defer authnDB.Close()
+	authnSvc := newAuthnService(authnDB, cfg.authn.secret, logger)
+	go startHTTPServer("Authentication", authnhttp.MakeHandler(authnSvc), cfg.authn.httpPort, logger, errs)
+	go startAuthnGRPCServer(authnSvc, cfg.authn.grpcPort, logger, errs)
+
+	authnConn := connectToGRPC(fmt.Sprintf("localhost:%s", cfg.authn.grpcPort), logger)
+	defer authnConn.Close()
+	auth := authngrpc.NewClient(authnConn, cfg.authn.timeout)
+
+	usersDB, err := userspg.Connect(cfg.users.dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users postgres: %s", err))
+		os.Exit(1)
+	}
+	defer usersDB.Close()
+	usersSvc := newUsersService(usersDB, auth, logger)
+	go startHTTPServer("Users", usersapi.MakeHandler(usersSvc, logger), cfg.users.httpPort, logger, errs)
+
+	thingsDB, err := thingspg.Connect(cfg.things.dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things postgres: %s", err))
+		os.Exit(1)
+	}
+	defer thingsDB.Close()
+	thingsSvc := newThingsService(thingsDB, auth, cfg.things, logger)
+	go startHTTPServer("Things", thingshttp.MakeHandler(thingsSvc), cfg.things.httpPort, logger, errs)
+	go startThingsGRPCServer(thingsSvc, cfg.things.grpcPort, logger, errs)
+
+	thingsConn := connectToGRPC(fmt.Sprintf("localhost:%s", cfg.things.grpcPort), logger)
+	defer thingsConn.Close()
+	tc := thingsgrpc.NewClient(thingsConn, cfg.things.timeout)
+
+	pub := broker.PubSub("", 1)
+	adapterSvc := adapter.New(pub, tc)
+	adapterSvc = adapterapi.LoggingMiddleware(adapterSvc, logger)
+	go startHTTPServer("HTTP adapter", adapterapi.MakeHandler(adapterSvc), cfg.httpPort, logger, errs)
+
+	startMQTTAdapter(broker, tc, cfg.mqtt, logger, errs)
+
+	writerClient, err := influxdata.NewHTTPClient(cfg.writer.dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create InfluxDB client: %s", err))
+		os.Exit(1)
+	}
+	defer writerClient.Close()
+	repo := writerinflux.New(writerClient, cfg.writer.db)
+	repo = writerapi.LoggingMiddleware(repo, logger)
+	wcfg, err := writer.LoadConfig(cfg.writer.configPath)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load writer configuration: %s", err))
+		os.Exit(1)
+	}
+	// Messages of the batch are acknowledged once it's written, so that the
+	// subscriptions handle up to the batch size messages at the same time.
+	writerPubSub := broker.PubSub("writer", cfg.writer.batch.Size)
+	sub := messaging.NewRetrySubscriber(writerPubSub, cfg.writer.retry, nil)
+	bw, err := writer.Run(sub, repo, cfg.writer.transformer, wcfg, cfg.writer.batch, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to start InfluxDB writer: %s", err))
+		os.Exit(1)
+	}
+
+	readerClient, err := influxdata.NewHTTPClient(cfg.reader)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create InfluxDB client: %s", err))
+		os.Exit(1)
+	}
+	defer readerClient.Close()
+	msgs := readerinflux.New(readerClient, cfg.readerDB)
+	msgs = readerapi.LoggingMiddleware(msgs, logger)
+	go startHTTPServer("InfluxDB reader", readerapi.MakeHandler(msgs, tc, readerName), cfg.readerPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Standalone service terminated: %s", err))
+
+	// Writer subscription is closed first, so that no message is received
+	// while the buffered messages are written.
+	writerPubSub.Close()
+	bw.Close()
+}
+
+func loadConfig() config {
+	bufferSize, err := strconv.Atoi(alpha.Env(envBufferSize, defBufferSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBufferSize, err.Error())
+	}
+
+	overflowPolicy, err := memory.ParseOverflowPolicy(alpha.Env(envOverflowPolicy, defOverflowPolicy))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOverflowPolicy, err.Error())
+	}
+
+	cacheSize, err := strconv.Atoi(alpha.Env(envThingsCacheSize, defThingsCacheSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsCacheSize, err.Error())
+	}
+
+	cacheTTL, err := strconv.ParseInt(alpha.Env(envThingsCacheTTL, defThingsCacheTTL), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsCacheTTL, err.Error())
+	}
+
+	keyGrace, err := strconv.ParseInt(alpha.Env(envThingsKeyGrace, defThingsKeyGrace), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsKeyGrace, err.Error())
+	}
+
+	mqttTimeout, err := strconv.ParseInt(alpha.Env(envMQTTForwarderTimeout, defMQTTForwarderTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMQTTForwarderTimeout, err.Error())
+	}
+
+	authnTimeout, err := strconv.ParseInt(alpha.Env(envAuthnTimeout, defAuthnTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthnTimeout, err.Error())
+	}
+
+	thingsTimeout, err := strconv.ParseInt(alpha.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	authnCfg := authnConfig{
+		dbConfig: authnpg.Config{
+			Host: alpha.Env(envAuthnDBHost, defAuthnDBHost),
+			Port: alpha.Env(envAuthnDBPort, defAuthnDBPort),
+			User: alpha.Env(envAuthnDBUser, defAuthnDBUser),
+			Pass: alpha.Env(envAuthnDBPass, defAuthnDBPass),
+			Name: alpha.Env(envAuthnDB, defAuthnDB),
+		},
+		httpPort: alpha.Env(envAuthnHTTPPort, defAuthnHTTPPort),
+		grpcPort: alpha.Env(envAuthnGRPCPort, defAuthnGRPCPort),
+		secret:   alpha.Env(envAuthnSecret, defAuthnSecret),
+		timeout:  time.Duration(authnTimeout) * time.Second,
+	}
+
+	usersCfg := usersConfig{
+		dbConfig: userspg.Config{
+			Host: alpha.Env(envUsersDBHost, defUsersDBHost),
+			Port: alpha.Env(envUsersDBPort, defUsersDBPort),
+			User: alpha.Env(envUsersDBUser, defUsersDBUser),
+			Pass: alpha.Env(envUsersDBPass, defUsersDBPass),
+			Name: alpha.Env(envUsersDB, defUsersDB),
+		},
+		httpPort: alpha.Env(envUsersHTTPPort, defUsersHTTPPort),
+	}
+
+	thingsCfg := thingsConfig{
+		dbConfig: thingspg.Config{
+			Host: alpha.Env(envThingsDBHost, defThingsDBHost),
+			Port: alpha.Env(envThingsDBPort, defThingsDBPort),
+			User: alpha.Env(envThingsDBUser, defThingsDBUser),
+			Pass: alpha.Env(envThingsDBPass, defThingsDBPass),
+			Name: alpha.Env(envThingsDB, defThingsDB),
+		},
+		httpPort:  alpha.Env(envThingsHTTPPort, defThingsHTTPPort),
+		grpcPort:  alpha.Env(envThingsGRPCPort, defThingsGRPCPort),
+		cacheSize: cacheSize,
+		cacheTTL:  time.Duration(cacheTTL) * time.Second,
+		keyGrace:  time.Duration(keyGrace) * time.Second,
+		timeout:   time.Duration(thingsTimeout) * time.Second,
+	}
+
+	mqttCfg := mqttConfig{
+		host:             alpha.Env(envMQTTHost, defMQTTHost),
+		port:             alpha.Env(envMQTTPort, defMQTTPort),
+		targetHost:       alpha.Env(envMQTTTargetHost, defMQTTTargetHost),
+		targetPort:       alpha.Env(envMQTTTargetPort, defMQTTTargetPort),
+		forwarderTimeout: time.Duration(mqttTimeout) * time.Second,
+		wsHost:           alpha.Env(envWSHost, defWSHost),
+		wsPort:           alpha.Env(envWSPort, defWSPort),
+		wsScheme:         alpha.Env(envWSScheme, defWSScheme),
+		wsTargetHost:     alpha.Env(envWSTargetHost, defWSTargetHost),
+		wsTargetPort:     alpha.Env(envWSTargetPort, defWSTargetPort),
+		wsTargetPath:     alpha.Env(envWSTargetPath, defWSTargetPath),
+	}
+
+	readerCfg := influxdata.HTTPConfig{
+		Addr:     fmt.Sprintf("http://%s:%s", alpha.Env(envReaderDBHost, defReaderDBHost), alpha.Env(envReaderDBPort, defReaderDBPort)),
+		Username: alpha.Env(envReaderDBUser, defReaderDBUser),
+		Password: alpha.Env(envReaderDBPass, defReaderDBPass),
+	}
+
+	return config{
+		logLevel:       alpha.Env(envLogLevel, defLogLevel),
+		bufferSize:     bufferSize,
+		overflowPolicy: overflowPolicy,
+		authn:          authnCfg,
+		users:          usersCfg,
+		things:         thingsCfg,
+		httpPort:       alpha.Env(envHTTPPort, defHTTPPort),
+		mqtt:           mqttCfg,
+		writer:         loadWriterConfig(),
+		readerPort:     alpha.Env(envReaderPort, defReaderPort),
+		reader:         readerCfg,
+		readerDB:       alpha.Env(envReaderDB, defReaderDB),
+	}
+}
+
+func loadWriterConfig() writerConfig {
+	tr, err := writer.DefaultTransformer(alpha.Env(envWriterTransformer, defWriterTransformer), alpha.Env(envWriterContentType, defWriterContentType))
+	if err != nil {
+		log.Fatalf("Invalid %s or %s value: %s", envWriterTransformer, envWriterContentType, err.Error())
+	}
+
+	retryAttempts, err := strconv.Atoi(alpha.Env(envWriterRetryAttempts, defWriterRetryAttempts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWriterRetryAttempts, err.Error())
+	}
+
+	retryBackoff, err := time.ParseDuration(alpha.Env(envWriterRetryBackoff, defWriterRetryBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWriterRetryBackoff, err.Error())
+	}
+
+	retryMaxBackoff, err := time.ParseDuration(alpha.Env(envWriterRetryMaxBackoff, defWriterRetryMaxBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWriterRetryMaxBackoff, err.Error())
+	}
+
+	size, err := strconv.Atoi(alpha.Env(envWriterBatchSize, defWriterBatchSize))
+	if err != nil || size < 0 {
+		log.Fatalf("Invalid %s value: %s", envWriterBatchSize, alpha.Env(envWriterBatchSize, defWriterBatchSize))
+	}
+
+	timeout, err := strconv.ParseInt(alpha.Env(envWriterBatchTimeout, defWriterBatchTimeout), 10, 64)
+	if err != nil || timeout < 1 {
+		log.Fatalf("Invalid %s value: %s", envWriterBatchTimeout, alpha.Env(envWriterBatchTimeout, defWriterBatchTimeout))
+	}
+
+	queueSize, err := strconv.Atoi(alpha.Env(envWriterBatchQueueSize, defWriterBatchQueueSize))
+	if err != nil || queueSize < 0 {
+		log.Fatalf("Invalid %s value: %s", envWriterBatchQueueSize, alpha.Env(envWriterBatchQueueSize, defWriterBatchQueueSize))
+	}
+
+	workers, err := strconv.Atoi(alpha.Env(envWriterBatchWorkers, defWriterBatchWorkers))
+	if err != nil || workers < 1 {
+		log.Fatalf("Invalid %s value: %s", envWriterBatchWorkers, alpha.Env(envWriterBatchWorkers, defWriterBatchWorkers))
+	}
+
+	attempts, err := strconv.Atoi(alpha.Env(envWriterBatchAttempts, defWriterBatchAttempts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWriterBatchAttempts, err.Error())
+	}
+
+	backoff, err := time.ParseDuration(alpha.Env(envWriterBatchBackoff, defWriterBatchBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWriterBatchBackoff, err.Error())
+	}
+
+	maxBackoff, err := time.ParseDuration(alpha.Env(envWriterBatchMaxBackoff, defWriterBatchMaxBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWriterBatchMaxBackoff, err.Error())
+	}
+
+	return writerConfig{
+		dbConfig: influxdata.HTTPConfig{
+			Addr:     fmt.Sprintf("http://%s:%s", alpha.Env(envWriterDBHost, defWriterDBHost), alpha.Env(envWriterDBPort, defWriterDBPort)),
+			Username: alpha.Env(envWriterDBUser, defWriterDBUser),
+			Password: alpha.Env(envWriterDBPass, defWriterDBPass),
+		},
+		db:          alpha.Env(envWriterDB, defWriterDB),
+		configPath:  alpha.Env(envWriterConfig, defWriterConfig),
+		transformer: tr,
+		retry: messaging.RetryPolicy{
+			Attempts:   retryAttempts,
+			Backoff:    retryBackoff,
+			MaxBackoff: retryMaxBackoff,
+			Retryable:  writer.Retryable,
+		},
+		batch: writer.BatchConfig{
+			Size:        size,
+			Timeout:     time.Duration(timeout) * time.Second,
+			QueueSize:   queueSize,
+			Concurrency: workers,
+			Retry: messaging.RetryPolicy{
+				Attempts:   attempts,
+				Backoff:    backoff,
+				MaxBackoff: maxBackoff,
+			},
+		},
+	}
+}
+
+func connectToGRPC(url string, logger logger.Logger) *grpc.ClientConn {
+	conn, err := grpc.Dial(url, grpc.WithInsecure())
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s: %s", url, err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func newAuthnService(db *sqlx.DB, secret string, logger logger.Logger) authn.Service {
+	repo := authnpg.New(db)
+	idp := authnuuid.New()
+	t := jwt.New(secret)
+
+	svc := authn.New(repo, idp, t)
+	svc = authnapi.LoggingMiddleware(svc, logger)
+
+	return svc
+}
+
+func newUsersService(db *sqlx.DB, auth alpha.AuthNServiceClient, logger logger.Logger) users.Service {
+	repo := userspg.New(db)
+	orgRepo := userspg.NewOrganizationRepository(db)
+	hasher := bcrypt.New()
+	// Users share the UUID provider of the things, as in cmd/users.
+	idp := thingsuuid.New()
+
+	svc := users.New(repo, orgRepo, hasher, idp, auth)
+	svc = usersapi.LoggingMiddleware(svc, logger)
+
+	return svc
+}
+
+// newThingsService returns the things service without the event store
+// middleware, since none of the services run in the process consumes
+// the things events.
+func newThingsService(db *sqlx.DB, auth alpha.AuthNServiceClient, cfg thingsConfig, logger logger.Logger) things.Service {
+	thingsRepo := thingspg.NewThingRepository(db)
+	projectsRepo := thingspg.NewProjectRepository(db)
+	sharesRepo := thingspg.NewShareRepository(db)
+
+	thingCache := cache.NewThingCache(cfg.cacheSize, cfg.cacheTTL)
+	projectCache := cache.NewProjectCache(cfg.cacheSize, cfg.cacheTTL)
+
+	idp := thingsuuid.New()
+
+	svc := things.New(auth, thingsRepo, projectsRepo, sharesRepo, thingCache, projectCache, idp, cfg.keyGrace)
+	svc = thingsapi.LoggingMiddleware(svc, logger)
+
+	return svc
+}
+
+// startMQTTAdapter starts the MQTT adapter, forwarding the messages
+// published using the other adapters to the MQTT broker.
+func startMQTTAdapter(broker memory.Broker, tc alpha.ThingsServiceClient, cfg mqttConfig, logger logger.Logger, errs chan error) {
+	target := fmt.Sprintf("%s:%s", cfg.targetHost, cfg.targetPort)
+	mpub, err := mqttpub.NewPublisher(target, cfg.forwarderTimeout)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create MQTT publisher: %s", err))
+		os.Exit(1)
+	}
+
+	fwd := mqtt.NewForwarder(memory.SubjectAllProjects, logger)
+	if err := fwd.Forward(broker.PubSub("mqtt", 1), mpub); err != nil {
+		logger.Error(fmt.Sprintf("Failed to forward messages: %s", err))
+		os.Exit(1)
+	}
+
+	h := mqtt.NewHandler([]messaging.Publisher{broker.PubSub("", 1)}, tc, logger)
+
+	logger.Info(fmt.Sprintf("Starting MQTT proxy on port %s", cfg.port))
+	go proxyMQTT(cfg, logger, h, errs)
+
+	logger.Info(fmt.Sprintf("Starting MQTT over WS  proxy on port %s", cfg.wsPort))
+	go proxyWS(cfg, logger, h, errs)
+}
+
+func proxyMQTT(cfg mqttConfig, logger logger.Logger, handler session.Handler, errs chan error) {
+	address := fmt.Sprintf("%s:%s", cfg.host, cfg.port)
+	target := fmt.Sprintf("%s:%s", cfg.targetHost, cfg.targetPort)
+	mp := mp.New(address, target, handler, logger)
+
+	errs <- mp.Proxy()
+}
+
+func proxyWS(cfg mqttConfig, logger logger.Logger, handler session.Handler, errs chan error) {
+	target := fmt.Sprintf("%s:%s", cfg.wsTargetHost, cfg.wsTargetPort)
+	wp := ws.New(target, cfg.wsTargetPath, cfg.wsScheme, handler, logger)
+
+	mux := http.NewServeMux()
+	mux.Handle("/mqtt", wp.Handler())
+
+	p := fmt.Sprintf("%s:%s", cfg.wsHost, cfg.wsPort)
+	errs <- http.ListenAndServe(p, mux)
+}
+
+func startHTTPServer(name string, handler http.Handler, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("%s service started using http, exposed port %s", name, port))
+	errs <- http.ListenAndServe(p, handler)
+}
+
+func startAuthnGRPCServer(svc authn.Service, port string, logger logger.Logger, errs chan error) {
+	listener := listen(port, logger)
+	server := grpc.NewServer()
+	alpha.RegisterAuthNServiceServer(server, authngrpc.NewServer(svc))
+	logger.Info(fmt.Sprintf("Authentication gRPC service started, exposed port %s", port))
+	errs <- server.Serve(listener)
+}
+
+func startThingsGRPCServer(svc things.Service, port string, logger logger.Logger, errs chan error) {
+	listener := listen(port, logger)
+	server := grpc.NewServer()
+	alpha.RegisterThingsServiceServer(server, thingsgrpc.NewServer(svc))
+	logger.Info(fmt.Sprintf("Things gRPC service started, exposed port %s", port))
+	errs <- server.Serve(listener)
+}
+
+func listen(port string, logger logger.Logger) net.Listener {
+	p := fmt.Sprintf(":%s", port)
+	listener, err := net.Listen("tcp", p)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to listen on port %s: %s", port, err))
+		os.Exit(1)
+	}
+	return listener
+}
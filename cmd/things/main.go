@@ -18,6 +18,8 @@ import (
 	"github.com/vietquy/alpha/logger"
 	"github.com/vietquy/alpha/things"
 	"github.com/vietquy/alpha/things/api"
+	"github.com/vietquy/alpha/things/cache"
+	"github.com/vietquy/alpha/things/events"
 	authgrpcapi "github.com/vietquy/alpha/things/api/grpc"
 	thhttpapi "github.com/vietquy/alpha/things/api/http"
 	"github.com/vietquy/alpha/things/postgres"
@@ -36,6 +38,10 @@ const (
 	defGRPCPort    = "8181"
 	defAuthnURL        = "localhost:8181"
 	defAuthnTimeout    = "1" // in seconds
+	defCacheSize       = "100000"
+	defCacheTTL        = "300" // in seconds
+	defKeyGrace        = "86400" // in seconds
+	defNatsURL         = "nats://localhost:4222"
 
 	envLogLevel        = "AP_THINGS_LOG_LEVEL"
 	envDBHost          = "AP_THINGS_DB_HOST"
@@ -47,6 +53,10 @@ const (
 	envGRPCPort    	   = "AP_THINGS_GRPC_PORT"
 	envAuthnURL        = "AP_AUTHN_GRPC_URL"
 	envAuthnTimeout    = "AP_AUTHN_GRPC_TIMEOUT"
+	envCacheSize       = "AP_THINGS_CACHE_SIZE"
+	envCacheTTL        = "AP_THINGS_CACHE_TTL"
+	envKeyGrace        = "AP_THINGS_KEY_GRACE_PERIOD"
+	envNatsURL         = "AP_NATS_URL"
 )
 
 type config struct {
@@ -56,6 +66,10 @@ type config struct {
 	grpcPort    string
 	authnURL        string
 	authnTimeout    time.Duration
+	cacheSize       int
+	cacheTTL        time.Duration
+	keyGrace        time.Duration
+	natsURL         string
 }
 
 func main() {
@@ -74,7 +88,14 @@ func main() {
 		defer close()
 	}
 
-	svc := newService(auth, db, logger)
+	pub, err := events.NewPublisher(cfg.natsURL)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer pub.Close()
+
+	svc := newService(auth, db, pub, cfg, logger)
 	errs := make(chan error, 2)
 
 	go startHTTPServer(thhttpapi.MakeHandler(svc), cfg.httpPort, cfg, logger, errs)
@@ -96,6 +117,21 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envAuthnTimeout, err.Error())
 	}
 
+	cacheSize, err := strconv.Atoi(alpha.Env(envCacheSize, defCacheSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheSize, err.Error())
+	}
+
+	cacheTTL, err := strconv.ParseInt(alpha.Env(envCacheTTL, defCacheTTL), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheTTL, err.Error())
+	}
+
+	keyGrace, err := strconv.ParseInt(alpha.Env(envKeyGrace, defKeyGrace), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envKeyGrace, err.Error())
+	}
+
 	dbConfig := postgres.Config{
 		Host:        alpha.Env(envDBHost, defDBHost),
 		Port:        alpha.Env(envDBPort, defDBPort),
@@ -111,6 +147,10 @@ func loadConfig() config {
 		grpcPort:    	 alpha.Env(envGRPCPort, defGRPCPort),
 		authnURL:        alpha.Env(envAuthnURL, defAuthnURL),
 		authnTimeout:    time.Duration(timeout) * time.Second,
+		cacheSize:       cacheSize,
+		cacheTTL:        time.Duration(cacheTTL) * time.Second,
+		keyGrace:        time.Duration(keyGrace) * time.Second,
+		natsURL:         alpha.Env(envNatsURL, defNatsURL),
 	}
 }
 
@@ -143,13 +183,18 @@ func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func newService(auth alpha.AuthNServiceClient, db *sqlx.DB, logger logger.Logger) things.Service {
+func newService(auth alpha.AuthNServiceClient, db *sqlx.DB, pub events.Publisher, cfg config, logger logger.Logger) things.Service {
 	thingsRepo := postgres.NewThingRepository(db)
 	projectsRepo := postgres.NewProjectRepository(db)
+	sharesRepo := postgres.NewShareRepository(db)
+
+	thingCache := cache.NewThingCache(cfg.cacheSize, cfg.cacheTTL)
+	projectCache := cache.NewProjectCache(cfg.cacheSize, cfg.cacheTTL)
 
 	idp := uuid.New()
 
-	svc := things.New(auth, thingsRepo, projectsRepo, idp)
+	svc := things.New(auth, thingsRepo, projectsRepo, sharesRepo, thingCache, projectCache, idp, cfg.keyGrace)
+	svc = events.NewEventStoreMiddleware(svc, pub)
 	svc = api.LoggingMiddleware(svc, logger)
 
 	return svc
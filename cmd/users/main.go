@@ -17,6 +17,7 @@ import (
 	"github.com/vietquy/alpha"
 	authapi "github.com/vietquy/alpha/authn/api/grpc"
 	"github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/things/uuid"
 	"github.com/vietquy/alpha/users/api"
 	"github.com/vietquy/alpha/users/bcrypt"
 	"github.com/vietquy/alpha/users/postgres"
@@ -137,9 +138,11 @@ func connectToAuthn(cfg config, logger logger.Logger) (alpha.AuthNServiceClient,
 
 func newService(db *sqlx.DB, auth alpha.AuthNServiceClient, c config, logger logger.Logger) users.Service {
 	repo := postgres.New(db)
+	orgRepo := postgres.NewOrganizationRepository(db)
 	hasher := bcrypt.New()
+	idp := uuid.New()
 
-	svc := users.New(repo, hasher, auth)
+	svc := users.New(repo, orgRepo, hasher, idp, auth)
 	svc = api.LoggingMiddleware(svc, logger)
 
 	return svc
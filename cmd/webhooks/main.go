@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vietquy/alpha"
+	authapi "github.com/vietquy/alpha/authn/api/grpc"
+	"github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/messaging/broker"
+	thingsclient "github.com/vietquy/alpha/things/client"
+	"github.com/vietquy/alpha/things/uuid"
+	"github.com/vietquy/alpha/transformer"
+	"github.com/vietquy/alpha/webhooks"
+	"github.com/vietquy/alpha/webhooks/api"
+	"github.com/vietquy/alpha/webhooks/postgres"
+	"github.com/vietquy/alpha/webhooks/sender"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel        = "error"
+	defDBHost          = "localhost"
+	defDBPort          = "5432"
+	defDBUser          = "alpha"
+	defDBPass          = "alpha"
+	defDB              = "webhooks"
+	defHTTPPort        = "8210"
+	defThingsURL       = "http://localhost:8182"
+	defThingsTimeout   = "5" // in seconds
+	defAuthnURL        = "localhost:8181"
+	defAuthnTimeout    = "1" // in seconds
+	defTimeout         = "5" // in seconds
+	defRetryAttempts   = "5"
+	defRetryBackoff    = "1s"
+	defRetryMaxBackoff = "1m"
+	defWorkers         = "4"
+	defQueueSize       = "100"
+
+	envLogLevel        = "AP_WEBHOOKS_LOG_LEVEL"
+	envDBHost          = "AP_WEBHOOKS_DB_HOST"
+	envDBPort          = "AP_WEBHOOKS_DB_PORT"
+	envDBUser          = "AP_WEBHOOKS_DB_USER"
+	envDBPass          = "AP_WEBHOOKS_DB_PASS"
+	envDB              = "AP_WEBHOOKS_DB"
+	envHTTPPort        = "AP_WEBHOOKS_HTTP_PORT"
+	envThingsURL       = "AP_WEBHOOKS_THINGS_URL"
+	envThingsTimeout   = "AP_WEBHOOKS_THINGS_TIMEOUT"
+	envAuthnURL        = "AP_AUTHN_GRPC_URL"
+	envAuthnTimeout    = "AP_AUTHN_GRPC_TIMEOUT"
+	envTimeout         = "AP_WEBHOOKS_TIMEOUT"
+	envRetryAttempts   = "AP_WEBHOOKS_RETRY_ATTEMPTS"
+	envRetryBackoff    = "AP_WEBHOOKS_RETRY_BACKOFF"
+	envRetryMaxBackoff = "AP_WEBHOOKS_RETRY_MAX_BACKOFF"
+	envWorkers         = "AP_WEBHOOKS_WORKERS"
+	envQueueSize       = "AP_WEBHOOKS_QUEUE_SIZE"
+)
+
+type config struct {
+	logLevel      string
+	dbConfig      postgres.Config
+	httpPort      string
+	thingsURL     string
+	thingsTimeout time.Duration
+	authnURL      string
+	authnTimeout  time.Duration
+	broker        broker.Config
+	timeout       time.Duration
+	retry         messaging.RetryPolicy
+	queue         webhooks.QueueConfig
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	auth, close := connectToAuthn(cfg, logger)
+	if close != nil {
+		defer close()
+	}
+
+	pubSub, err := broker.NewPubSub(cfg.broker, "webhooks", "webhooks", logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
+		os.Exit(1)
+	}
+	defer pubSub.Close()
+
+	svc := newService(db, auth, cfg, logger)
+	if err := messaging.SubscribeAllProjects(pubSub, svc.Deliver); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to messages: %s", err))
+		os.Exit(1)
+	}
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Webhooks service terminated: %s", err))
+}
+
+func loadConfig() config {
+	authnTimeout, err := strconv.ParseInt(alpha.Env(envAuthnTimeout, defAuthnTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthnTimeout, err.Error())
+	}
+
+	thingsTimeout, err := strconv.ParseInt(alpha.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	timeout, err := strconv.ParseInt(alpha.Env(envTimeout, defTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envTimeout, err.Error())
+	}
+
+	attempts, err := strconv.Atoi(alpha.Env(envRetryAttempts, defRetryAttempts))
+	if err != nil || attempts < 1 {
+		log.Fatalf("Invalid %s value: %s", envRetryAttempts, alpha.Env(envRetryAttempts, defRetryAttempts))
+	}
+
+	backoff, err := time.ParseDuration(alpha.Env(envRetryBackoff, defRetryBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetryBackoff, err.Error())
+	}
+
+	maxBackoff, err := time.ParseDuration(alpha.Env(envRetryMaxBackoff, defRetryMaxBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetryMaxBackoff, err.Error())
+	}
+
+	workers, err := strconv.Atoi(alpha.Env(envWorkers, defWorkers))
+	if err != nil || workers < 1 {
+		log.Fatalf("Invalid %s value: %s", envWorkers, alpha.Env(envWorkers, defWorkers))
+	}
+
+	queueSize, err := strconv.Atoi(alpha.Env(envQueueSize, defQueueSize))
+	if err != nil || queueSize < 0 {
+		log.Fatalf("Invalid %s value: %s", envQueueSize, alpha.Env(envQueueSize, defQueueSize))
+	}
+
+	dbConfig := postgres.Config{
+		Host: alpha.Env(envDBHost, defDBHost),
+		Port: alpha.Env(envDBPort, defDBPort),
+		User: alpha.Env(envDBUser, defDBUser),
+		Pass: alpha.Env(envDBPass, defDBPass),
+		Name: alpha.Env(envDB, defDB),
+	}
+
+	brokerConfig, err := broker.LoadConfig()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	return config{
+		logLevel:      alpha.Env(envLogLevel, defLogLevel),
+		dbConfig:      dbConfig,
+		httpPort:      alpha.Env(envHTTPPort, defHTTPPort),
+		thingsURL:     alpha.Env(envThingsURL, defThingsURL),
+		thingsTimeout: time.Duration(thingsTimeout) * time.Second,
+		authnURL:      alpha.Env(envAuthnURL, defAuthnURL),
+		authnTimeout:  time.Duration(authnTimeout) * time.Second,
+		broker:        brokerConfig,
+		timeout:       time.Duration(timeout) * time.Second,
+		retry: messaging.RetryPolicy{
+			Attempts:   attempts,
+			Backoff:    backoff,
+			MaxBackoff: maxBackoff,
+		},
+		queue: webhooks.QueueConfig{
+			Workers: workers,
+			Size:    queueSize,
+		},
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+
+	return db
+}
+
+func connectToAuthn(cfg config, logger logger.Logger) (alpha.AuthNServiceClient, func() error) {
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithInsecure())
+	logger.Info("gRPC communication is not encrypted")
+
+	conn, err := grpc.Dial(cfg.authnURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to authn service: %s", err))
+		os.Exit(1)
+	}
+
+	return authapi.NewClient(conn, cfg.authnTimeout), conn.Close
+}
+
+func newService(db *sqlx.DB, auth alpha.AuthNServiceClient, c config, logger logger.Logger) webhooks.Service {
+	webhookRepo := postgres.NewWebhookRepository(db)
+	deadLetterRepo := postgres.NewDeadLetterRepository(db)
+	ths := thingsclient.New(c.thingsURL, c.thingsTimeout, thingsclient.Errors{
+		Unauthorized: webhooks.ErrUnauthorizedAccess,
+		NotFound:     webhooks.ErrNotFound,
+		Failed:       webhooks.ErrThings,
+	})
+	idp := uuid.New()
+	snd := sender.New(c.timeout)
+
+	svc := webhooks.New(auth, webhookRepo, deadLetterRepo, ths, idp, transformer.New(), snd, c.retry, c.queue, logger)
+	svc = api.LoggingMiddleware(svc, logger)
+
+	return svc
+}
+
+func startHTTPServer(svc webhooks.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Webhooks service started using http, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
@@ -2,85 +2,135 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	influxdata "github.com/influxdata/influxdb/client/v2"
 	"github.com/vietquy/alpha"
 	"github.com/vietquy/alpha/logger"
-	"github.com/vietquy/alpha/messaging/nats"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/messaging/broker"
 	"github.com/vietquy/alpha/transformer"
+	"github.com/vietquy/alpha/transformer/senml"
 	"github.com/vietquy/alpha/writer"
 	"github.com/vietquy/alpha/writer/api"
 	"github.com/vietquy/alpha/writer/influxdb"
+	"github.com/vietquy/alpha/writer/postgres"
 )
 
 const (
-	svcName = "influxdb-writer"
+	influxDBType   = "influxdb"
+	postgresDBType = "postgres"
 
-	defNatsURL         = "nats://localhost:4222"
 	defLogLevel        = "error"
 	defPort            = "8180"
+	defDBType          = influxDBType
 	defDB              = "messages"
 	defDBHost          = "localhost"
-	defDBPort          = "8086"
+	defInfluxDBPort    = "8086"
+	defPostgresDBPort  = "5432"
 	defDBUser          = "alpha"
 	defDBPass          = "alpha"
+	defRetryAttempts   = "3"
+	defRetryBackoff    = "100ms"
+	defRetryMaxBackoff = "1s"
+	defBatchSize       = "5000"
+	defBatchTimeout    = "5" // in seconds
+	defBatchQueueSize  = "100"
+	defBatchWorkers    = "4"
+	defBatchAttempts   = "10"
+	defBatchBackoff    = "1s"
+	defBatchMaxBackoff = "30s"
+	defConfigPath      = ""
+	defTransformer     = writer.JSONTransformer
+	defContentType     = senml.JSON
 
-	envNatsURL         = "AP_NATS_URL"
 	envLogLevel        = "AP_WRITER_LOG_LEVEL"
 	envPort            = "AP_WRITER_PORT"
+	envDBType          = "AP_WRITER_DB_TYPE"
 	envDB              = "AP_WRITER_DB"
 	envDBHost          = "AP_WRITER_DB_HOST"
 	envDBPort          = "AP_WRITER_DB_PORT"
 	envDBUser          = "AP_WRITER_DB_USER"
 	envDBPass          = "AP_WRITER_DB_PASS"
+	envRetryAttempts   = "AP_WRITER_RETRY_ATTEMPTS"
+	envRetryBackoff    = "AP_WRITER_RETRY_BACKOFF"
+	envRetryMaxBackoff = "AP_WRITER_RETRY_MAX_BACKOFF"
+	envBatchSize       = "AP_WRITER_BATCH_SIZE"
+	envBatchTimeout    = "AP_WRITER_BATCH_TIMEOUT"
+	envBatchQueueSize  = "AP_WRITER_BATCH_QUEUE_SIZE"
+	envBatchWorkers    = "AP_WRITER_BATCH_WORKERS"
+	envBatchAttempts   = "AP_WRITER_BATCH_RETRY_ATTEMPTS"
+	envBatchBackoff    = "AP_WRITER_BATCH_RETRY_BACKOFF"
+	envBatchMaxBackoff = "AP_WRITER_BATCH_RETRY_MAX_BACKOFF"
+	envConfigPath      = "AP_WRITER_CONFIG"
+	envTransformer     = "AP_WRITER_TRANSFORMER"
+	envContentType     = "AP_WRITER_CONTENT_TYPE"
 )
 
 type config struct {
-	natsURL         string
-	logLevel        string
-	port            string
-	dbName          string
-	dbHost          string
-	dbPort          string
-	dbUser          string
-	dbPass          string
-	contentType     string
+	broker      broker.Config
+	logLevel    string
+	port        string
+	dbType      string
+	dbName      string
+	dbHost      string
+	dbPort      string
+	dbUser      string
+	dbPass      string
+	retry       messaging.RetryPolicy
+	batch       writer.BatchConfig
+	configPath  string
+	transformer transformer.Transformer
 }
 
 func main() {
-	cfg, clientCfg := loadConfigs()
+	cfg := loadConfigs()
 
 	logger, err := logger.New(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
 
-	pubSub, err := nats.NewPubSub(cfg.natsURL, "", logger)
+	pubSub, err := broker.NewPubSub(cfg.broker, "writer", "", logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer pubSub.Close()
 
-	client, err := influxdata.NewHTTPClient(clientCfg)
+	repo, db, err := newRepo(cfg)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to create InfluxDB client: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to %s database: %s", cfg.dbType, err))
 		os.Exit(1)
 	}
-	defer client.Close()
-
-	repo := influxdb.New(client, cfg.dbName)
+	defer db.Close()
 
 	repo = api.LoggingMiddleware(repo, logger)
-	t := transformer.New()
 
-	if err := writer.Start(pubSub, repo, t, logger); err != nil {
-		logger.Error(fmt.Sprintf("Failed to start InfluxDB writer: %s", err))
+	dlp, err := broker.NewDeadLetterPublisher(cfg.broker, "writer")
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
+		os.Exit(1)
+	}
+	defer dlp.Close()
+
+	wcfg, err := writer.LoadConfig(cfg.configPath)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load writer configuration: %s", err))
+		os.Exit(1)
+	}
+
+	sub := messaging.NewRetrySubscriber(pubSub, cfg.retry, dlp)
+	bw, err := writer.Run(sub, repo, cfg.transformer, wcfg, cfg.batch, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to start writer: %s", err))
 		os.Exit(1)
 	}
 
@@ -91,35 +141,164 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	go startHTTPService(cfg.port, logger, errs)
+	go startHTTPService(cfg, logger, errs)
 
 	err = <-errs
-	logger.Error(fmt.Sprintf("InfluxDB writer service terminated: %s", err))
+	logger.Error(fmt.Sprintf("Writer service terminated: %s", err))
+
+	// Subscription is closed first, so that no message is received while the
+	// buffered messages are written.
+	pubSub.Close()
+	bw.Close()
+}
+
+func loadConfigs() config {
+	brokerConfig, err := broker.LoadConfig()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	// Messages of the batch are acknowledged once it's written, so that the
+	// subscriptions handle up to the batch size messages at the same time.
+	batch := loadBatchConfig()
+	brokerConfig.Concurrency = batch.Size
+
+	retryAttempts, err := strconv.Atoi(alpha.Env(envRetryAttempts, defRetryAttempts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetryAttempts, err.Error())
+	}
+
+	retryBackoff, err := time.ParseDuration(alpha.Env(envRetryBackoff, defRetryBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetryBackoff, err.Error())
+	}
+
+	retryMaxBackoff, err := time.ParseDuration(alpha.Env(envRetryMaxBackoff, defRetryMaxBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetryMaxBackoff, err.Error())
+	}
+
+	retry := messaging.RetryPolicy{
+		Attempts:   retryAttempts,
+		Backoff:    retryBackoff,
+		MaxBackoff: retryMaxBackoff,
+		Retryable:  writer.Retryable,
+	}
+
+	tr, err := writer.DefaultTransformer(alpha.Env(envTransformer, defTransformer), alpha.Env(envContentType, defContentType))
+	if err != nil {
+		log.Fatalf("Invalid %s or %s value: %s", envTransformer, envContentType, err.Error())
+	}
+
+	dbType := alpha.Env(envDBType, defDBType)
+	defDBPort := defInfluxDBPort
+	switch dbType {
+	case influxDBType:
+	case postgresDBType:
+		defDBPort = defPostgresDBPort
+	default:
+		log.Fatalf("Invalid %s value: %s", envDBType, dbType)
+	}
+
+	return config{
+		broker:      brokerConfig,
+		logLevel:    alpha.Env(envLogLevel, defLogLevel),
+		port:        alpha.Env(envPort, defPort),
+		dbType:      dbType,
+		dbName:      alpha.Env(envDB, defDB),
+		dbHost:      alpha.Env(envDBHost, defDBHost),
+		dbPort:      alpha.Env(envDBPort, defDBPort),
+		dbUser:      alpha.Env(envDBUser, defDBUser),
+		dbPass:      alpha.Env(envDBPass, defDBPass),
+		retry:       retry,
+		batch:       batch,
+		configPath:  alpha.Env(envConfigPath, defConfigPath),
+		transformer: tr,
+	}
 }
 
-func loadConfigs() (config, influxdata.HTTPConfig) {
-	cfg := config{
-		natsURL:         alpha.Env(envNatsURL, defNatsURL),
-		logLevel:        alpha.Env(envLogLevel, defLogLevel),
-		port:            alpha.Env(envPort, defPort),
-		dbName:          alpha.Env(envDB, defDB),
-		dbHost:          alpha.Env(envDBHost, defDBHost),
-		dbPort:          alpha.Env(envDBPort, defDBPort),
-		dbUser:          alpha.Env(envDBUser, defDBUser),
-		dbPass:          alpha.Env(envDBPass, defDBPass),
+func loadBatchConfig() writer.BatchConfig {
+	size, err := strconv.Atoi(alpha.Env(envBatchSize, defBatchSize))
+	if err != nil || size < 0 {
+		log.Fatalf("Invalid %s value: %s", envBatchSize, alpha.Env(envBatchSize, defBatchSize))
+	}
+
+	timeout, err := strconv.ParseInt(alpha.Env(envBatchTimeout, defBatchTimeout), 10, 64)
+	if err != nil || timeout < 1 {
+		log.Fatalf("Invalid %s value: %s", envBatchTimeout, alpha.Env(envBatchTimeout, defBatchTimeout))
+	}
+
+	queueSize, err := strconv.Atoi(alpha.Env(envBatchQueueSize, defBatchQueueSize))
+	if err != nil || queueSize < 0 {
+		log.Fatalf("Invalid %s value: %s", envBatchQueueSize, alpha.Env(envBatchQueueSize, defBatchQueueSize))
 	}
 
-	clientCfg := influxdata.HTTPConfig{
-		Addr:     fmt.Sprintf("http://%s:%s", cfg.dbHost, cfg.dbPort),
-		Username: cfg.dbUser,
-		Password: cfg.dbPass,
+	workers, err := strconv.Atoi(alpha.Env(envBatchWorkers, defBatchWorkers))
+	if err != nil || workers < 1 {
+		log.Fatalf("Invalid %s value: %s", envBatchWorkers, alpha.Env(envBatchWorkers, defBatchWorkers))
 	}
 
-	return cfg, clientCfg
+	attempts, err := strconv.Atoi(alpha.Env(envBatchAttempts, defBatchAttempts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchAttempts, err.Error())
+	}
+
+	backoff, err := time.ParseDuration(alpha.Env(envBatchBackoff, defBatchBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchBackoff, err.Error())
+	}
+
+	maxBackoff, err := time.ParseDuration(alpha.Env(envBatchMaxBackoff, defBatchMaxBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchMaxBackoff, err.Error())
+	}
+
+	return writer.BatchConfig{
+		Size:        size,
+		Timeout:     time.Duration(timeout) * time.Second,
+		QueueSize:   queueSize,
+		Concurrency: workers,
+		Retry: messaging.RetryPolicy{
+			Attempts:   attempts,
+			Backoff:    backoff,
+			MaxBackoff: maxBackoff,
+		},
+	}
+}
+
+// newRepo returns the writer of the configured database type, along with the
+// database connection to be closed once the writer is stopped.
+func newRepo(cfg config) (writer.Writer, io.Closer, error) {
+	switch cfg.dbType {
+	case postgresDBType:
+		dbCfg := postgres.Config{
+			Host: cfg.dbHost,
+			Port: cfg.dbPort,
+			User: cfg.dbUser,
+			Pass: cfg.dbPass,
+			Name: cfg.dbName,
+		}
+		db, err := postgres.Connect(dbCfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return postgres.New(db), db, nil
+	default:
+		clientCfg := influxdata.HTTPConfig{
+			Addr:     fmt.Sprintf("http://%s:%s", cfg.dbHost, cfg.dbPort),
+			Username: cfg.dbUser,
+			Password: cfg.dbPass,
+		}
+		client, err := influxdata.NewHTTPClient(clientCfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return influxdb.New(client, cfg.dbName), client, nil
+	}
 }
 
-func startHTTPService(port string, logger logger.Logger, errs chan error) {
-	p := fmt.Sprintf(":%s", port)
-	logger.Info(fmt.Sprintf("InfluxDB writer service started, exposed port %s", p))
-	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
+func startHTTPService(cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.port)
+	logger.Info(fmt.Sprintf("Writer service started, exposed port %s", p))
+	errs <- http.ListenAndServe(p, api.MakeHandler(fmt.Sprintf("%s-writer", cfg.dbType)))
 }
@@ -6,6 +6,12 @@ envsubst '${AP_MQTT_ADAPTER_WS_PORT}' < /etc/nginx/snippets/mqtt-ws-upstream-sin
 envsubst '
     ${AP_USERS_HTTP_PORT}
     ${AP_THINGS_HTTP_PORT}
+    ${AP_BOOTSTRAP_HTTP_PORT}
+    ${AP_CERTS_HTTP_PORT}
+    ${AP_TWINS_HTTP_PORT}
+    ${AP_RULES_HTTP_PORT}
+    ${AP_WEBHOOKS_HTTP_PORT}
+    ${AP_NOTIFIERS_HTTP_PORT}
     ${AP_HTTP_ADAPTER_PORT}
     ${AP_WS_ADAPTER_PORT}' < /etc/nginx/nginx.conf.template > /etc/nginx/nginx.conf
 
@@ -11,6 +11,10 @@ import (
 type Service interface {
 	// Publish Messssage
 	Publish(ctx context.Context, token string, msg messaging.Message) error
+
+	// PublishByID publishes the message on behalf of the thing having the
+	// provided ID, authenticated by its client certificate.
+	PublishByID(ctx context.Context, thingID string, msg messaging.Message) error
 }
 
 var _ Service = (*adapterService)(nil)
@@ -29,11 +33,14 @@ func New(publisher messaging.Publisher, things alpha.ThingsServiceClient) Servic
 }
 
 func (as *adapterService) Publish(ctx context.Context, token string, msg messaging.Message) error {
-	ar := &alpha.AccessByKeyReq{
-		Token:  token,
+	// Key is resolved to the thing ID by the same call that authorizes
+	// publishing.
+	pr := &alpha.AccessByIDReq{
+		Token:     token,
 		ProjectID: msg.Project,
+		Subtopic:  msg.Subtopic,
 	}
-	thid, err := as.things.CanAccessByKey(ctx, ar)
+	thid, err := as.things.CanPublish(ctx, pr)
 	if err != nil {
 		return err
 	}
@@ -41,3 +48,22 @@ func (as *adapterService) Publish(ctx context.Context, token string, msg messagi
 
 	return as.publisher.Publish(msg.Project, msg)
 }
+
+func (as *adapterService) PublishByID(ctx context.Context, thingID string, msg messaging.Message) error {
+	msg.Publisher = thingID
+
+	return as.publish(ctx, msg)
+}
+
+func (as *adapterService) publish(ctx context.Context, msg messaging.Message) error {
+	pr := &alpha.AccessByIDReq{
+		ThingID:   msg.Publisher,
+		ProjectID: msg.Project,
+		Subtopic:  msg.Subtopic,
+	}
+	if _, err := as.things.CanPublish(ctx, pr); err != nil {
+		return err
+	}
+
+	return as.publisher.Publish(msg.Project, msg)
+}
@@ -10,6 +10,9 @@ import (
 func sendMessageEndpoint(svc http.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(publishReq)
+		if req.thingID != "" {
+			return nil, svc.PublishByID(ctx, req.thingID, req.msg)
+		}
 		err := svc.Publish(ctx, req.token, req.msg)
 		return nil, err
 	}
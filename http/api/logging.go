@@ -38,3 +38,20 @@ func (lm *loggingMiddleware) Publish(ctx context.Context, token string, msg mess
 
 	return lm.svc.Publish(ctx, token, msg)
 }
+
+func (lm *loggingMiddleware) PublishByID(ctx context.Context, thingID string, msg messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		destProject := msg.Project
+		if msg.Subtopic != "" {
+			destProject = fmt.Sprintf("%s.%s", destProject, msg.Subtopic)
+		}
+		message := fmt.Sprintf("Method publish_by_id for thing %s to project %s took %s to complete", thingID, destProject, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PublishByID(ctx, thingID, msg)
+}
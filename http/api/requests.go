@@ -5,6 +5,7 @@ import (
 )
 
 type publishReq struct {
-	msg   messaging.Message
-	token string
+	msg     messaging.Message
+	token   string
+	thingID string
 }
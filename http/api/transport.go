@@ -14,6 +14,7 @@ import (
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/certs/mtls"
 	adapter "github.com/vietquy/alpha/http"
 	"github.com/vietquy/alpha/messaging"
 	"github.com/vietquy/alpha/things"
@@ -28,6 +29,15 @@ var (
 	errMalformedSubtopic = errors.New("malformed subtopic")
 )
 
+// headers maps the request headers to the message headers they are copied to.
+var headers = map[string]string{
+	"Content-Type":      messaging.ContentTypeHeader,
+	"X-Correlation-ID":  messaging.CorrelationIDHeader,
+	"Traceparent":       messaging.TraceParentHeader,
+	"Tracestate":        messaging.TraceStateHeader,
+	"X-Alpha-Timestamp": messaging.TimestampHeader,
+}
+
 var projectPartRegExp = regexp.MustCompile(`^/projects/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
 
 // MakeHandler returns a HTTP handler for API endpoints.
@@ -108,16 +118,35 @@ func decodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 		Subtopic: subtopic,
 		Payload:  payload,
 		Created:  time.Now().UnixNano(),
+		Headers:  decodeHeaders(r.Header),
 	}
 
+	// The thing authenticated by the client certificate doesn't need to
+	// provide its key.
 	req := publishReq{
-		msg:   msg,
-		token: r.Header.Get("Authorization"),
+		msg:     msg,
+		token:   r.Header.Get("Authorization"),
+		thingID: mtls.ThingID(r.TLS),
 	}
 
 	return req, nil
 }
 
+func decodeHeaders(h http.Header) map[string]string {
+	ret := map[string]string{}
+	for name, header := range headers {
+		if val := h.Get(name); val != "" {
+			ret[header] = val
+		}
+	}
+
+	if len(ret) == 0 {
+		return nil
+	}
+
+	return ret
+}
+
 func decodePayload(body io.ReadCloser) ([]byte, error) {
 	payload, err := ioutil.ReadAll(body)
 	if err != nil {
@@ -0,0 +1,219 @@
+// Package broker provides the message publishers and subscribers of the
+// message broker selected by the configuration, being either NATS, using
+// JetStream if enabled, or Kafka.
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vietquy/alpha"
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/messaging/kafka"
+	"github.com/vietquy/alpha/messaging/nats"
+)
+
+const (
+	// NATS selects the NATS broker.
+	NATS = "nats"
+
+	// Kafka selects the Kafka broker.
+	Kafka = "kafka"
+)
+
+const (
+	defType            = NATS
+	defNatsURL         = "nats://localhost:4222"
+	defNatsJetStream   = "false"
+	defNatsMaxDeliver  = "5"
+	defNatsMaxAge      = "24h"
+	defNatsMaxBytes    = "1073741824"
+	defKafkaURL        = "localhost:9092"
+	defKafkaMaxDeliver = "5"
+
+	envType            = "AP_BROKER_TYPE"
+	envNatsURL         = "AP_NATS_URL"
+	envNatsJetStream   = "AP_NATS_JETSTREAM"
+	envNatsMaxDeliver  = "AP_NATS_JETSTREAM_MAX_DELIVER"
+	envNatsMaxAge      = "AP_NATS_JETSTREAM_MAX_AGE"
+	envNatsMaxBytes    = "AP_NATS_JETSTREAM_MAX_BYTES"
+	envKafkaURL        = "AP_KAFKA_URL"
+	envKafkaMaxDeliver = "AP_KAFKA_MAX_DELIVER"
+)
+
+var errUnknownType = errors.New("unknown message broker type")
+
+// Config defines the options used to connect to the message broker. KafkaURL
+// holds the comma-separated addresses of the Kafka bootstrap brokers, while
+// JetStreamMaxDeliver and KafkaMaxDeliver limit the number of the deliveries
+// of the message before it is published to the dead letters. JetStreamLimits
+// limit the messages kept by the JetStream streams. Concurrency is the number
+// of the messages each subscription handles at the same time, which isn't
+// read from the environment, but set by the services handling the messages
+// concurrently. Messages are handled one at a time by default.
+type Config struct {
+	Type                string
+	NatsURL             string
+	JetStream           bool
+	JetStreamMaxDeliver int
+	JetStreamLimits     nats.StreamLimits
+	KafkaURL            string
+	KafkaMaxDeliver     int
+	Concurrency         int
+}
+
+// PubSub wraps messaging PubSub exposing Close() method for the broker
+// connection.
+type PubSub interface {
+	messaging.PubSub
+	Close()
+}
+
+// Publisher wraps messaging Publisher exposing Close() method for the broker
+// connection.
+type Publisher interface {
+	messaging.Publisher
+	Close()
+}
+
+// DeadLetterPublisher wraps messaging DeadLetterPublisher exposing Close()
+// method for the broker connection.
+type DeadLetterPublisher interface {
+	messaging.DeadLetterPublisher
+	Close()
+}
+
+// Validate returns an error if the broker type is unknown.
+func (cfg Config) Validate() error {
+	if cfg.Type != NATS && cfg.Type != Kafka {
+		return errUnknownType
+	}
+
+	return nil
+}
+
+// LoadConfig reads the broker configuration, shared by all the services, from
+// the environment variables.
+func LoadConfig() (Config, error) {
+	jetStream, err := strconv.ParseBool(alpha.Env(envNatsJetStream, defNatsJetStream))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid %s value: %s", envNatsJetStream, err)
+	}
+
+	jsMaxDeliver, err := strconv.Atoi(alpha.Env(envNatsMaxDeliver, defNatsMaxDeliver))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid %s value: %s", envNatsMaxDeliver, err)
+	}
+
+	jsMaxAge, err := time.ParseDuration(alpha.Env(envNatsMaxAge, defNatsMaxAge))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid %s value: %s", envNatsMaxAge, err)
+	}
+
+	jsMaxBytes, err := strconv.ParseInt(alpha.Env(envNatsMaxBytes, defNatsMaxBytes), 10, 64)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid %s value: %s", envNatsMaxBytes, err)
+	}
+
+	kafkaMaxDeliver, err := strconv.Atoi(alpha.Env(envKafkaMaxDeliver, defKafkaMaxDeliver))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid %s value: %s", envKafkaMaxDeliver, err)
+	}
+
+	cfg := Config{
+		Type:                alpha.Env(envType, defType),
+		NatsURL:             alpha.Env(envNatsURL, defNatsURL),
+		JetStream:           jetStream,
+		JetStreamMaxDeliver: jsMaxDeliver,
+		JetStreamLimits: nats.StreamLimits{
+			MaxAge:   jsMaxAge,
+			MaxBytes: jsMaxBytes,
+		},
+		KafkaURL:        alpha.Env(envKafkaURL, defKafkaURL),
+		KafkaMaxDeliver: kafkaMaxDeliver,
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid %s value: %s", envType, err)
+	}
+
+	return cfg, nil
+}
+
+// NewPubSub returns the message publisher/subscriber of the configured
+// broker. Subscribers sharing the queue receive each message once, the same
+// as NATS QueueSubscribe, while the subscribers having the empty queue
+// receive all the messages. Name identifies the service, naming the durable
+// JetStream consumer.
+func NewPubSub(cfg Config, name, queue string, logger log.Logger) (PubSub, error) {
+	switch cfg.Type {
+	case NATS:
+		if cfg.JetStream {
+			return nats.NewJetStreamPubSub(cfg.NatsURL, name, cfg.JetStreamMaxDeliver, cfg.Concurrency, cfg.JetStreamLimits, logger)
+		}
+		return nats.NewPubSub(cfg.NatsURL, queue, cfg.Concurrency, logger)
+	case Kafka:
+		b, err := kafka.NewBroker(cfg.kafkaBrokers())
+		if err != nil {
+			return nil, err
+		}
+		return kafka.NewPubSub(b, queue, cfg.KafkaMaxDeliver, cfg.Concurrency, logger)
+	default:
+		return nil, errUnknownType
+	}
+}
+
+// NewPublisher returns the message publisher of the configured broker.
+func NewPublisher(cfg Config, logger log.Logger) (Publisher, error) {
+	switch cfg.Type {
+	case NATS:
+		if cfg.JetStream {
+			return nats.NewJetStreamPublisher(cfg.NatsURL, cfg.JetStreamLimits)
+		}
+		return nats.NewPublisher(cfg.NatsURL)
+	case Kafka:
+		return NewPubSub(cfg, "", "", logger)
+	default:
+		return nil, errUnknownType
+	}
+}
+
+// NewDeadLetterPublisher returns the dead letters publisher of the configured
+// broker, publishing the dead letters of the service having the provided
+// name.
+func NewDeadLetterPublisher(cfg Config, name string) (DeadLetterPublisher, error) {
+	switch cfg.Type {
+	case NATS:
+		if cfg.JetStream {
+			return nats.NewJetStreamDeadLetterPublisher(cfg.NatsURL, name, cfg.JetStreamLimits)
+		}
+		return nats.NewDeadLetterPublisher(cfg.NatsURL, name)
+	case Kafka:
+		b, err := kafka.NewBroker(cfg.kafkaBrokers())
+		if err != nil {
+			return nil, err
+		}
+		return kafkaDeadLetterPublisher{
+			DeadLetterPublisher: kafka.NewDeadLetterPublisher(b, name),
+			broker:              b,
+		}, nil
+	default:
+		return nil, errUnknownType
+	}
+}
+
+type kafkaDeadLetterPublisher struct {
+	messaging.DeadLetterPublisher
+	broker kafka.Broker
+}
+
+func (pub kafkaDeadLetterPublisher) Close() {
+	pub.broker.Close()
+}
+
+func (cfg Config) kafkaBrokers() []string {
+	return strings.Split(cfg.KafkaURL, ",")
+}
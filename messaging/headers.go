@@ -0,0 +1,32 @@
+package messaging
+
+// Names of the well-known message headers. Adapters may set any other header,
+// and the message backends preserve them all.
+const (
+	// ContentTypeHeader is the media type of the message payload.
+	ContentTypeHeader = "content-type"
+
+	// CorrelationIDHeader correlates the message with the other messages,
+	// e.g. the request with its response.
+	CorrelationIDHeader = "correlation-id"
+
+	// TraceParentHeader and TraceStateHeader carry the W3C trace context of
+	// the message.
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+
+	// TimestampHeader is the time reported by the device when the message
+	// was produced, as opposed to the time the message was received.
+	TimestampHeader = "timestamp"
+
+	// QoSHeader and RetainHeader are the quality of service level and the
+	// retain flag of the message published over MQTT.
+	QoSHeader    = "qos"
+	RetainHeader = "retain"
+)
+
+// Header returns the value of the message header having the provided name, or
+// an empty string if the message has no such header.
+func (m *Message) Header(name string) string {
+	return m.GetHeaders()[name]
+}
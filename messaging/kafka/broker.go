@@ -0,0 +1,43 @@
+package kafka
+
+import "regexp"
+
+// Record represents the record appended to the Kafka topic.
+type Record struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// RecordHandler represents Record handler for Broker.
+type RecordHandler func(rec Record) error
+
+// Broker specifies the API of the Kafka client used by the pub/sub, so that
+// the pub/sub doesn't depend on the particular client library.
+type Broker interface {
+	// Produce appends the record to its topic, creating the topic if it
+	// doesn't exist. Records having the same key are appended to the same
+	// partition, keeping their order.
+	Produce(rec Record) error
+
+	// Consume joins the consumer group and consumes the records of the
+	// topics matching the pattern. Each record is handled by a single member
+	// of the group, while every group receives all the records. Consumption
+	// of the newly created group starts from the latest offsets. Records are
+	// acknowledged once the handler returns nil, while otherwise they are
+	// handled again, so the handler must give up on the records it can
+	// never handle. Up to concurrency records are handled at the same time,
+	// while the records are acknowledged in the order they are consumed.
+	Consume(pattern *regexp.Regexp, group string, concurrency int, handler RecordHandler) (Consumer, error)
+
+	// Close closes the connection to the cluster.
+	Close() error
+}
+
+// Consumer represents the membership in the consumer group.
+type Consumer interface {
+	// Close leaves the consumer group, committing the offsets of the
+	// handled records.
+	Close() error
+}
@@ -0,0 +1,290 @@
+package kafka
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	broker "github.com/segmentio/kafka-go"
+	"github.com/vietquy/alpha/messaging"
+)
+
+const (
+	// topicsRefresh is the period of checking for the new topics matching
+	// the pattern of the consumer.
+	topicsRefresh = 10 * time.Second
+
+	// redeliveryDelay is the wait before the record whose handling failed
+	// is handled again.
+	redeliveryDelay = time.Second
+)
+
+var _ Broker = (*clusterBroker)(nil)
+
+// clusterBroker is the broker connected to the Kafka cluster.
+type clusterBroker struct {
+	brokers []string
+	writer  *broker.Writer
+}
+
+type clusterConsumer struct {
+	broker      *clusterBroker
+	pattern     *regexp.Regexp
+	group       string
+	concurrency int
+	handler     RecordHandler
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewBroker returns the broker connected to the Kafka cluster having the
+// provided bootstrap brokers. Records are produced once acknowledged by all
+// the in-sync replicas, while the consumed records offsets are committed
+// once handled.
+func NewBroker(brokers []string) (Broker, error) {
+	conn, err := broker.Dial("tcp", brokers[0])
+	if err != nil {
+		return nil, err
+	}
+	conn.Close()
+
+	w := &broker.Writer{
+		Addr:                   broker.TCP(brokers...),
+		Balancer:               &broker.Hash{},
+		RequiredAcks:           broker.RequireAll,
+		AllowAutoTopicCreation: true,
+	}
+	ret := &clusterBroker{
+		brokers: brokers,
+		writer:  w,
+	}
+	return ret, nil
+}
+
+func (cb *clusterBroker) Produce(rec Record) error {
+	msg := broker.Message{
+		Topic: rec.Topic,
+		Key:   rec.Key,
+		Value: rec.Value,
+	}
+	for k, v := range rec.Headers {
+		msg.Headers = append(msg.Headers, broker.Header{Key: k, Value: []byte(v)})
+	}
+
+	return cb.writer.WriteMessages(context.Background(), msg)
+}
+
+func (cb *clusterBroker) Consume(pattern *regexp.Regexp, group string, concurrency int, handler RecordHandler) (Consumer, error) {
+	topics, err := cb.topics(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &clusterConsumer{
+		broker:      cb,
+		pattern:     pattern,
+		group:       group,
+		concurrency: concurrency,
+		handler:     handler,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go c.run(ctx, topics)
+
+	return c, nil
+}
+
+func (cb *clusterBroker) Close() error {
+	return cb.writer.Close()
+}
+
+// topics returns the sorted names of the topics matching the pattern.
+func (cb *clusterBroker) topics(pattern *regexp.Regexp) ([]string, error) {
+	conn, err := broker.Dial("tcp", cb.brokers[0])
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions()
+	if err != nil {
+		return nil, err
+	}
+
+	set := map[string]bool{}
+	for _, p := range partitions {
+		if pattern.MatchString(p.Topic) {
+			set[p.Topic] = true
+		}
+	}
+	topics := []string{}
+	for t := range set {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+
+	return topics, nil
+}
+
+// run consumes the topics matching the pattern. The topics existing when
+// the consumer joins the group are consumed from the latest offsets, unless
+// the group committed their offsets before. The topics created later are
+// consumed from the earliest offsets, so that their first records aren't
+// skipped. Since the topics are created by producing the records to them,
+// their offsets are committed once the first record is handled.
+func (c *clusterConsumer) run(ctx context.Context, initial []string) {
+	defer close(c.done)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	if len(initial) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.work(ctx, initial, broker.LastOffset)
+		}()
+	}
+
+	var added []string
+	stop := func() {}
+	defer func() { stop() }()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(topicsRefresh):
+		}
+
+		topics, err := c.broker.topics(c.pattern)
+		if err != nil {
+			continue
+		}
+		current := subtract(topics, initial)
+		if len(current) == 0 || equal(current, added) {
+			continue
+		}
+
+		// The record of the previously added topics that is being handled
+		// is handled before rejoining with the new topics.
+		stop()
+		added = current
+		wctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func(topics []string) {
+			defer close(done)
+			c.work(wctx, topics, broker.FirstOffset)
+		}(added)
+		stop = func() {
+			cancel()
+			<-done
+		}
+	}
+}
+
+// work consumes the topics until the context is canceled, rejoining the
+// group if the consumption fails.
+func (c *clusterConsumer) work(ctx context.Context, topics []string, start int64) {
+	for {
+		c.consume(ctx, topics, start)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(redeliveryDelay):
+		}
+	}
+}
+
+// consume consumes the topics until the context is canceled or the
+// consumption fails.
+func (c *clusterConsumer) consume(ctx context.Context, topics []string, start int64) {
+	r := broker.NewReader(broker.ReaderConfig{
+		Brokers:     c.broker.brokers,
+		GroupID:     c.group,
+		GroupTopics: topics,
+		StartOffset: start,
+	})
+	defer r.Close()
+
+	// Offsets are committed in the order the records are fetched, and the
+	// commits stop once a record isn't handled or its commit fails, so that
+	// the records following it are consumed again once rejoined.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p := messaging.NewPipeline(c.concurrency)
+	defer p.Close()
+	failed := false
+
+	for {
+		msg, err := r.FetchMessage(ctx)
+		if err != nil {
+			return
+		}
+
+		rec := Record{
+			Topic: msg.Topic,
+			Key:   msg.Key,
+			Value: msg.Value,
+		}
+		p.Handle(func() error {
+			// The record is handled again until the handler succeeds,
+			// and its offset is committed only then.
+			for c.handler(rec) != nil {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(redeliveryDelay):
+				}
+			}
+			return nil
+		}, func(err error) {
+			if failed {
+				return
+			}
+			if err == nil {
+				err = r.CommitMessages(ctx, msg)
+			}
+			if err != nil {
+				failed = true
+				cancel()
+			}
+		})
+	}
+}
+
+func (c *clusterConsumer) Close() error {
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+// subtract returns the sorted topics that are not in the other sorted
+// topics.
+func subtract(topics, other []string) []string {
+	ret := []string{}
+	for _, t := range topics {
+		i := sort.SearchStrings(other, t)
+		if i == len(other) || other[i] != t {
+			ret = append(ret, t)
+		}
+	}
+
+	return ret
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,60 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/vietquy/alpha/messaging"
+)
+
+const (
+	dlPrefix = "deadletters"
+	dlAll    = "all"
+
+	// Headers of the dead letters.
+	subjectHeader = "Alpha-Subject"
+	errorHeader   = "Alpha-Error"
+)
+
+var _ messaging.DeadLetterPublisher = (*deadLetterPublisher)(nil)
+
+type deadLetterPublisher struct {
+	broker Broker
+	topic  string
+}
+
+// NewDeadLetterPublisher returns Kafka dead letters publisher. Dead letters
+// are appended to the topic deadletters.<name>, carrying the topic of the
+// message and the failure reason in the headers.
+func NewDeadLetterPublisher(broker Broker, name string) messaging.DeadLetterPublisher {
+	return &deadLetterPublisher{
+		broker: broker,
+		topic:  fmt.Sprintf("%s.%s", dlPrefix, name),
+	}
+}
+
+func (pub *deadLetterPublisher) Publish(msg messaging.Message, reason error) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	topic := fmt.Sprintf("%s.%s", prjPrefix, msg.Project)
+	if msg.Subtopic != "" {
+		topic = fmt.Sprintf("%s.%s", topic, msg.Subtopic)
+	}
+
+	return pub.broker.Produce(deadLetter(pub.topic, topic, []byte(msg.Project), data, reason))
+}
+
+func deadLetter(dlTopic, topic string, key, data []byte, reason error) Record {
+	return Record{
+		Topic: dlTopic,
+		Key:   key,
+		Value: data,
+		Headers: map[string]string{
+			subjectHeader: topic,
+			errorHeader:   reason.Error(),
+		},
+	}
+}
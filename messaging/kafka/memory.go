@@ -0,0 +1,353 @@
+package kafka
+
+import (
+	"errors"
+	"hash/fnv"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/vietquy/alpha/messaging"
+)
+
+var errClosed = errors.New("broker closed")
+
+var _ Broker = (*memoryBroker)(nil)
+
+// memoryBroker is the in-process broker keeping the topics in memory. Each
+// topic is the single partition, so the partitions are assigned to the
+// group members by topic, the same as the Kafka range assignor does for the
+// single partition topics. Records are kept only until dispatched to all
+// the groups consuming their topic.
+type memoryBroker struct {
+	mu     sync.Mutex
+	closed bool
+	topics map[string]*topic
+	groups map[string]*group
+}
+
+type topic struct {
+	// base is the offset of the first record kept.
+	base int
+	recs []Record
+}
+
+type group struct {
+	members []*member
+	// patterns holds the patterns of the topics consumed by the group.
+	patterns map[string]*regexp.Regexp
+	// offsets holds the offset of the next record of the topic.
+	offsets map[string]int
+	// pending holds the records the members left without handling, that
+	// no remaining member consumes.
+	pending []Record
+}
+
+type member struct {
+	broker      *memoryBroker
+	group       string
+	pattern     *regexp.Regexp
+	concurrency int
+	handler     RecordHandler
+	mu          sync.Mutex
+	cond        *sync.Cond
+	queue       []Record
+	// next is the index of the queued record handed to the handler next,
+	// while the records preceding it are being handled.
+	next    int
+	closed  bool
+	stopped chan struct{}
+}
+
+// NewMemoryBroker returns the in-process broker, which is meant for running
+// the services without Kafka cluster, and for testing. Records are handled
+// in the order they are produced, and the records produced while the group
+// has no members are handled once the member joins.
+func NewMemoryBroker() Broker {
+	return &memoryBroker{
+		topics: make(map[string]*topic),
+		groups: make(map[string]*group),
+	}
+}
+
+func (mb *memoryBroker) Produce(rec Record) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if mb.closed {
+		return errClosed
+	}
+
+	t, ok := mb.topics[rec.Topic]
+	if !ok {
+		t = &topic{}
+		mb.topics[rec.Topic] = t
+	}
+	t.recs = append(t.recs, rec)
+	for _, g := range mb.groups {
+		mb.dispatch(g, rec.Topic)
+	}
+	mb.trim(rec.Topic)
+
+	return nil
+}
+
+func (mb *memoryBroker) Consume(pattern *regexp.Regexp, name string, concurrency int, handler RecordHandler) (Consumer, error) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if mb.closed {
+		return nil, errClosed
+	}
+
+	g, ok := mb.groups[name]
+	if !ok {
+		g = &group{
+			patterns: make(map[string]*regexp.Regexp),
+			offsets:  make(map[string]int),
+		}
+		mb.groups[name] = g
+	}
+	// Consumption of the topics the group didn't consume before starts
+	// from the latest offset.
+	for tn, t := range mb.topics {
+		if _, ok := g.offsets[tn]; !ok && !g.consumes(tn) && pattern.MatchString(tn) {
+			g.offsets[tn] = t.base + len(t.recs)
+		}
+	}
+	g.patterns[pattern.String()] = pattern
+
+	m := &member{
+		broker:      mb,
+		group:       name,
+		pattern:     pattern,
+		concurrency: concurrency,
+		handler:     handler,
+		stopped:     make(chan struct{}),
+	}
+	m.cond = sync.NewCond(&m.mu)
+	g.members = append(g.members, m)
+	go m.consume()
+
+	pending := g.pending
+	g.pending = nil
+	mb.redispatch(g, pending)
+	for topic := range mb.topics {
+		mb.dispatch(g, topic)
+	}
+
+	return m, nil
+}
+
+func (mb *memoryBroker) Close() error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	mb.closed = true
+	for _, g := range mb.groups {
+		for _, m := range g.members {
+			m.stop()
+		}
+		g.members = nil
+	}
+	mb.topics = make(map[string]*topic)
+
+	return nil
+}
+
+// consumes returns true if the group consumes the topic.
+func (g *group) consumes(topic string) bool {
+	for _, p := range g.patterns {
+		if p.MatchString(topic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// assignee returns the group member the topic is assigned to, or nil if no
+// member consumes it.
+func (g *group) assignee(topic string) *member {
+	var members []*member
+	for _, m := range g.members {
+		if m.pattern.MatchString(topic) {
+			members = append(members, m)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(topic))
+	return members[h.Sum32()%uint32(len(members))]
+}
+
+// dispatch queues the records of the topic following the group offset to
+// the group member the topic is assigned to.
+func (mb *memoryBroker) dispatch(g *group, name string) {
+	m := g.assignee(name)
+	if m == nil {
+		return
+	}
+
+	t := mb.topics[name]
+	offset, ok := g.offsets[name]
+	if !ok || offset < t.base {
+		offset = t.base
+	}
+	m.push(t.recs[offset-t.base:]...)
+	g.offsets[name] = t.base + len(t.recs)
+}
+
+// redispatch queues the records to the group members the topics are assigned
+// to, keeping the records no member consumes as pending.
+func (mb *memoryBroker) redispatch(g *group, recs []Record) {
+	for _, rec := range recs {
+		m := g.assignee(rec.Topic)
+		if m == nil {
+			g.pending = append(g.pending, rec)
+			continue
+		}
+		m.push(rec)
+	}
+}
+
+// trim drops the records of the topic dispatched to all the groups
+// consuming it.
+func (mb *memoryBroker) trim(name string) {
+	t := mb.topics[name]
+	end := t.base + len(t.recs)
+	min := end
+	for _, g := range mb.groups {
+		if !g.consumes(name) {
+			continue
+		}
+		offset, ok := g.offsets[name]
+		if !ok {
+			offset = t.base
+		}
+		if offset < min {
+			min = offset
+		}
+	}
+	if min <= t.base {
+		return
+	}
+
+	t.recs = append([]Record(nil), t.recs[min-t.base:]...)
+	t.base = min
+}
+
+// leave removes the member from the group, and dispatches the records the
+// member didn't handle to the remaining members of the group.
+func (mb *memoryBroker) leave(m *member) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	m.mu.Lock()
+	pending := m.queue
+	m.queue = nil
+	m.next = 0
+	m.mu.Unlock()
+	m.stop()
+
+	g, ok := mb.groups[m.group]
+	if !ok {
+		return
+	}
+	for i, gm := range g.members {
+		if gm == m {
+			g.members = append(g.members[:i], g.members[i+1:]...)
+			break
+		}
+	}
+
+	mb.redispatch(g, pending)
+}
+
+func (m *member) push(recs ...Record) {
+	if len(recs) == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queue = append(m.queue, recs...)
+	m.cond.Signal()
+}
+
+// take returns the first queued record that isn't being handled. Records
+// stay queued until acknowledged, so that they are dispatched to another
+// member if this one leaves before handling them.
+func (m *member) take() (Record, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for m.next == len(m.queue) && !m.closed {
+		m.cond.Wait()
+	}
+	if m.closed {
+		return Record{}, false
+	}
+
+	rec := m.queue[m.next]
+	m.next++
+	return rec, true
+}
+
+// ack removes the record at the head of the queue, since the records are
+// acknowledged in the order they are taken.
+func (m *member) ack() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed || m.next == 0 {
+		return
+	}
+	m.queue = m.queue[1:]
+	m.next--
+}
+
+func (m *member) consume() {
+	p := messaging.NewPipeline(m.concurrency)
+	defer p.Close()
+
+	for {
+		rec, ok := m.take()
+		if !ok {
+			return
+		}
+
+		p.Handle(func() error {
+			// The record is handled again until the handler succeeds,
+			// the same as the uncommitted records of Kafka consumers.
+			for m.handler(rec) != nil {
+				select {
+				case <-m.stopped:
+					return errClosed
+				case <-time.After(redeliveryDelay):
+				}
+			}
+			return nil
+		}, func(err error) {
+			if err == nil {
+				m.ack()
+			}
+		})
+	}
+}
+
+func (m *member) stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.stopped)
+	m.cond.Signal()
+}
+
+func (m *member) Close() error {
+	m.broker.leave(m)
+	return nil
+}
@@ -0,0 +1,224 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/gogo/protobuf/proto"
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+)
+
+const prjPrefix = "projects"
+
+// SubjectAllProjects represents subject to subscribe for all the projects.
+const SubjectAllProjects = messaging.SubjectAllProjects
+
+var (
+	errAlreadySubscribed = errors.New("already subscribed to topic")
+	errNotSubscribed     = errors.New("not subscribed")
+	errEmptyTopic        = errors.New("empty topic")
+	errInvalidTopic      = errors.New("invalid topic")
+)
+
+var _ messaging.PubSub = (*pubsub)(nil)
+
+// PubSub wraps messaging Publisher exposing
+// Close() method for Kafka connection.
+type PubSub interface {
+	messaging.PubSub
+	Close()
+}
+
+type pubsub struct {
+	broker        Broker
+	logger        log.Logger
+	mu            sync.Mutex
+	queue         string
+	maxDeliver    int
+	concurrency   int
+	subscriptions map[string]Consumer
+}
+
+// NewPubSub returns Kafka message publisher/subscriber. Messages are
+// published to the topics named the same as the NATS subjects, i.e.
+// projects.<project>.<subtopic>, keyed by the project. Subscribe method
+// accepts the topic in the NATS subject syntax, including the wildcards.
+// Parameter queue specifies the consumer group for the Subscribe method,
+// so that the message is handled by a single subscriber of the queue, the
+// same as NATS QueueSubscribe. If the queue is empty, each subscription
+// joins its own consumer group, so that it receives all the messages.
+// Messages are acknowledged once the handler returns nil, while otherwise
+// they are handled up to maxDeliver times, after which they are published
+// to the dead letters topic deadletters.<queue>, or deadletters.all if the
+// queue is empty. Each subscription handles up to concurrency messages at
+// the same time.
+func NewPubSub(broker Broker, queue string, maxDeliver, concurrency int, logger log.Logger) (PubSub, error) {
+	ret := &pubsub{
+		broker:        broker,
+		queue:         queue,
+		maxDeliver:    maxDeliver,
+		concurrency:   concurrency,
+		logger:        logger,
+		subscriptions: make(map[string]Consumer),
+	}
+	return ret, nil
+}
+
+func (ps *pubsub) Publish(topic string, msg messaging.Message) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", prjPrefix, topic)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+	rec := Record{
+		Topic: subject,
+		Key:   []byte(msg.Project),
+		Value: data,
+	}
+
+	return ps.broker.Produce(rec)
+}
+
+func (ps *pubsub) Subscribe(topic string, handler messaging.MessageHandler) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if _, ok := ps.subscriptions[topic]; ok {
+		return errAlreadySubscribed
+	}
+
+	re, err := pattern(topic)
+	if err != nil {
+		return err
+	}
+
+	group := ps.queue
+	if group == "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		group = id.String()
+	}
+
+	consumer, err := ps.broker.Consume(re, group, ps.concurrency, ps.kafkaHandler(handler))
+	if err != nil {
+		return err
+	}
+	ps.subscriptions[topic] = consumer
+	return nil
+}
+
+func (ps *pubsub) Unsubscribe(topic string) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	consumer, ok := ps.subscriptions[topic]
+	if !ok {
+		return errNotSubscribed
+	}
+
+	if err := consumer.Close(); err != nil {
+		return err
+	}
+
+	delete(ps.subscriptions, topic)
+	return nil
+}
+
+func (ps *pubsub) Close() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for topic, consumer := range ps.subscriptions {
+		if err := consumer.Close(); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to leave consumer group of topic %s: %s", topic, err))
+		}
+		delete(ps.subscriptions, topic)
+	}
+
+	if err := ps.broker.Close(); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to close Kafka connection: %s", err))
+	}
+}
+
+func (ps *pubsub) kafkaHandler(h messaging.MessageHandler) RecordHandler {
+	return func(rec Record) error {
+		var msg messaging.Message
+		if err := proto.Unmarshal(rec.Value, &msg); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+			return ps.deadLetter(rec, err)
+		}
+
+		for attempts := 1; ; attempts++ {
+			err := h(msg)
+			if err == nil {
+				return nil
+			}
+
+			ps.logger.Warn(fmt.Sprintf("Failed to handle message: %s", err))
+			if attempts >= ps.maxDeliver {
+				return ps.deadLetter(rec, err)
+			}
+			time.Sleep(redeliveryDelay)
+		}
+	}
+}
+
+// deadLetter appends the record to the dead letters topic along with the
+// original topic and the failure reason. The error is returned only if the
+// dead letter can't be appended, so that the record is handled again rather
+// than lost.
+func (ps *pubsub) deadLetter(rec Record, reason error) error {
+	name := ps.queue
+	if name == "" {
+		name = dlAll
+	}
+
+	dl := deadLetter(fmt.Sprintf("%s.%s", dlPrefix, name), rec.Topic, rec.Key, rec.Value, reason)
+	if err := ps.broker.Produce(dl); err != nil {
+		ps.logger.Error(fmt.Sprintf("Failed to publish dead letter: %s", err))
+		return err
+	}
+
+	return nil
+}
+
+// pattern returns the pattern matching the names of the topics the NATS
+// subject matches. Token wildcard * matches a single topic name token, while
+// the full wildcard > matches one or more trailing tokens.
+func pattern(subject string) (*regexp.Regexp, error) {
+	tokens := strings.Split(subject, ".")
+	for i, t := range tokens {
+		switch {
+		case t == "":
+			return nil, errInvalidTopic
+		case t == "*":
+			tokens[i] = `[^.]+`
+		case t == ">":
+			if i != len(tokens)-1 {
+				return nil, errInvalidTopic
+			}
+			tokens[i] = `.+`
+		default:
+			tokens[i] = regexp.QuoteMeta(t)
+		}
+	}
+
+	return regexp.Compile(fmt.Sprintf(`^%s$`, strings.Join(tokens, `\.`)))
+}
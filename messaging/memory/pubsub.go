@@ -0,0 +1,354 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+)
+
+const prjPrefix = "projects"
+
+// SubjectAllProjects represents subject to subscribe for all the projects.
+const SubjectAllProjects = messaging.SubjectAllProjects
+
+// Overflow policies of the subscription buffers.
+const (
+	// Block blocks the publisher until there is space in the buffer.
+	Block OverflowPolicy = "block"
+	// DropNewest drops the message being published.
+	DropNewest OverflowPolicy = "drop_newest"
+	// DropOldest drops the oldest buffered message.
+	DropOldest OverflowPolicy = "drop_oldest"
+)
+
+var (
+	errAlreadySubscribed = errors.New("already subscribed to topic")
+	errNotSubscribed     = errors.New("not subscribed")
+	errEmptyTopic        = errors.New("empty topic")
+	errInvalidTopic      = errors.New("invalid topic")
+	errClosed            = errors.New("broker closed")
+
+	// ErrOverflowPolicy indicates unknown overflow policy.
+	ErrOverflowPolicy = errors.New("unknown overflow policy")
+)
+
+// OverflowPolicy specifies what happens to the message published to the
+// subscription whose buffer is full.
+type OverflowPolicy string
+
+// ParseOverflowPolicy returns the overflow policy having the provided name.
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	switch p := OverflowPolicy(name); p {
+	case Block, DropNewest, DropOldest:
+		return p, nil
+	default:
+		return "", ErrOverflowPolicy
+	}
+}
+
+// Broker is the in-process message broker, that connects the publishers and
+// the subscribers running in the same process.
+type Broker interface {
+	// PubSub returns the publisher/subscriber using the broker. Parameter
+	// queue specifies the queue for the Subscribe method, so that the
+	// message is handled by a single subscriber of the queue subscribed to
+	// the same topic, the same as NATS QueueSubscribe. If the queue is empty,
+	// each subscriber receives all the messages. Each subscription handles
+	// up to concurrency messages at the same time.
+	PubSub(queue string, concurrency int) PubSub
+
+	// Close unsubscribes all the subscribers.
+	Close()
+}
+
+// PubSub wraps messaging PubSub exposing Close() method, which
+// unsubscribes from all the topics.
+type PubSub interface {
+	messaging.PubSub
+	Close()
+}
+
+var _ Broker = (*broker)(nil)
+
+type broker struct {
+	mu       sync.Mutex
+	closed   bool
+	size     int
+	overflow OverflowPolicy
+	logger   log.Logger
+	groups   map[string]*group
+}
+
+// group holds the subscriptions of the queue subscribed to the topic, or
+// the single subscription without the queue.
+type group struct {
+	tokens []string
+	subs   []*subscription
+	next   int
+}
+
+type subscription struct {
+	broker      *broker
+	key         string
+	concurrency int
+	handler     messaging.MessageHandler
+	msgs        chan messaging.Message
+	done        chan struct{}
+}
+
+// NewBroker returns the in-process message broker. Each subscription
+// buffers up to size messages, while the overflow policy specifies what
+// happens once its buffer is full.
+func NewBroker(size int, overflow OverflowPolicy, logger log.Logger) Broker {
+	return &broker{
+		size:     size,
+		overflow: overflow,
+		logger:   logger,
+		groups:   make(map[string]*group),
+	}
+}
+
+func (b *broker) PubSub(queue string, concurrency int) PubSub {
+	return &pubsub{
+		broker:        b,
+		queue:         queue,
+		concurrency:   concurrency,
+		subscriptions: make(map[string]*subscription),
+	}
+}
+
+func (b *broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for key, g := range b.groups {
+		for _, s := range g.subs {
+			close(s.done)
+		}
+		delete(b.groups, key)
+	}
+}
+
+func (b *broker) publish(subject string, msg messaging.Message) error {
+	tokens := strings.Split(subject, ".")
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errClosed
+	}
+	var subs []*subscription
+	for _, g := range b.groups {
+		if len(g.subs) == 0 || !match(g.tokens, tokens) {
+			continue
+		}
+		subs = append(subs, g.subs[g.next%len(g.subs)])
+		g.next++
+	}
+	b.mu.Unlock()
+
+	// Messages are buffered without holding the lock, so that the blocked
+	// publisher doesn't prevent the handlers from publishing.
+	for _, s := range subs {
+		s.push(msg)
+	}
+
+	return nil
+}
+
+func (b *broker) subscribe(topic, queue string, concurrency int, handler messaging.MessageHandler) (*subscription, error) {
+	tokens := strings.Split(topic, ".")
+	for i, t := range tokens {
+		if t == "" || (t == ">" && i != len(tokens)-1) {
+			return nil, errInvalidTopic
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	s := &subscription{
+		broker:      b,
+		concurrency: concurrency,
+		handler:     handler,
+		msgs:        make(chan messaging.Message, b.size),
+		done:        make(chan struct{}),
+	}
+
+	// Subscriptions without the queue form the groups of their own.
+	s.key = fmt.Sprintf("%s %s", topic, queue)
+	if queue == "" {
+		s.key = fmt.Sprintf("%s %p", topic, s)
+	}
+
+	g, ok := b.groups[s.key]
+	if !ok {
+		g = &group{tokens: tokens}
+		b.groups[s.key] = g
+	}
+	g.subs = append(g.subs, s)
+
+	go s.consume()
+
+	return s, nil
+}
+
+func (b *broker) unsubscribe(s *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	g, ok := b.groups[s.key]
+	if !ok {
+		return
+	}
+	for i, gs := range g.subs {
+		if gs == s {
+			g.subs = append(g.subs[:i], g.subs[i+1:]...)
+			close(s.done)
+			break
+		}
+	}
+	if len(g.subs) == 0 {
+		delete(b.groups, s.key)
+	}
+}
+
+func (s *subscription) push(msg messaging.Message) {
+	switch s.broker.overflow {
+	case DropNewest:
+		select {
+		case s.msgs <- msg:
+		case <-s.done:
+		default:
+			s.broker.logger.Warn(fmt.Sprintf("Dropped message published to project %s: buffer full", msg.Project))
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.msgs <- msg:
+				return
+			case <-s.done:
+				return
+			default:
+			}
+			select {
+			case old := <-s.msgs:
+				s.broker.logger.Warn(fmt.Sprintf("Dropped message published to project %s: buffer full", old.Project))
+			default:
+			}
+		}
+	default:
+		select {
+		case s.msgs <- msg:
+		case <-s.done:
+		}
+	}
+}
+
+func (s *subscription) consume() {
+	p := messaging.NewPipeline(s.concurrency)
+	defer p.Close()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg := <-s.msgs:
+			p.Handle(func() error {
+				return s.handler(msg)
+			}, func(err error) {
+				if err != nil {
+					s.broker.logger.Warn(fmt.Sprintf("Failed to handle message: %s", err))
+				}
+			})
+		}
+	}
+}
+
+// match reports whether the subject matches the topic, which can contain
+// the token wildcard * and the full wildcard > the same as NATS subjects.
+func match(topic, subject []string) bool {
+	for i, t := range topic {
+		if t == ">" {
+			return len(subject) > i
+		}
+		if i >= len(subject) || (t != "*" && t != subject[i]) {
+			return false
+		}
+	}
+
+	return len(topic) == len(subject)
+}
+
+var _ messaging.PubSub = (*pubsub)(nil)
+
+type pubsub struct {
+	broker        *broker
+	mu            sync.Mutex
+	queue         string
+	concurrency   int
+	subscriptions map[string]*subscription
+}
+
+func (ps *pubsub) Publish(topic string, msg messaging.Message) error {
+	subject := fmt.Sprintf("%s.%s", prjPrefix, topic)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+
+	return ps.broker.publish(subject, msg)
+}
+
+func (ps *pubsub) Subscribe(topic string, handler messaging.MessageHandler) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if _, ok := ps.subscriptions[topic]; ok {
+		return errAlreadySubscribed
+	}
+
+	s, err := ps.broker.subscribe(topic, ps.queue, ps.concurrency, handler)
+	if err != nil {
+		return err
+	}
+	ps.subscriptions[topic] = s
+	return nil
+}
+
+func (ps *pubsub) Unsubscribe(topic string) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	s, ok := ps.subscriptions[topic]
+	if !ok {
+		return errNotSubscribed
+	}
+
+	ps.broker.unsubscribe(s)
+	delete(ps.subscriptions, topic)
+	return nil
+}
+
+func (ps *pubsub) Close() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for topic, s := range ps.subscriptions {
+		ps.broker.unsubscribe(s)
+		delete(ps.subscriptions, topic)
+	}
+}
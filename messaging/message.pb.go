@@ -30,12 +30,13 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Message struct {
-	Project   string `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
-	Subtopic  string `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
-	Publisher string `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
-	Protocol  string `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
-	Payload   []byte `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
-	Created   int64  `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	Project   string            `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Subtopic  string            `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
+	Publisher string            `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Protocol  string            `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Payload   []byte            `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	Created   int64             `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	Headers   map[string]string `protobuf:"bytes,7,rep,name=headers" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *Message) Reset()                    { *m = Message{} }
@@ -85,6 +86,13 @@ func (m *Message) GetCreated() int64 {
 	return 0
 }
 
+func (m *Message) GetHeaders() map[string]string {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "messaging.Message")
 }
@@ -138,6 +146,23 @@ func (m *Message) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMessage(dAtA, i, uint64(m.Created))
 	}
+	if len(m.Headers) > 0 {
+		for k, _ := range m.Headers {
+			dAtA[i] = 0x3a
+			i++
+			v := m.Headers[k]
+			mapSize := 1 + len(k) + sovMessage(uint64(len(k))) + 1 + len(v) + sovMessage(uint64(len(v)))
+			i = encodeVarintMessage(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintMessage(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintMessage(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
 	return i, nil
 }
 
@@ -176,6 +201,14 @@ func (m *Message) Size() (n int) {
 	if m.Created != 0 {
 		n += 1 + sovMessage(uint64(m.Created))
 	}
+	if len(m.Headers) > 0 {
+		for k, v := range m.Headers {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMessage(uint64(len(k))) + 1 + len(v) + sovMessage(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMessage(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -387,6 +420,124 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Headers == nil {
+				m.Headers = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessage
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMessage(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Headers[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
@@ -516,17 +667,21 @@ var (
 func init() { proto.RegisterFile("messaging/message.proto", fileDescriptorMessage) }
 
 var fileDescriptorMessage = []byte{
-	// 184 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xcf, 0x4d, 0x2d, 0x2e,
-	0x4e, 0x4c, 0xcf, 0xcc, 0x4b, 0xd7, 0x87, 0xb0, 0x52, 0xf5, 0x0a, 0x8a, 0xf2, 0x4b, 0xf2, 0x85,
-	0x38, 0xe1, 0x12, 0x4a, 0x6b, 0x19, 0xb9, 0xd8, 0x7d, 0x21, 0x92, 0x42, 0x12, 0x5c, 0xec, 0x05,
-	0x45, 0xf9, 0x59, 0xa9, 0xc9, 0x25, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x30, 0xae, 0x90,
-	0x14, 0x17, 0x47, 0x71, 0x69, 0x52, 0x49, 0x7e, 0x41, 0x66, 0xb2, 0x04, 0x13, 0x58, 0x0a, 0xce,
-	0x17, 0x92, 0xe1, 0xe2, 0x2c, 0x28, 0x4d, 0xca, 0xc9, 0x2c, 0xce, 0x48, 0x2d, 0x92, 0x60, 0x06,
-	0x4b, 0x22, 0x04, 0x40, 0x3a, 0xc1, 0x76, 0x26, 0xe7, 0xe7, 0x48, 0xb0, 0x40, 0x74, 0xc2, 0xf8,
-	0x60, 0xfb, 0x12, 0x2b, 0x73, 0xf2, 0x13, 0x53, 0x24, 0x58, 0x15, 0x18, 0x35, 0x78, 0x82, 0x60,
-	0x5c, 0x90, 0x4c, 0x72, 0x51, 0x6a, 0x62, 0x49, 0x6a, 0x8a, 0x04, 0x9b, 0x02, 0xa3, 0x06, 0x73,
-	0x10, 0x8c, 0xeb, 0x24, 0x70, 0xe2, 0x91, 0x1c, 0xe3, 0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9,
-	0x31, 0xce, 0x78, 0x2c, 0xc7, 0x90, 0xc4, 0x06, 0x36, 0xcf, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff,
-	0x1b, 0x87, 0x8a, 0x74, 0xee, 0x00, 0x00, 0x00,
+	// 252 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x8f, 0x41, 0x4e, 0x84, 0x30,
+	0x14, 0x40, 0x2d, 0x38, 0x83, 0x7c, 0x67, 0x31, 0x69, 0x4c, 0x6c, 0x26, 0x06, 0x89, 0x2b, 0x56,
+	0x98, 0xe8, 0x46, 0x67, 0x69, 0x62, 0xe2, 0xc6, 0x0d, 0x37, 0x28, 0xf0, 0x33, 0x83, 0x22, 0x25,
+	0x6d, 0x31, 0xe1, 0x26, 0x1e, 0xc0, 0xc3, 0xb8, 0xf4, 0x08, 0x06, 0x2f, 0x62, 0x68, 0x29, 0xce,
+	0xae, 0xaf, 0xef, 0xff, 0xfc, 0x3c, 0x38, 0x7f, 0x43, 0xa5, 0xf8, 0xae, 0x6a, 0x76, 0xd7, 0xf6,
+	0x85, 0x69, 0x2b, 0x85, 0x16, 0x34, 0x9c, 0xc5, 0xd5, 0xa7, 0x07, 0xc1, 0xb3, 0x95, 0x94, 0x41,
+	0xd0, 0x4a, 0xf1, 0x82, 0x85, 0x66, 0x24, 0x26, 0x49, 0x98, 0x39, 0xa4, 0x1b, 0x38, 0x51, 0x5d,
+	0xae, 0x45, 0x5b, 0x15, 0xcc, 0x33, 0x6a, 0x66, 0x7a, 0x01, 0x61, 0xdb, 0xe5, 0x75, 0xa5, 0xf6,
+	0x28, 0x99, 0x6f, 0xe4, 0xff, 0xc7, 0xb8, 0x69, 0x6e, 0x16, 0xa2, 0x66, 0xc7, 0x76, 0xd3, 0xb1,
+	0xb9, 0xc7, 0xfb, 0x5a, 0xf0, 0x92, 0x2d, 0x62, 0x92, 0xac, 0x32, 0x87, 0xa3, 0x29, 0x24, 0x72,
+	0x8d, 0x25, 0x5b, 0xc6, 0x24, 0xf1, 0x33, 0x87, 0xf4, 0x1e, 0x82, 0x3d, 0xf2, 0x12, 0xa5, 0x62,
+	0x41, 0xec, 0x27, 0xa7, 0x37, 0x97, 0xe9, 0x1c, 0x93, 0x4e, 0x21, 0xe9, 0x93, 0x9d, 0x78, 0x6c,
+	0xb4, 0xec, 0x33, 0x37, 0xbf, 0xd9, 0xc2, 0xea, 0x50, 0xd0, 0x35, 0xf8, 0xaf, 0xd8, 0x4f, 0xa9,
+	0xe3, 0x93, 0x9e, 0xc1, 0xe2, 0x9d, 0xd7, 0x1d, 0x4e, 0x8d, 0x16, 0xb6, 0xde, 0x1d, 0x79, 0x58,
+	0x7f, 0x0d, 0x11, 0xf9, 0x1e, 0x22, 0xf2, 0x33, 0x44, 0xe4, 0xe3, 0x37, 0x3a, 0xca, 0x97, 0x26,
+	0xe3, 0xf6, 0x6f, 0x00, 0xa6, 0xbd, 0xdf, 0x68, 0x65, 0x01, 0x00, 0x00,
 }
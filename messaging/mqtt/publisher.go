@@ -2,6 +2,7 @@ package mqtt
 
 import (
 	"errors"
+	"strconv"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -32,7 +33,8 @@ func NewPublisher(address string, timeout time.Duration) (messaging.Publisher, e
 }
 
 func (pub publisher) Publish(topic string, msg messaging.Message) error {
-	token := pub.client.Publish(topic, qos, false, msg.Payload)
+	q, retain := options(msg)
+	token := pub.client.Publish(topic, q, retain, msg.Payload)
 	if token.Error() != nil {
 		return token.Error()
 	}
@@ -45,3 +47,21 @@ func (pub publisher) Publish(topic string, msg messaging.Message) error {
 	}
 	return nil
 }
+
+// options returns the quality of service level and the retain flag the
+// message is published with, taken from the message headers. Messages
+// without such headers are published with the default quality of service
+// level and are not retained.
+func options(msg messaging.Message) (byte, bool) {
+	q := byte(qos)
+	if v, err := strconv.ParseUint(msg.Header(messaging.QoSHeader), 10, 8); err == nil && v <= 2 {
+		q = byte(v)
+	}
+
+	retain, err := strconv.ParseBool(msg.Header(messaging.RetainHeader))
+	if err != nil {
+		retain = false
+	}
+
+	return q, retain
+}
@@ -0,0 +1,72 @@
+package nats
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	broker "github.com/nats-io/nats.go"
+	"github.com/vietquy/alpha/messaging"
+)
+
+const (
+	dlPrefix = "deadletters"
+
+	// Headers of the dead letters.
+	subjectHeader = "Alpha-Subject"
+	errorHeader   = "Alpha-Error"
+)
+
+var _ messaging.DeadLetterPublisher = (*deadLetterPublisher)(nil)
+
+// DeadLetterPublisher wraps messaging DeadLetterPublisher exposing
+// Close() method for NATS connection.
+type DeadLetterPublisher interface {
+	messaging.DeadLetterPublisher
+	Close()
+}
+
+type deadLetterPublisher struct {
+	conn    *broker.Conn
+	subject string
+}
+
+// NewDeadLetterPublisher returns NATS dead letters publisher. Dead letters
+// are published to the subject deadletters.<name>, carrying the subject of
+// the message and the failure reason in the headers.
+func NewDeadLetterPublisher(url, name string) (DeadLetterPublisher, error) {
+	conn, err := broker.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	ret := &deadLetterPublisher{
+		conn:    conn,
+		subject: fmt.Sprintf("%s.%s", dlPrefix, name),
+	}
+	return ret, nil
+}
+
+func (pub *deadLetterPublisher) Publish(msg messaging.Message, reason error) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", prjPrefix, msg.Project)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+
+	return pub.conn.PublishMsg(deadLetter(pub.subject, subject, data, reason))
+}
+
+func (pub *deadLetterPublisher) Close() {
+	pub.conn.Close()
+}
+
+func deadLetter(dlSubject, subject string, data []byte, reason error) *broker.Msg {
+	dl := broker.NewMsg(dlSubject)
+	dl.Data = data
+	dl.Header.Set(subjectHeader, subject)
+	dl.Header.Set(errorHeader, reason.Error())
+	return dl
+}
@@ -0,0 +1,376 @@
+package nats
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	broker "github.com/nats-io/nats.go"
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+)
+
+const (
+	// StreamAllProjects is the JetStream stream persisting the messages
+	// published to all projects.
+	StreamAllProjects = "projects"
+
+	// StreamDeadLetters is the JetStream stream persisting the messages that
+	// the consumers failed to handle within the maximum number of deliveries.
+	StreamDeadLetters = "deadletters"
+)
+
+var (
+	_ messaging.PubSub              = (*jsPubSub)(nil)
+	_ messaging.Publisher           = (*jsPublisher)(nil)
+	_ messaging.DeadLetterPublisher = (*jsDeadLetterPublisher)(nil)
+)
+
+// StreamLimits limits the messages kept by the JetStream streams. The
+// oldest messages are discarded once either limit is reached, while zero
+// values leave the streams unlimited. Messages of the project stream are
+// kept only until acknowledged by all the consumers, since the durable
+// consumers receive the messages published after they are created.
+type StreamLimits struct {
+	// MaxAge is the age beyond which the messages are discarded.
+	MaxAge time.Duration
+
+	// MaxBytes is the size of the stream beyond which the oldest messages
+	// are discarded.
+	MaxBytes int64
+}
+
+type jsPubSub struct {
+	conn          *broker.Conn
+	js            broker.JetStreamContext
+	logger        log.Logger
+	mu            sync.Mutex
+	durable       string
+	maxDeliver    int
+	concurrency   int
+	subscriptions map[string]subscription
+}
+
+// NewJetStreamPubSub returns NATS JetStream message publisher/subscriber,
+// creating the streams of the project messages and the dead letters if they
+// don't exist. Subscribe method creates the durable consumer, named after
+// the provided durable name, shared by all subscribers having the same name.
+// Messages are acknowledged once the handler returns nil, while otherwise
+// they are redelivered up to maxDeliver times, after which they are
+// published to the dead letters stream, to the subject
+// deadletters.<durable name>. Each subscription handles up to concurrency
+// messages at the same time.
+func NewJetStreamPubSub(url, durable string, maxDeliver, concurrency int, limits StreamLimits, logger log.Logger) (PubSub, error) {
+	conn, js, err := connectJetStream(url, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &jsPubSub{
+		conn:          conn,
+		js:            js,
+		logger:        logger,
+		durable:       durable,
+		maxDeliver:    maxDeliver,
+		concurrency:   concurrency,
+		subscriptions: make(map[string]subscription),
+	}
+	return ret, nil
+}
+
+// NewJetStreamPublisher returns NATS JetStream message publisher, creating
+// the streams the same as NewJetStreamPubSub. Publish returns once the
+// message is persisted by the stream.
+func NewJetStreamPublisher(url string, limits StreamLimits) (Publisher, error) {
+	conn, js, err := connectJetStream(url, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsPublisher{conn: conn, js: js}, nil
+}
+
+// NewJetStreamDeadLetterPublisher returns NATS JetStream dead letters
+// publisher, publishing the same dead letters as NewDeadLetterPublisher to
+// the dead letters stream.
+func NewJetStreamDeadLetterPublisher(url, name string, limits StreamLimits) (DeadLetterPublisher, error) {
+	conn, js, err := connectJetStream(url, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &jsDeadLetterPublisher{
+		conn:    conn,
+		js:      js,
+		subject: fmt.Sprintf("%s.%s", dlPrefix, name),
+	}
+	return ret, nil
+}
+
+// connectJetStream connects to NATS, creating the streams of the project
+// messages and the dead letters, or updating their limits if they exist.
+func connectJetStream(url string, limits StreamLimits) (*broker.Conn, broker.JetStreamContext, error) {
+	conn, err := broker.Connect(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	streams := []broker.StreamConfig{
+		{
+			Name:      StreamAllProjects,
+			Subjects:  []string{SubjectAllProjects},
+			Retention: broker.InterestPolicy,
+		},
+		{
+			Name:      StreamDeadLetters,
+			Subjects:  []string{fmt.Sprintf("%s.>", dlPrefix)},
+			Retention: broker.LimitsPolicy,
+		},
+	}
+	for _, cfg := range streams {
+		cfg.Storage = broker.FileStorage
+		cfg.MaxAge = limits.MaxAge
+		cfg.MaxBytes = limits.bytes()
+		if err := createStream(js, cfg); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+
+	return conn, js, nil
+}
+
+func createStream(js broker.JetStreamContext, cfg broker.StreamConfig) error {
+	info, err := js.StreamInfo(cfg.Name)
+	switch {
+	case err == broker.ErrStreamNotFound:
+		if _, err := js.AddStream(&cfg); err != nil && err != broker.ErrStreamNameAlreadyInUse {
+			return err
+		}
+		return nil
+	case err != nil:
+		return err
+	}
+
+	// Retention of the existing stream can't be changed, so that only the
+	// limits are updated.
+	if info.Config.MaxAge == cfg.MaxAge && info.Config.MaxBytes == cfg.MaxBytes {
+		return nil
+	}
+	update := info.Config
+	update.MaxAge = cfg.MaxAge
+	update.MaxBytes = cfg.MaxBytes
+	if _, err := js.UpdateStream(&update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bytes returns the stream size limit, where -1 leaves the stream unlimited.
+func (l StreamLimits) bytes() int64 {
+	if l.MaxBytes <= 0 {
+		return -1
+	}
+
+	return l.MaxBytes
+}
+
+func (ps *jsPubSub) Publish(topic string, msg messaging.Message) error {
+	return publish(ps.js, topic, msg)
+}
+
+func (ps *jsPubSub) Subscribe(topic string, handler messaging.MessageHandler) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if _, ok := ps.subscriptions[topic]; ok {
+		return errAlreadySubscribed
+	}
+
+	durable := ps.consumer(topic)
+	opts := []broker.SubOpt{
+		broker.Durable(durable),
+		broker.BindStream(StreamAllProjects),
+		broker.DeliverNew(),
+		broker.ManualAck(),
+		broker.AckExplicit(),
+		broker.MaxDeliver(ps.maxDeliver),
+	}
+	if ps.concurrency > 1 {
+		// The messages being handled are not acknowledged yet, so that
+		// the consumer delivers the following ones meanwhile.
+		opts = append(opts, broker.MaxAckPending(ps.concurrency))
+	}
+	p := messaging.NewPipeline(ps.concurrency)
+	sub, err := ps.js.QueueSubscribe(topic, durable, ps.jsHandler(handler, p), opts...)
+	if err != nil {
+		return err
+	}
+	ps.subscriptions[topic] = subscription{sub: sub, pipeline: p}
+	return nil
+}
+
+func (ps *jsPubSub) Unsubscribe(topic string) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	s, ok := ps.subscriptions[topic]
+	if !ok {
+		return errNotSubscribed
+	}
+
+	// The durable consumer is kept, so that the messages published in the
+	// meantime are delivered once subscribed again.
+	if err := s.sub.Drain(); err != nil {
+		return err
+	}
+	s.pipeline.Close()
+
+	delete(ps.subscriptions, topic)
+	return nil
+}
+
+func (ps *jsPubSub) Close() {
+	ps.conn.Close()
+}
+
+// consumer returns the name of the durable consumer of the topic. Consumers
+// of all projects are named after the durable name, while the others get
+// the topic appended, since the consumer names can't contain the subject
+// tokens separators and wildcards.
+func (ps *jsPubSub) consumer(topic string) string {
+	if topic == SubjectAllProjects {
+		return ps.durable
+	}
+
+	r := strings.NewReplacer(".", "_", "*", "any", ">", "all")
+	return fmt.Sprintf("%s_%s", ps.durable, r.Replace(topic))
+}
+
+func (ps *jsPubSub) jsHandler(h messaging.MessageHandler, p *messaging.Pipeline) broker.MsgHandler {
+	return func(m *broker.Msg) {
+		var msg messaging.Message
+		if err := proto.Unmarshal(m.Data, &msg); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+			ps.deadLetter(m, err)
+			return
+		}
+
+		p.Handle(func() error {
+			return h(msg)
+		}, func(err error) {
+			ps.ack(m, err)
+		})
+	}
+}
+
+// ack acknowledges the handled message, or rejects it so that it's
+// redelivered, unless the maximum number of deliveries is reached.
+func (ps *jsPubSub) ack(m *broker.Msg, err error) {
+	if err == nil {
+		if err := m.Ack(); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to acknowledge message: %s", err))
+		}
+		return
+	}
+
+	ps.logger.Warn(fmt.Sprintf("Failed to handle message: %s", err))
+	meta, mErr := m.Metadata()
+	if mErr == nil && meta.NumDelivered >= uint64(ps.maxDeliver) {
+		ps.deadLetter(m, err)
+		return
+	}
+	if err := m.Nak(); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to reject message: %s", err))
+	}
+}
+
+// deadLetter publishes the message to the dead letters stream along with the
+// original subject and the failure reason, and terminates its redelivery.
+func (ps *jsPubSub) deadLetter(m *broker.Msg, reason error) {
+	dl := deadLetter(fmt.Sprintf("%s.%s", dlPrefix, ps.durable), m.Subject, m.Data, reason)
+	if _, err := ps.js.PublishMsg(dl); err != nil {
+		ps.logger.Error(fmt.Sprintf("Failed to publish dead letter: %s", err))
+		// The message is redelivered, unless the maximum number of
+		// deliveries is reached, rather than lost.
+		m.Nak()
+		return
+	}
+
+	if err := m.Term(); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to terminate message redelivery: %s", err))
+	}
+}
+
+type jsPublisher struct {
+	conn *broker.Conn
+	js   broker.JetStreamContext
+}
+
+func (pub *jsPublisher) Publish(topic string, msg messaging.Message) error {
+	return publish(pub.js, topic, msg)
+}
+
+func (pub *jsPublisher) Close() {
+	pub.conn.Close()
+}
+
+type jsDeadLetterPublisher struct {
+	conn    *broker.Conn
+	js      broker.JetStreamContext
+	subject string
+}
+
+func (pub *jsDeadLetterPublisher) Publish(msg messaging.Message, reason error) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", prjPrefix, msg.Project)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+	if _, err := pub.js.PublishMsg(deadLetter(pub.subject, subject, data, reason)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pub *jsDeadLetterPublisher) Close() {
+	pub.conn.Close()
+}
+
+// publish publishes the message to the project stream, returning once the
+// stream acknowledges it.
+func publish(js broker.JetStreamContext, topic string, msg messaging.Message) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", prjPrefix, topic)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+	if _, err := js.Publish(subject, data); err != nil {
+		return err
+	}
+
+	return nil
+}
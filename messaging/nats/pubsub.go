@@ -15,7 +15,7 @@ import (
 const prjPrefix = "projects"
 
 // SubjectAllProjects represents subject to subscribe for all the projects.
-const SubjectAllProjects = "projects.>"
+const SubjectAllProjects = messaging.SubjectAllProjects
 
 var (
 	errAlreadySubscribed = errors.New("already subscribed to topic")
@@ -37,7 +37,15 @@ type pubsub struct {
 	logger        log.Logger
 	mu            sync.Mutex
 	queue         string
-	subscriptions map[string]*broker.Subscription
+	concurrency   int
+	subscriptions map[string]subscription
+}
+
+// subscription holds the NATS subscription along with the pipeline handling
+// its messages.
+type subscription struct {
+	sub      *broker.Subscription
+	pipeline *messaging.Pipeline
 }
 
 // NewPubSub returns NATS message publisher/subscriber.
@@ -47,7 +55,9 @@ type pubsub struct {
 // from ordinary subscribe. For more information, please take a look
 // here: https://docs.nats.io/developing-with-nats/receiving/queues.
 // If the queue is empty, Subscribe will be used.
-func NewPubSub(url, queue string, logger log.Logger) (PubSub, error) {
+// Parameter concurrency specifies the number of messages each subscription
+// handles at the same time.
+func NewPubSub(url, queue string, concurrency int, logger log.Logger) (PubSub, error) {
 	conn, err := broker.Connect(url)
 	if err != nil {
 		return nil, err
@@ -55,8 +65,9 @@ func NewPubSub(url, queue string, logger log.Logger) (PubSub, error) {
 	ret := &pubsub{
 		conn:          conn,
 		queue:         queue,
+		concurrency:   concurrency,
 		logger:        logger,
-		subscriptions: make(map[string]*broker.Subscription),
+		subscriptions: make(map[string]subscription),
 	}
 	return ret, nil
 }
@@ -87,21 +98,22 @@ func (ps *pubsub) Subscribe(topic string, handler messaging.MessageHandler) erro
 	if _, ok := ps.subscriptions[topic]; ok {
 		return errAlreadySubscribed
 	}
-	nh := ps.natsHandler(handler)
+	p := messaging.NewPipeline(ps.concurrency)
+	nh := ps.natsHandler(handler, p)
 
 	if ps.queue != "" {
 		sub, err := ps.conn.QueueSubscribe(topic, ps.queue, nh)
 		if err != nil {
 			return err
 		}
-		ps.subscriptions[topic] = sub
+		ps.subscriptions[topic] = subscription{sub: sub, pipeline: p}
 		return nil
 	}
 	sub, err := ps.conn.Subscribe(topic, nh)
 	if err != nil {
 		return err
 	}
-	ps.subscriptions[topic] = sub
+	ps.subscriptions[topic] = subscription{sub: sub, pipeline: p}
 	return nil
 }
 
@@ -112,14 +124,15 @@ func (ps *pubsub) Unsubscribe(topic string) error {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 
-	sub, ok := ps.subscriptions[topic]
+	s, ok := ps.subscriptions[topic]
 	if !ok {
 		return errNotSubscribed
 	}
 
-	if err := sub.Unsubscribe(); err != nil {
+	if err := s.sub.Unsubscribe(); err != nil {
 		return err
 	}
+	s.pipeline.Close()
 
 	delete(ps.subscriptions, topic)
 	return nil
@@ -129,15 +142,19 @@ func (ps *pubsub) Close() {
 	ps.conn.Close()
 }
 
-func (ps *pubsub) natsHandler(h messaging.MessageHandler) broker.MsgHandler {
+func (ps *pubsub) natsHandler(h messaging.MessageHandler, p *messaging.Pipeline) broker.MsgHandler {
 	return func(m *broker.Msg) {
 		var msg messaging.Message
 		if err := proto.Unmarshal(m.Data, &msg); err != nil {
 			ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
 			return
 		}
-		if err := h(msg); err != nil {
-			ps.logger.Warn(fmt.Sprintf("Failed to handle message: %s", err))
-		}
+		p.Handle(func() error {
+			return h(msg)
+		}, func(err error) {
+			if err != nil {
+				ps.logger.Warn(fmt.Sprintf("Failed to handle message: %s", err))
+			}
+		})
 	}
 }
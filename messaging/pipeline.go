@@ -0,0 +1,90 @@
+package messaging
+
+import "sync"
+
+// Pipeline handles the received messages concurrently, while acknowledging
+// them in the order they are received, so that the brokers committing the
+// offset of the last handled message, such as Kafka, don't commit the
+// offsets of the messages still being handled. Subscriptions handling the
+// messages concurrently let the handlers wait for the other messages, e.g.
+// to write them in batches, without blocking the subscription.
+type Pipeline struct {
+	mu      sync.Mutex
+	closed  bool
+	size    int
+	slots   chan struct{}
+	pending chan *pipelined
+	done    chan struct{}
+}
+
+type pipelined struct {
+	ack  func(err error)
+	err  error
+	done chan struct{}
+}
+
+// NewPipeline returns the Pipeline handling up to size messages at the same
+// time. Pipeline of size 1 or less handles the messages one at a time, in
+// the goroutine receiving them.
+func NewPipeline(size int) *Pipeline {
+	p := &Pipeline{
+		size: size,
+		done: make(chan struct{}),
+	}
+	if size <= 1 {
+		close(p.done)
+		return p
+	}
+
+	p.slots = make(chan struct{}, size)
+	p.pending = make(chan *pipelined, size)
+	go p.acknowledge()
+
+	return p
+}
+
+// Handle calls handle in a new goroutine, and then ack with the error handle
+// returns, once the messages handled before are acknowledged. Handle blocks
+// while the pipeline is full. Once the pipeline is closed, the message is
+// handled and acknowledged before Handle returns.
+func (p *Pipeline) Handle(handle func() error, ack func(err error)) {
+	p.mu.Lock()
+	if p.size <= 1 || p.closed {
+		p.mu.Unlock()
+		ack(handle())
+		return
+	}
+	defer p.mu.Unlock()
+
+	p.slots <- struct{}{}
+	pm := &pipelined{
+		ack:  ack,
+		done: make(chan struct{}),
+	}
+	p.pending <- pm
+	go func() {
+		pm.err = handle()
+		close(pm.done)
+	}()
+}
+
+// Close waits for the messages being handled to be acknowledged.
+func (p *Pipeline) Close() {
+	p.mu.Lock()
+	if !p.closed && p.size > 1 {
+		close(p.pending)
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	<-p.done
+}
+
+func (p *Pipeline) acknowledge() {
+	defer close(p.done)
+	for pm := range p.pending {
+		<-pm.done
+		pm.ack(pm.err)
+		<-p.slots
+	}
+}
@@ -0,0 +1,114 @@
+package messaging
+
+import (
+	"time"
+
+	"github.com/vietquy/alpha/errors"
+)
+
+// RetryPolicy specifies how many times the message handling is attempted,
+// and how long to wait between the attempts. The wait starts from Backoff
+// and is doubled after each failed attempt, up to MaxBackoff. If Retryable
+// is set, only the errors it returns true for are retried, while the others
+// fail the handling at once.
+type RetryPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Retryable  func(err error) bool
+}
+
+// ShouldRetry reports whether the handling failed with the error is retried
+// after the provided number of attempts.
+func (rp RetryPolicy) ShouldRetry(err error, attempts int) bool {
+	if attempts >= rp.Attempts {
+		return false
+	}
+
+	return rp.Retryable == nil || rp.Retryable(err)
+}
+
+// Delay returns the wait after the provided number of failed attempts.
+func (rp RetryPolicy) Delay(attempts int) time.Duration {
+	delay := rp.Backoff
+	for i := 1; i < attempts && delay < rp.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > rp.MaxBackoff {
+		return rp.MaxBackoff
+	}
+
+	return delay
+}
+
+// DeadLetterPublisher specifies the API of publishing the messages that
+// couldn't be handled, along with the reason of the last failure.
+type DeadLetterPublisher interface {
+	// Publish publishes the message that failed to be handled.
+	Publish(msg Message, reason error) error
+}
+
+// ErrDeadLetter indicates the failure to publish the dead letter.
+var ErrDeadLetter = errors.New("failed to publish dead letter")
+
+var _ Subscriber = (*retrySubscriber)(nil)
+
+type retrySubscriber struct {
+	sub         Subscriber
+	policy      RetryPolicy
+	deadLetters DeadLetterPublisher
+}
+
+// NewRetrySubscriber returns the subscriber that retries the handling of the
+// received messages according to the retry policy, and publishes the message
+// to the dead letters once all attempts fail, or at once if the error is not
+// retryable. The wrapped subscriber receives the error only if the dead
+// letter can't be published, or the last retryable handling error if the
+// dead letters publisher is nil, so the retries and the dead letters work the
+// same regardless of the broker. Without the dead letters publisher, the
+// messages failing with the errors that are not retryable are dropped. Note
+// that the handler blocks while waiting for the next attempt.
+func NewRetrySubscriber(sub Subscriber, policy RetryPolicy, deadLetters DeadLetterPublisher) Subscriber {
+	return retrySubscriber{
+		sub:         sub,
+		policy:      policy,
+		deadLetters: deadLetters,
+	}
+}
+
+func (rs retrySubscriber) Subscribe(topic string, handler MessageHandler) error {
+	return rs.sub.Subscribe(topic, rs.handle(handler))
+}
+
+func (rs retrySubscriber) Unsubscribe(topic string) error {
+	return rs.sub.Unsubscribe(topic)
+}
+
+func (rs retrySubscriber) handle(h MessageHandler) MessageHandler {
+	return func(msg Message) error {
+		attempts := 0
+		for {
+			attempts++
+			err := h(msg)
+			if err == nil {
+				return nil
+			}
+			if rs.policy.ShouldRetry(err, attempts) {
+				time.Sleep(rs.policy.Delay(attempts))
+				continue
+			}
+
+			if rs.deadLetters == nil {
+				if rs.policy.Retryable != nil && !rs.policy.Retryable(err) {
+					return nil
+				}
+				return err
+			}
+			if dlErr := rs.deadLetters.Publish(msg, err); dlErr != nil {
+				return errors.Wrap(ErrDeadLetter, dlErr)
+			}
+
+			return nil
+		}
+	}
+}
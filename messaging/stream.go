@@ -0,0 +1,17 @@
+package messaging
+
+import "context"
+
+// SubjectAllProjects represents subject to subscribe for all the projects.
+const SubjectAllProjects = "projects.>"
+
+// ServiceHandler represents the service method handling the Message.
+type ServiceHandler func(ctx context.Context, msg Message) error
+
+// SubscribeAllProjects subscribes the service handler to the messages
+// published to all projects.
+func SubscribeAllProjects(sub Subscriber, handler ServiceHandler) error {
+	return sub.Subscribe(SubjectAllProjects, func(msg Message) error {
+		return handler(context.Background(), msg)
+	})
+}
@@ -48,11 +48,12 @@ func (f forwarder) handle(pub messaging.Publisher) messaging.MessageHandler {
 		if msg.Subtopic != "" {
 			topic += "/" + strings.ReplaceAll(msg.Subtopic, ".", "/")
 		}
-		go func() {
-			if err := pub.Publish(topic, msg); err != nil {
-				f.logger.Warn(fmt.Sprintf("Failed to forward message: %s", err))
-			}
-		}()
+		// Publish error is returned, so that the subscriber can retry
+		// forwarding or publish the message to the dead letters.
+		if err := pub.Publish(topic, msg); err != nil {
+			f.logger.Warn(fmt.Sprintf("Failed to forward message: %s", err))
+			return err
+		}
 		return nil
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,7 +17,16 @@ import (
 
 var _ session.Handler = (*handler)(nil)
 
-const protocol = "mqtt"
+const (
+	protocol = "mqtt"
+
+	// ctSep separates the subtopic from the content type of the messages
+	// published to the topic.
+	ctSep = "/ct/"
+	// ctPrefix is the type of the content type given without one, e.g.
+	// senml+json.
+	ctPrefix = "application/"
+)
 
 var (
 	projectRegExp         = regexp.MustCompile(`^\/?projects\/([\w\-]+)\/messages(\/[^?]*)?(\?.*)?$`)
@@ -54,6 +64,17 @@ func (h *handler) AuthConnect(c *session.Client) error {
 		return errInvalidConnect
 	}
 
+	// The thing authenticated by the client certificate is identified by
+	// the common name of the certificate, instead of the key.
+	if c.Cert != nil {
+		thingID := c.Cert.Subject.CommonName
+		if thingID == "" || (c.Username != "" && c.Username != thingID) {
+			return errUnauthorizedAccess
+		}
+		c.Username = thingID
+		return nil
+	}
+
 	t := &alpha.Token{
 		Value: string(c.Password),
 	}
@@ -80,7 +101,13 @@ func (h *handler) AuthPublish(c *session.Client, topic *string, payload *[]byte)
 		return errNilTopicPub
 	}
 
-	return h.authAccess(c.Username, *topic)
+	ar, err := h.accessReq(c.Username, *topic)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.tc.CanPublish(context.TODO(), ar)
+	return err
 }
 
 // AuthSubscribe is called on device publish,
@@ -94,10 +121,14 @@ func (h *handler) AuthSubscribe(c *session.Client, topics *[]string) error {
 	}
 
 	for _, v := range *topics {
-		if err := h.authAccess(c.Username, v); err != nil {
+		ar, err := h.accessReq(c.Username, topicFilter(v))
+		if err != nil {
 			return err
 		}
 
+		if _, err := h.tc.CanSubscribe(context.TODO(), ar); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -113,7 +144,7 @@ func (h *handler) Connect(c *session.Client) {
 }
 
 // Publish - after client successfully published
-func (h *handler) Publish(c *session.Client, topic *string, payload *[]byte) {
+func (h *handler) Publish(c *session.Client, topic *string, payload *[]byte, qos byte, retain bool) {
 	if c == nil {
 		h.logger.Error("Nil client publish")
 		return
@@ -129,7 +160,7 @@ func (h *handler) Publish(c *session.Client, topic *string, payload *[]byte) {
 	}
 
 	projectID := projectParts[1]
-	subtopic := projectParts[2]
+	subtopic, contentType := splitContentType(projectParts[2])
 
 	subtopic, err := parseSubtopic(subtopic)
 	if err != nil {
@@ -144,6 +175,13 @@ func (h *handler) Publish(c *session.Client, topic *string, payload *[]byte) {
 		Publisher: c.Username,
 		Payload:   *payload,
 		Created:   time.Now().UnixNano(),
+		Headers: map[string]string{
+			messaging.QoSHeader:    strconv.Itoa(int(qos)),
+			messaging.RetainHeader: strconv.FormatBool(retain),
+		},
+	}
+	if contentType != "" {
+		msg.Headers[messaging.ContentTypeHeader] = contentType
 	}
 
 	for _, pub := range h.publishers {
@@ -180,27 +218,69 @@ func (h *handler) Disconnect(c *session.Client) {
 	h.logger.Info("Disconnect - Client with ID: " + c.ID + " and username " + c.Username + " disconnected")
 }
 
-func (h *handler) authAccess(username string, topic string) error {
+// accessReq builds the access request of the thing with the provided
+// username for the project and the subtopic the topic refers to.
+func (h *handler) accessReq(username string, topic string) (*alpha.AccessByIDReq, error) {
 	// Topics are in the format:
 	// projects/<project_id>/messages/<subtopic>/.../ct/<content_type>
 	if !projectRegExp.Match([]byte(topic)) {
 		h.logger.Info("Malformed topic: " + topic)
-		return errMalformedTopic
+		return nil, errMalformedTopic
 	}
 
 	projectParts := projectRegExp.FindStringSubmatch(topic)
 	if len(projectParts) < 1 {
-		return errMalformedData
+		return nil, errMalformedData
 	}
 
-	projectID := projectParts[1]
+	subtopic, _ := splitContentType(projectParts[2])
+	subtopic, err := parseSubtopic(subtopic)
+	if err != nil {
+		return nil, err
+	}
 
 	ar := &alpha.AccessByIDReq{
-		ThingID: username,
-		ProjectID:  projectID,
+		ThingID:   username,
+		ProjectID: projectParts[1],
+		Subtopic:  subtopic,
 	}
-	_, err := h.tc.CanAccessByID(context.TODO(), ar)
-	return err
+	return ar, nil
+}
+
+// topicFilter replaces the MQTT wildcards of the topic filter with the
+// wildcards of the subtopic patterns, so that the subscriptions to the
+// subtopics matching several subtopics are allowed only by the patterns
+// matching them, e.g. the topic filter projects/1/messages/room/+ gives the
+// room.* subtopic.
+func topicFilter(topic string) string {
+	levels := strings.Split(topic, "/")
+	for i, level := range levels {
+		switch level {
+		case "+":
+			levels[i] = "*"
+		case "#":
+			levels[i] = ">"
+		}
+	}
+
+	return strings.Join(levels, "/")
+}
+
+// splitContentType splits the content type suffix off the subtopic, e.g.
+// the subtopic /room/1/ct/senml+json gives the subtopic /room/1 and the
+// application/senml+json content type.
+func splitContentType(subtopic string) (string, string) {
+	i := strings.LastIndex(subtopic, ctSep)
+	if i < 0 {
+		return subtopic, ""
+	}
+
+	ct, err := url.PathUnescape(subtopic[i+len(ctSep):])
+	if err != nil || ct == "" || strings.Contains(ct, "/") {
+		return subtopic, ""
+	}
+
+	return subtopic[:i], ctPrefix + ct
 }
 
 func parseSubtopic(subtopic string) (string, error) {
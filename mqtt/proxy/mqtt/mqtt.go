@@ -1,6 +1,8 @@
 package mqtt
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"net"
@@ -51,7 +53,13 @@ func (p Proxy) handle(inbound net.Conn) {
 	}
 	defer p.close(outbound)
 
-	s := session.New(inbound, outbound, p.handler, p.logger)
+	cert, err := clientCert(inbound)
+	if err != nil {
+		p.logger.Warn("TLS handshake failed due to: " + err.Error())
+		return
+	}
+
+	s := session.New(inbound, outbound, p.handler, p.logger, cert)
 
 	if err = s.Stream(); !errors.Contains(err, io.EOF) {
 		p.logger.Warn("Broken connection for client: " + s.Client.ID + " with error: " + err.Error())
@@ -73,6 +81,41 @@ func (p Proxy) Proxy() error {
 	return nil
 }
 
+// ProxyTLS of the server using the provided TLS config, this will block.
+func (p Proxy) ProxyTLS(cfg *tls.Config) error {
+	l, err := tls.Listen("tcp", p.address, cfg)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	// Acceptor loop
+	p.accept(l)
+
+	p.logger.Info("Server Exiting...")
+	return nil
+}
+
+// clientCert returns the verified client certificate of the TLS connection,
+// or nil if the connection is not encrypted or the client didn't provide one.
+func clientCert(conn net.Conn) (*x509.Certificate, error) {
+	tc, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, nil
+	}
+
+	if err := tc.Handshake(); err != nil {
+		return nil, err
+	}
+
+	state := tc.ConnectionState()
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+
+	return state.VerifiedChains[0][0], nil
+}
+
 func (p Proxy) close(conn net.Conn) {
 	if err := conn.Close(); err != nil {
 		p.logger.Warn(fmt.Sprintf("Error closing connection %s", err.Error()))
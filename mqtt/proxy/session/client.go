@@ -1,8 +1,13 @@
 package session
 
+import "crypto/x509"
+
 // Client stores MQTT client data.
 type Client struct {
 	ID       string
 	Username string
 	Password []byte
+	// Cert is the verified client certificate, if the client provided one
+	// over TLS.
+	Cert *x509.Certificate
 }
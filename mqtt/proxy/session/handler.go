@@ -18,7 +18,9 @@ type Handler interface {
 	Connect(client *Client)
 
 	// After client successfully published
-	Publish(client *Client, topic *string, payload *[]byte)
+	// QoS and retain are the quality of service level and the retain flag of
+	// the published packet
+	Publish(client *Client, topic *string, payload *[]byte, qos byte, retain bool)
 
 	// After client successfully subscribed
 	Subscribe(client *Client, topics *[]string)
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"crypto/x509"
 	"net"
 
 	"github.com/eclipse/paho.mqtt.golang/packets"
@@ -26,16 +27,19 @@ type Session struct {
 	inbound  net.Conn
 	outbound net.Conn
 	handler  Handler
+	cert     *x509.Certificate
 	Client   Client
 }
 
-// New creates a new Session.
-func New(inbound, outbound net.Conn, handler Handler, logger logger.Logger) *Session {
+// New creates a new Session. The certificate is the verified client
+// certificate, or nil if the client didn't provide one.
+func New(inbound, outbound net.Conn, handler Handler, logger logger.Logger, cert *x509.Certificate) *Session {
 	return &Session{
 		logger:   logger,
 		inbound:  inbound,
 		outbound: outbound,
 		handler:  handler,
+		cert:     cert,
 	}
 }
 
@@ -92,6 +96,7 @@ func (s *Session) authorize(pkt packets.ControlPacket) error {
 			ID:       p.ClientIdentifier,
 			Username: p.Username,
 			Password: p.Password,
+			Cert:     s.cert,
 		}
 		if err := s.handler.AuthConnect(&s.Client); err != nil {
 			return err
@@ -116,7 +121,7 @@ func (s *Session) notify(pkt packets.ControlPacket) {
 	case *packets.ConnectPacket:
 		s.handler.Connect(&s.Client)
 	case *packets.PublishPacket:
-		s.handler.Publish(&s.Client, &p.TopicName, &p.Payload)
+		s.handler.Publish(&s.Client, &p.TopicName, &p.Payload, p.Qos, p.Retain)
 	case *packets.SubscribePacket:
 		s.handler.Subscribe(&s.Client, &p.Topics)
 	case *packets.UnsubscribePacket:
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"crypto/x509"
 	"net/http"
 	"net/url"
 	"time"
@@ -55,11 +56,16 @@ func (p Proxy) handle() http.Handler {
 			return
 		}
 
-		go p.pass(cconn)
+		var cert *x509.Certificate
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			cert = r.TLS.VerifiedChains[0][0]
+		}
+
+		go p.pass(cconn, cert)
 	})
 }
 
-func (p Proxy) pass(in *websocket.Conn) {
+func (p Proxy) pass(in *websocket.Conn, cert *x509.Certificate) {
 	defer in.Close()
 
 	url := url.URL{
@@ -85,7 +91,7 @@ func (p Proxy) pass(in *websocket.Conn) {
 	defer s.Close()
 	defer c.Close()
 
-	session := session.New(c, s, p.event, p.logger)
+	session := session.New(c, s, p.event, p.logger, cert)
 	err = session.Stream()
 	errc <- err
 	p.logger.Warn("Broken connection for client: " + session.Client.ID + " with error: " + err.Error())
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/vietquy/alpha/notifiers"
+)
+
+func addSubscriptionEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addSubscriptionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sub := notifiers.Subscription{
+			Contact:   req.Contact,
+			Project:   req.Project,
+			Subtopic:  req.Subtopic,
+			Condition: req.Condition,
+		}
+
+		saved, err := svc.AddSubscription(ctx, req.token, sub)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toSubscriptionRes(saved)
+		res.created = true
+
+		return res, nil
+	}
+}
+
+func viewSubscriptionEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSubscriptionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sub, err := svc.ViewSubscription(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toSubscriptionRes(sub), nil
+	}
+}
+
+func listSubscriptionsEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListSubscriptions(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := subscriptionsPageRes{
+			Total:         page.Total,
+			Offset:        page.Offset,
+			Limit:         page.Limit,
+			Subscriptions: []subscriptionRes{},
+		}
+		for _, sub := range page.Subscriptions {
+			res.Subscriptions = append(res.Subscriptions, toSubscriptionRes(sub))
+		}
+
+		return res, nil
+	}
+}
+
+func removeSubscriptionEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSubscriptionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveSubscription(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toSubscriptionRes(sub notifiers.Subscription) subscriptionRes {
+	return subscriptionRes{
+		ID:        sub.ID,
+		Contact:   sub.Contact,
+		Project:   sub.Project,
+		Subtopic:  sub.Subtopic,
+		Condition: sub.Condition,
+		Created:   sub.Created,
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/notifiers"
+)
+
+var _ notifiers.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    notifiers.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc notifiers.Service, logger log.Logger) notifiers.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) AddSubscription(ctx context.Context, token string, sub notifiers.Subscription) (saved notifiers.Subscription, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_subscription for project %s and subscription %s took %s to complete", sub.Project, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddSubscription(ctx, token, sub)
+}
+
+func (lm *loggingMiddleware) ViewSubscription(ctx context.Context, token, id string) (_ notifiers.Subscription, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_subscription for subscription %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewSubscription(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListSubscriptions(ctx context.Context, token string, offset, limit uint64) (_ notifiers.SubscriptionsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_subscriptions for offset %d and limit %d took %s to complete", offset, limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSubscriptions(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveSubscription(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_subscription for subscription %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveSubscription(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Consume(ctx context.Context, msg messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method consume for thing %s and project %s took %s to complete", msg.Publisher, msg.Project, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Debug(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Consume(ctx, msg)
+}
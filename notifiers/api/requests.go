@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/mail"
+
+	"github.com/vietquy/alpha/notifiers"
+)
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type addSubscriptionReq struct {
+	token     string
+	Contact   string `json:"contact"`
+	Project   string `json:"project"`
+	Subtopic  string `json:"subtopic,omitempty"`
+	Condition string `json:"condition"`
+}
+
+func (req addSubscriptionReq) validate() error {
+	if req.token == "" {
+		return notifiers.ErrUnauthorizedAccess
+	}
+
+	if req.Project == "" || req.Condition == "" {
+		return notifiers.ErrMalformedEntity
+	}
+
+	// Only the bare email addresses are accepted, so that the contact can't
+	// inject the additional email headers.
+	addr, err := mail.ParseAddress(req.Contact)
+	if err != nil || addr.Address != req.Contact {
+		return notifiers.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewSubscriptionReq struct {
+	token string
+	id    string
+}
+
+func (req viewSubscriptionReq) validate() error {
+	if req.token == "" {
+		return notifiers.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return notifiers.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return notifiers.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return notifiers.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vietquy/alpha"
+)
+
+var (
+	_ alpha.Response = (*subscriptionRes)(nil)
+	_ alpha.Response = (*subscriptionsPageRes)(nil)
+	_ alpha.Response = (*removeRes)(nil)
+)
+
+type subscriptionRes struct {
+	ID        string    `json:"id"`
+	Contact   string    `json:"contact"`
+	Project   string    `json:"project"`
+	Subtopic  string    `json:"subtopic,omitempty"`
+	Condition string    `json:"condition"`
+	Created   time.Time `json:"created"`
+	created   bool
+}
+
+func (res subscriptionRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res subscriptionRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/subscriptions/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res subscriptionRes) Empty() bool {
+	return false
+}
+
+type subscriptionsPageRes struct {
+	Total         uint64            `json:"total"`
+	Offset        uint64            `json:"offset"`
+	Limit         uint64            `json:"limit"`
+	Subscriptions []subscriptionRes `json:"subscriptions"`
+}
+
+func (res subscriptionsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res subscriptionsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res subscriptionsPageRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
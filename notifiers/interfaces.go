@@ -0,0 +1,24 @@
+package notifiers
+
+import "context"
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
+
+// ThingsService specifies an API for accessing the projects of the user in
+// the things service, on behalf of the user identified by the provided token.
+type ThingsService interface {
+	// ViewProject checks that the project having the provided ID exists and
+	// is accessible by the user.
+	ViewProject(ctx context.Context, token, id string) error
+}
+
+// Notifier specifies an API for sending the alerts.
+type Notifier interface {
+	// Notify sends the alert having the provided subject and body to the
+	// contacts.
+	Notify(to []string, subject, body string) error
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/notifiers"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var (
+	errSaveDB     = errors.New("Save subscription to DB failed")
+	errRetrieveDB = errors.New("Retrieving subscription from DB failed")
+	errRemoveDB   = errors.New("Remove subscription from DB failed")
+	errNotifyDB   = errors.New("Update subscription notification time in DB failed")
+)
+
+var _ notifiers.SubscriptionRepository = (*subscriptionRepository)(nil)
+
+type subscriptionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSubscriptionRepository instantiates a PostgreSQL implementation of
+// subscription repository.
+func NewSubscriptionRepository(db *sqlx.DB) notifiers.SubscriptionRepository {
+	return &subscriptionRepository{
+		db: db,
+	}
+}
+
+func (sr subscriptionRepository) Save(ctx context.Context, sub notifiers.Subscription) error {
+	q := `INSERT INTO subscriptions (id, owner, contact, project, subtopic, condition, created)
+	      VALUES (:id, :owner, :contact, :project, :subtopic, :condition, :created)`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, toDBSubscription(sub)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return errors.Wrap(notifiers.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(notifiers.ErrConflict, err)
+			}
+		}
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	return nil
+}
+
+func (sr subscriptionRepository) RetrieveByID(ctx context.Context, owner, id string) (notifiers.Subscription, error) {
+	q := `SELECT id, owner, contact, project, subtopic, condition, created
+	      FROM subscriptions WHERE id = $1 AND owner = $2`
+
+	dbs := dbSubscription{}
+	if err := sr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return notifiers.Subscription{}, errors.Wrap(notifiers.ErrNotFound, err)
+		}
+		return notifiers.Subscription{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toSubscription(dbs), nil
+}
+
+func (sr subscriptionRepository) RetrieveByProject(ctx context.Context, project string) ([]notifiers.Subscription, error) {
+	q := `SELECT id, owner, contact, project, subtopic, condition, created
+	      FROM subscriptions WHERE project = $1`
+
+	rows, err := sr.db.QueryxContext(ctx, q, project)
+	if err != nil {
+		// Messages published to the projects having the invalid ID don't
+		// match any subscription.
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return []notifiers.Subscription{}, nil
+		}
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	return scanSubscriptions(rows)
+}
+
+func (sr subscriptionRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (notifiers.SubscriptionsPage, error) {
+	q := `SELECT id, owner, contact, project, subtopic, condition, created
+	      FROM subscriptions WHERE owner = $1 ORDER BY created, id LIMIT $2 OFFSET $3`
+
+	rows, err := sr.db.QueryxContext(ctx, q, owner, limit, offset)
+	if err != nil {
+		return notifiers.SubscriptionsPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	items, err := scanSubscriptions(rows)
+	if err != nil {
+		return notifiers.SubscriptionsPage{}, err
+	}
+
+	var total uint64
+	if err := sr.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM subscriptions WHERE owner = $1`, owner).Scan(&total); err != nil {
+		return notifiers.SubscriptionsPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	page := notifiers.SubscriptionsPage{
+		Total:         total,
+		Offset:        offset,
+		Limit:         limit,
+		Subscriptions: items,
+	}
+
+	return page, nil
+}
+
+func (sr subscriptionRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM subscriptions WHERE id = $1 AND owner = $2`
+
+	if _, err := sr.db.ExecContext(ctx, q, id, owner); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return errors.Wrap(errRemoveDB, err)
+	}
+
+	return nil
+}
+
+func (sr subscriptionRepository) Notify(ctx context.Context, id string, at, threshold time.Time) (bool, error) {
+	// The conditional update lets only one of the concurrent consumers send
+	// the alert.
+	q := `UPDATE subscriptions SET notified = $2 WHERE id = $1 AND (notified IS NULL OR notified <= $3)`
+
+	res, err := sr.db.ExecContext(ctx, q, id, at, threshold)
+	if err != nil {
+		return false, errors.Wrap(errNotifyDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(errNotifyDB, err)
+	}
+
+	return cnt > 0, nil
+}
+
+func scanSubscriptions(rows *sqlx.Rows) ([]notifiers.Subscription, error) {
+	items := []notifiers.Subscription{}
+	for rows.Next() {
+		dbs := dbSubscription{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+		items = append(items, toSubscription(dbs))
+	}
+
+	return items, nil
+}
+
+type dbSubscription struct {
+	ID        string         `db:"id"`
+	Owner     string         `db:"owner"`
+	Contact   string         `db:"contact"`
+	Project   string         `db:"project"`
+	Subtopic  sql.NullString `db:"subtopic"`
+	Condition string         `db:"condition"`
+	Created   time.Time      `db:"created"`
+}
+
+func toDBSubscription(sub notifiers.Subscription) dbSubscription {
+	return dbSubscription{
+		ID:        sub.ID,
+		Owner:     sub.Owner,
+		Contact:   sub.Contact,
+		Project:   sub.Project,
+		Subtopic:  sql.NullString{String: sub.Subtopic, Valid: sub.Subtopic != ""},
+		Condition: sub.Condition,
+		Created:   sub.Created,
+	}
+}
+
+func toSubscription(dbs dbSubscription) notifiers.Subscription {
+	return notifiers.Subscription{
+		ID:        dbs.ID,
+		Owner:     dbs.Owner,
+		Contact:   dbs.Contact,
+		Project:   dbs.Project,
+		Subtopic:  dbs.Subtopic.String,
+		Condition: dbs.Condition,
+		Created:   dbs.Created,
+	}
+}
@@ -0,0 +1,220 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/authn"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/rules"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrConflict indicates that the entity already exists.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrThings indicates failed communication with the things service.
+	ErrThings = errors.New("things service request failed")
+
+	// ErrNotify indicates the failure to send the alert.
+	ErrNotify = errors.New("failed to send alert")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// AddSubscription adds the subscription to the user identified by the
+	// provided key.
+	AddSubscription(ctx context.Context, token string, sub Subscription) (Subscription, error)
+
+	// ViewSubscription retrieves the subscription having the provided ID,
+	// that belongs to the user identified by the provided key.
+	ViewSubscription(ctx context.Context, token, id string) (Subscription, error)
+
+	// ListSubscriptions retrieves the subset of subscriptions that belong to
+	// the user identified by the provided key.
+	ListSubscriptions(ctx context.Context, token string, offset, limit uint64) (SubscriptionsPage, error)
+
+	// RemoveSubscription removes the subscription having the provided ID,
+	// that belongs to the user identified by the provided key.
+	RemoveSubscription(ctx context.Context, token, id string) error
+
+	// Consume sends the alerts of the subscriptions whose conditions are
+	// met by the message. The alerts of the subscription are sent at most
+	// once per throttling period.
+	Consume(ctx context.Context, msg messaging.Message) error
+}
+
+var _ Service = (*notifierService)(nil)
+
+type notifierService struct {
+	auth     alpha.AuthNServiceClient
+	subs     SubscriptionRepository
+	things   ThingsService
+	idp      IdentityProvider
+	notifier Notifier
+	throttle time.Duration
+}
+
+// New instantiates the notifier service implementation.
+func New(auth alpha.AuthNServiceClient, subs SubscriptionRepository, things ThingsService, idp IdentityProvider, notifier Notifier, throttle time.Duration) Service {
+	return &notifierService{
+		auth:     auth,
+		subs:     subs,
+		things:   things,
+		idp:      idp,
+		notifier: notifier,
+		throttle: throttle,
+	}
+}
+
+func (ns notifierService) AddSubscription(ctx context.Context, token string, sub Subscription) (Subscription, error) {
+	owner, err := ns.identify(ctx, token)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	if _, err := rules.Parse(sub.Condition); err != nil {
+		return Subscription{}, errors.Wrap(ErrMalformedEntity, err)
+	}
+
+	if err := ns.things.ViewProject(ctx, token, sub.Project); err != nil {
+		return Subscription{}, err
+	}
+
+	sub.ID, err = ns.idp.ID()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	sub.Owner = owner
+	sub.Created = time.Now().UTC()
+
+	if err := ns.subs.Save(ctx, sub); err != nil {
+		return Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+func (ns notifierService) ViewSubscription(ctx context.Context, token, id string) (Subscription, error) {
+	owner, err := ns.identify(ctx, token)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return ns.subs.RetrieveByID(ctx, owner, id)
+}
+
+func (ns notifierService) ListSubscriptions(ctx context.Context, token string, offset, limit uint64) (SubscriptionsPage, error) {
+	owner, err := ns.identify(ctx, token)
+	if err != nil {
+		return SubscriptionsPage{}, err
+	}
+
+	return ns.subs.RetrieveAll(ctx, owner, offset, limit)
+}
+
+func (ns notifierService) RemoveSubscription(ctx context.Context, token, id string) error {
+	owner, err := ns.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return ns.subs.Remove(ctx, owner, id)
+}
+
+func (ns notifierService) Consume(ctx context.Context, msg messaging.Message) error {
+	subs, err := ns.subs.RetrieveByProject(ctx, msg.Project)
+	if err != nil {
+		return err
+	}
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	vals := rules.Values(msg)
+
+	// Failure of the subscription doesn't prevent the alerts of the other
+	// subscriptions, so the last failure is reported.
+	var failed error
+	for _, sub := range subs {
+		if sub.Subtopic != "" && sub.Subtopic != msg.Subtopic {
+			continue
+		}
+
+		cond, err := rules.Parse(sub.Condition)
+		if err != nil {
+			failed = errors.Wrap(ErrMalformedEntity, err)
+			continue
+		}
+
+		matched := false
+		for _, v := range vals {
+			if cond.Evaluate(v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		now := time.Now().UTC()
+		ok, err := ns.subs.Notify(ctx, sub.ID, now, now.Add(-ns.throttle))
+		if err != nil {
+			failed = err
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		subject, body := alert(sub, msg)
+		if err := ns.notifier.Notify([]string{sub.Contact}, subject, body); err != nil {
+			failed = errors.Wrap(ErrNotify, err)
+		}
+	}
+
+	return failed
+}
+
+// identify returns the owner of the subscriptions of the user identified by the
+// provided token, see authn.Owner.
+func (ns notifierService) identify(ctx context.Context, token string) (string, error) {
+	res, err := ns.auth.Identify(ctx, &alpha.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return authn.Owner(res.GetValue(), res.GetOrganization()), nil
+}
+
+// alert returns the subject and the body of the alert raised by the message.
+func alert(sub Subscription, msg messaging.Message) (string, string) {
+	subject := fmt.Sprintf("Alert for project %s", msg.Project)
+
+	topic := msg.Project
+	if msg.Subtopic != "" {
+		topic = fmt.Sprintf("%s.%s", msg.Project, msg.Subtopic)
+	}
+	created := time.Unix(0, msg.Created).UTC().Format(time.RFC3339)
+	body := fmt.Sprintf("Condition %s is met by the message published by thing %s to %s at %s.\r\n\r\nPayload:\r\n%s\r\n",
+		sub.Condition, msg.Publisher, topic, created, msg.Payload)
+
+	return subject, body
+}
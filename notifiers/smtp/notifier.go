@@ -0,0 +1,59 @@
+// Package smtp provides the notifier sending the alerts by email.
+package smtp
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/vietquy/alpha/notifiers"
+)
+
+// Config represents the SMTP server the alerts are sent through. The
+// username and the password are used for the PLAIN authentication if set,
+// while the messages are sent from the provided address.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+var _ notifiers.Notifier = (*notifier)(nil)
+
+type notifier struct {
+	cfg Config
+}
+
+// New instantiates the SMTP notifier.
+func New(cfg Config) notifiers.Notifier {
+	return &notifier{cfg: cfg}
+}
+
+func (n notifier) Notify(to []string, subject, body string) error {
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(n.cfg.Host, n.cfg.Port)
+
+	return smtp.SendMail(addr, auth, n.cfg.From, to, n.message(to, subject, body))
+}
+
+func (n notifier) message(to []string, subject, body string) []byte {
+	headers := []string{
+		fmt.Sprintf("From: %s", n.cfg.From),
+		fmt.Sprintf("To: %s", strings.Join(to, ", ")),
+		fmt.Sprintf("Subject: %s", mime.QEncoding.Encode("utf-8", subject)),
+		fmt.Sprintf("Date: %s", time.Now().Format(time.RFC1123Z)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+	}
+
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body)
+}
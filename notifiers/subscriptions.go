@@ -0,0 +1,56 @@
+package notifiers
+
+import (
+	"context"
+	"time"
+)
+
+// Subscription represents the subscription of the email address to the
+// alerts raised when the messages published to the project, and matching
+// the subtopic if set, meet the condition.
+type Subscription struct {
+	ID        string
+	Owner     string
+	Contact   string
+	Project   string
+	Subtopic  string
+	Condition string
+	Created   time.Time
+}
+
+// SubscriptionsPage contains page related metadata as well as the list of
+// subscriptions that belong to this page.
+type SubscriptionsPage struct {
+	Total         uint64
+	Offset        uint64
+	Limit         uint64
+	Subscriptions []Subscription
+}
+
+// SubscriptionRepository specifies a subscription persistence API.
+type SubscriptionRepository interface {
+	// Save persists the subscription.
+	Save(ctx context.Context, sub Subscription) error
+
+	// RetrieveByID retrieves the subscription having the provided
+	// identifier, that is owned by the specified user.
+	RetrieveByID(ctx context.Context, owner, id string) (Subscription, error)
+
+	// RetrieveByProject retrieves all subscriptions to the messages
+	// published to the project having the provided identifier.
+	RetrieveByProject(ctx context.Context, project string) ([]Subscription, error)
+
+	// RetrieveAll retrieves the subset of subscriptions owned by the
+	// specified user.
+	RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (SubscriptionsPage, error)
+
+	// Remove removes the subscription having the provided identifier, that
+	// is owned by the specified user.
+	Remove(ctx context.Context, owner, id string) error
+
+	// Notify records that the alert of the subscription is sent at the
+	// provided time, unless the previous alert is sent after the provided
+	// threshold. It reports whether the alert should be sent, so that the
+	// service instances don't send the same alert repeatedly.
+	Notify(ctx context.Context, id string, at, threshold time.Time) (bool, error)
+}
@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/vietquy/alpha/errors"
 	"github.com/vietquy/alpha/reader"
 
 	influxdata "github.com/influxdata/influxdb/client/v2"
 	"github.com/vietquy/alpha/transformer"
+	"github.com/vietquy/alpha/transformer/senml"
 )
 
 const (
@@ -64,6 +66,10 @@ func (repo *influxRepository) ReadAll(projectID string, rpm reader.PageMetadata)
 
 	result := resp.Results[0].Series[0]
 	for _, v := range result.Values {
+		if format == senml.Format {
+			ret = append(ret, parseSenML(result.Columns, v))
+			continue
+		}
 		ret = append(ret, parseJSON(result.Columns, v))
 	}
 
@@ -171,3 +177,24 @@ func parseJSON(names []string, fields []interface{}) interface{} {
 
 	return transformer.ParseFlat(ret)
 }
+
+// parseSenML returns the SenML measurement, whose time is in seconds, as
+// written by the SenML transformer, instead of the RFC3339 point time.
+func parseSenML(names []string, fields []interface{}) interface{} {
+	ret := make(map[string]interface{})
+	for i, n := range names {
+		ret[n] = fields[i]
+		if n != senml.TimeKey {
+			continue
+		}
+		s, ok := fields[i].(string)
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			ret[n] = float64(t.UnixNano()) / 1e9
+		}
+	}
+
+	return transformer.ParseFlat(ret)
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/lib/pq" // required for SQL access
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host string
+	Port string
+	User string
+	Pass string
+	Name string
+}
+
+// Connect creates a connection to the PostgreSQL instance. The messages
+// table is created by the writer, so the reader applies no migrations.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass)
+
+	return sqlx.Open("postgres", url)
+}
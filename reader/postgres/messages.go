@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/reader"
+	"github.com/vietquy/alpha/transformer"
+)
+
+const defFormat = "messages"
+
+var errReadMessages = errors.New("failed to read messages from postgres database")
+
+var _ reader.MessageRepository = (*postgresRepository)(nil)
+
+type postgresRepository struct {
+	db *sqlx.DB
+}
+
+// New returns new PostgreSQL reader.
+func New(db *sqlx.DB) reader.MessageRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (repo *postgresRepository) ReadAll(projectID string, rpm reader.PageMetadata) (reader.MessagesPage, error) {
+	condition, params := fmtCondition(projectID, rpm)
+
+	q := fmt.Sprintf(`SELECT project, subtopic, publisher, protocol, headers, payload, created
+	      FROM messages WHERE %s ORDER BY created DESC LIMIT :limit OFFSET :offset;`, condition)
+	params["limit"] = rpm.Limit
+	params["offset"] = rpm.Offset
+
+	rows, err := repo.db.NamedQuery(q, params)
+	if err != nil {
+		return reader.MessagesPage{}, errors.Wrap(errReadMessages, err)
+	}
+	defer rows.Close()
+
+	var ret []reader.Message
+	for rows.Next() {
+		dbm := dbMessage{}
+		if err := rows.StructScan(&dbm); err != nil {
+			return reader.MessagesPage{}, errors.Wrap(errReadMessages, err)
+		}
+
+		msg, err := toMessage(dbm)
+		if err != nil {
+			return reader.MessagesPage{}, errors.Wrap(errReadMessages, err)
+		}
+		ret = append(ret, msg)
+	}
+
+	total, err := repo.count(condition, params)
+	if err != nil {
+		return reader.MessagesPage{}, errors.Wrap(errReadMessages, err)
+	}
+
+	page := reader.MessagesPage{
+		PageMetadata: rpm,
+		Total:        total,
+		Messages:     ret,
+	}
+
+	return page, nil
+}
+
+func (repo *postgresRepository) count(condition string, params map[string]interface{}) (uint64, error) {
+	q := fmt.Sprintf(`SELECT COUNT(*) FROM messages WHERE %s;`, condition)
+
+	rows, err := repo.db.NamedQuery(q, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var total uint64
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+// fmtCondition returns the condition the messages are filtered by, along with
+// its named parameters. Values are filtered by the payload fields of the
+// normalized measurements, i.e. value, stringValue, boolValue and dataValue.
+func fmtCondition(projectID string, rpm reader.PageMetadata) (string, map[string]interface{}) {
+	format := defFormat
+	if rpm.Format != "" {
+		format = rpm.Format
+	}
+
+	conditions := []string{"project = :project", "format = :format"}
+	params := map[string]interface{}{
+		"project": projectID,
+		"format":  format,
+	}
+
+	if rpm.Subtopic != "" {
+		conditions = append(conditions, "subtopic = :subtopic")
+		params["subtopic"] = rpm.Subtopic
+	}
+	if rpm.Publisher != "" {
+		conditions = append(conditions, "publisher = :publisher")
+		params["publisher"] = rpm.Publisher
+	}
+	if rpm.Protocol != "" {
+		conditions = append(conditions, "protocol = :protocol")
+		params["protocol"] = rpm.Protocol
+	}
+	if rpm.Name != "" {
+		conditions = append(conditions, "payload->>'name' = :name")
+		params["name"] = rpm.Name
+	}
+	if rpm.Value != 0 {
+		comparator := reader.ParseValueComparator(map[string]interface{}{"comparator": rpm.Comparator})
+		// Value is cast only if it's a number, since the cast of the other
+		// JSON values fails the whole query.
+		conditions = append(conditions, fmt.Sprintf("CASE WHEN jsonb_typeof(payload->'value') = 'number' THEN CAST(payload->>'value' AS float8) END %s :v", comparator))
+		params["v"] = rpm.Value
+	}
+	if rpm.BoolValue {
+		conditions = append(conditions, "payload->>'boolValue' = :vb")
+		params["vb"] = strconv.FormatBool(rpm.BoolValue)
+	}
+	if rpm.StringValue != "" {
+		conditions = append(conditions, "payload->>'stringValue' = :vs")
+		params["vs"] = rpm.StringValue
+	}
+	if rpm.DataValue != "" {
+		conditions = append(conditions, "payload->>'dataValue' = :vd")
+		params["vd"] = rpm.DataValue
+	}
+	if rpm.From != 0 {
+		conditions = append(conditions, "created >= :from")
+		params["from"] = int64(rpm.From * 1e9)
+	}
+	if rpm.To != 0 {
+		conditions = append(conditions, "created < :to")
+		params["to"] = int64(rpm.To * 1e9)
+	}
+
+	return strings.Join(conditions, " AND "), params
+}
+
+type dbMessage struct {
+	Project   string         `db:"project"`
+	Subtopic  sql.NullString `db:"subtopic"`
+	Publisher string         `db:"publisher"`
+	Protocol  string         `db:"protocol"`
+	Headers   sql.NullString `db:"headers"`
+	Payload   []byte         `db:"payload"`
+	Created   int64          `db:"created"`
+}
+
+// toMessage returns the message in the format of the other readers, having the
+// payload fields along with the message fields.
+func toMessage(dbm dbMessage) (reader.Message, error) {
+	flat := map[string]interface{}{}
+	if len(dbm.Payload) > 0 {
+		if err := json.Unmarshal(dbm.Payload, &flat); err != nil {
+			return nil, err
+		}
+		// Payload stored as JSON null gives nil map.
+		if flat == nil {
+			flat = map[string]interface{}{}
+		}
+	}
+
+	flat["project"] = dbm.Project
+	flat["publisher"] = dbm.Publisher
+	flat["protocol"] = dbm.Protocol
+	flat["created"] = dbm.Created
+	if dbm.Subtopic.Valid {
+		flat["subtopic"] = dbm.Subtopic.String
+	}
+	if dbm.Headers.Valid {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(dbm.Headers.String), &headers); err != nil {
+			return nil, err
+		}
+		flat["headers"] = headers
+	}
+
+	return transformer.ParseFlat(flat), nil
+}
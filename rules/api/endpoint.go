@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/vietquy/alpha/rules"
+)
+
+func addRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ruleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		saved, err := svc.AddRule(ctx, req.token, req.rule())
+		if err != nil {
+			return nil, err
+		}
+
+		res := toRuleRes(saved)
+		res.created = true
+
+		return res, nil
+	}
+}
+
+func updateRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ruleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if req.id == "" {
+			return nil, rules.ErrMalformedEntity
+		}
+
+		if err := svc.UpdateRule(ctx, req.token, req.rule()); err != nil {
+			return nil, err
+		}
+
+		return updateRes{}, nil
+	}
+}
+
+func viewRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rule, err := svc.ViewRule(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRuleRes(rule), nil
+	}
+}
+
+func listRulesEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListRules(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := rulesPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Rules:  []ruleRes{},
+		}
+		for _, rule := range page.Rules {
+			res.Rules = append(res.Rules, toRuleRes(rule))
+		}
+
+		return res, nil
+	}
+}
+
+func removeRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveRule(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toRuleRes(rule rules.Rule) ruleRes {
+	res := ruleRes{
+		ID:        rule.ID,
+		Name:      rule.Name,
+		Project:   rule.Project,
+		Condition: rule.Condition,
+		Actions:   []actionRes{},
+		Created:   rule.Created,
+	}
+	for _, action := range rule.Actions {
+		res.Actions = append(res.Actions, actionRes(action))
+	}
+
+	return res
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/rules"
+)
+
+var _ rules.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    rules.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc rules.Service, logger log.Logger) rules.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) AddRule(ctx context.Context, token string, rule rules.Rule) (saved rules.Rule, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_rule for project %s and rule %s took %s to complete", rule.Project, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddRule(ctx, token, rule)
+}
+
+func (lm *loggingMiddleware) UpdateRule(ctx context.Context, token string, rule rules.Rule) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_rule for rule %s took %s to complete", rule.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateRule(ctx, token, rule)
+}
+
+func (lm *loggingMiddleware) ViewRule(ctx context.Context, token, id string) (_ rules.Rule, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_rule for rule %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewRule(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListRules(ctx context.Context, token string, offset, limit uint64) (_ rules.RulesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_rules for offset %d and limit %d took %s to complete", offset, limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListRules(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveRule(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_rule for rule %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveRule(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Evaluate(ctx context.Context, msg messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method evaluate for thing %s and project %s took %s to complete", msg.Publisher, msg.Project, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Debug(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Evaluate(ctx, msg)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"net/url"
+
+	"github.com/vietquy/alpha/rules"
+)
+
+const (
+	maxLimitSize = 100
+	maxNameSize  = 1024
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type actionReq struct {
+	Type     string `json:"type"`
+	Project  string `json:"project,omitempty"`
+	Subtopic string `json:"subtopic,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+func (req actionReq) validate() error {
+	switch req.Type {
+	case rules.PublishAction:
+		if req.Project == "" {
+			return rules.ErrMalformedEntity
+		}
+	case rules.WebhookAction:
+		u, err := url.Parse(req.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return rules.ErrMalformedEntity
+		}
+	case rules.LogAction:
+	default:
+		return rules.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type ruleReq struct {
+	token     string
+	id        string
+	Name      string      `json:"name,omitempty"`
+	Project   string      `json:"project"`
+	Condition string      `json:"condition"`
+	Actions   []actionReq `json:"actions"`
+}
+
+func (req ruleReq) validate() error {
+	if req.token == "" {
+		return rules.ErrUnauthorizedAccess
+	}
+
+	if req.Project == "" || req.Condition == "" || len(req.Name) > maxNameSize {
+		return rules.ErrMalformedEntity
+	}
+
+	if len(req.Actions) == 0 {
+		return rules.ErrMalformedEntity
+	}
+
+	for _, action := range req.Actions {
+		if err := action.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (req ruleReq) rule() rules.Rule {
+	rule := rules.Rule{
+		ID:        req.id,
+		Name:      req.Name,
+		Project:   req.Project,
+		Condition: req.Condition,
+		Actions:   []rules.Action{},
+	}
+	for _, action := range req.Actions {
+		rule.Actions = append(rule.Actions, rules.Action(action))
+	}
+
+	return rule
+}
+
+type viewRuleReq struct {
+	token string
+	id    string
+}
+
+func (req viewRuleReq) validate() error {
+	if req.token == "" {
+		return rules.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return rules.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return rules.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return rules.ErrMalformedEntity
+	}
+
+	return nil
+}
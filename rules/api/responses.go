@@ -0,0 +1,106 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vietquy/alpha"
+)
+
+var (
+	_ alpha.Response = (*ruleRes)(nil)
+	_ alpha.Response = (*rulesPageRes)(nil)
+	_ alpha.Response = (*updateRes)(nil)
+	_ alpha.Response = (*removeRes)(nil)
+)
+
+type actionRes struct {
+	Type     string `json:"type"`
+	Project  string `json:"project,omitempty"`
+	Subtopic string `json:"subtopic,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type ruleRes struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name,omitempty"`
+	Project   string      `json:"project"`
+	Condition string      `json:"condition"`
+	Actions   []actionRes `json:"actions"`
+	Created   time.Time   `json:"created"`
+	created   bool
+}
+
+func (res ruleRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res ruleRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/rules/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res ruleRes) Empty() bool {
+	return false
+}
+
+type rulesPageRes struct {
+	Total  uint64    `json:"total"`
+	Offset uint64    `json:"offset"`
+	Limit  uint64    `json:"limit"`
+	Rules  []ruleRes `json:"rules"`
+}
+
+func (res rulesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res rulesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res rulesPageRes) Empty() bool {
+	return false
+}
+
+type updateRes struct{}
+
+func (res updateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
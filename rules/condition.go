@@ -0,0 +1,372 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vietquy/alpha/errors"
+)
+
+// ErrCondition indicates the condition that can't be parsed.
+var ErrCondition = errors.New("invalid rule condition")
+
+// maxConditionDepth limits the nesting of the parentheses and the NOT
+// operators, so that the parser recursion is bounded.
+const maxConditionDepth = 16
+
+// Condition represents the parsed rule condition.
+type Condition interface {
+	// Evaluate reports whether the condition is met by the provided values,
+	// being the flattened message payload along with the message project,
+	// subtopic, publisher and protocol. Comparisons of the missing values
+	// and the values of different types are never met, except for !=.
+	Evaluate(values map[string]interface{}) bool
+}
+
+// Parse parses the condition. The condition consists of the comparisons of
+// the flattened payload keys and the message fields to the literals, e.g.
+// `temp > 30 AND subtopic == "room1"`, combined using AND, OR, NOT and the
+// parentheses. Supported comparison operators are ==, !=, >, >=, < and <=,
+// while the literals are the numbers, the quoted strings, true and false.
+func Parse(cond string) (Condition, error) {
+	toks, err := lex(cond)
+	if err != nil {
+		return nil, errors.Wrap(ErrCondition, err)
+	}
+
+	p := parser{toks: toks}
+	c, err := p.or()
+	if err != nil {
+		return nil, errors.Wrap(ErrCondition, err)
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errors.Wrap(ErrCondition, errors.New(fmt.Sprintf("unexpected %q", t.text)))
+	}
+
+	return c, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokBool
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func lex(s string) ([]token, error) {
+	toks := []token{}
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "("})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")"})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			j := i + 1
+			if j < len(rs) && rs[j] == '=' {
+				j++
+			}
+			op := string(rs[i:j])
+			if op == "=" || op == "!" {
+				return nil, errors.New(fmt.Sprintf("invalid operator %q", op))
+			}
+			toks = append(toks, token{tokOp, op})
+			i = j
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				j++
+			}
+			if j == len(rs) {
+				return nil, errors.New("unterminated string")
+			}
+			toks = append(toks, token{tokString, string(rs[i+1 : j])})
+			i = j + 1
+		case unicode.IsDigit(r) || r == '-' || r == '.':
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || strings.ContainsRune(".eE+-", rs[j])) {
+				j++
+			}
+			toks = append(toks, token{tokNumber, string(rs[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || strings.ContainsRune("_-./", rs[j])) {
+				j++
+			}
+			text := string(rs[i:j])
+			switch strings.ToUpper(text) {
+			case "AND":
+				toks = append(toks, token{tokAnd, text})
+			case "OR":
+				toks = append(toks, token{tokOr, text})
+			case "NOT":
+				toks = append(toks, token{tokNot, text})
+			case "TRUE", "FALSE":
+				toks = append(toks, token{tokBool, strings.ToLower(text)})
+			default:
+				toks = append(toks, token{tokIdent, text})
+			}
+			i = j
+		default:
+			return nil, errors.New(fmt.Sprintf("unexpected character %q", r))
+		}
+	}
+
+	return append(toks, token{kind: tokEOF}), nil
+}
+
+type parser struct {
+	toks  []token
+	pos   int
+	depth int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) or() (Condition, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orCond{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) and() (Condition, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = andCond{left, right}
+	}
+
+	return left, nil
+}
+
+// nest enters the nested condition, returning the error if the nesting is
+// too deep. The returned function leaves it.
+func (p *parser) nest() (func(), error) {
+	if p.depth >= maxConditionDepth {
+		return nil, errors.New("condition nested too deep")
+	}
+	p.depth++
+	return func() { p.depth-- }, nil
+}
+
+func (p *parser) not() (Condition, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		leave, err := p.nest()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		c, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return notCond{c}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *parser) primary() (Condition, error) {
+	if p.peek().kind == tokLParen {
+		p.next()
+		leave, err := p.nest()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		c, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		return c, nil
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.next()
+	if op.kind != tokOp {
+		return nil, errors.New(fmt.Sprintf("expected comparison operator, got %q", op.text))
+	}
+
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmpCond{op: op.text, left: left, right: right}, nil
+}
+
+func (p *parser) operand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokIdent:
+		return operand{key: t.text}, nil
+	case tokString:
+		return operand{val: t.text}, nil
+	case tokBool:
+		return operand{val: t.text == "true"}, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return operand{}, errors.New(fmt.Sprintf("invalid number %q", t.text))
+		}
+		return operand{val: f}, nil
+	case tokEOF:
+		return operand{}, errors.New("unexpected end of condition")
+	default:
+		return operand{}, errors.New(fmt.Sprintf("expected operand, got %q", t.text))
+	}
+}
+
+type andCond struct {
+	left, right Condition
+}
+
+func (c andCond) Evaluate(values map[string]interface{}) bool {
+	return c.left.Evaluate(values) && c.right.Evaluate(values)
+}
+
+type orCond struct {
+	left, right Condition
+}
+
+func (c orCond) Evaluate(values map[string]interface{}) bool {
+	return c.left.Evaluate(values) || c.right.Evaluate(values)
+}
+
+type notCond struct {
+	cond Condition
+}
+
+func (c notCond) Evaluate(values map[string]interface{}) bool {
+	return !c.cond.Evaluate(values)
+}
+
+// operand is either the key of the value being compared, or the literal.
+type operand struct {
+	key string
+	val interface{}
+}
+
+func (o operand) value(values map[string]interface{}) (interface{}, bool) {
+	if o.key == "" {
+		return o.val, true
+	}
+
+	v, ok := values[o.key]
+	return v, ok
+}
+
+type cmpCond struct {
+	op          string
+	left, right operand
+}
+
+func (c cmpCond) Evaluate(values map[string]interface{}) bool {
+	l, ok := c.left.value(values)
+	if !ok {
+		return false
+	}
+
+	r, ok := c.right.value(values)
+	if !ok {
+		return false
+	}
+
+	switch lv := l.(type) {
+	case float64:
+		if rv, ok := r.(float64); ok {
+			return compare(c.op, lv < rv, lv == rv)
+		}
+	case string:
+		if rv, ok := r.(string); ok {
+			return compare(c.op, lv < rv, lv == rv)
+		}
+	case bool:
+		if rv, ok := r.(bool); ok && (c.op == "==" || c.op == "!=") {
+			return compare(c.op, false, lv == rv)
+		}
+	}
+
+	return c.op == "!="
+}
+
+func compare(op string, less, equal bool) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	default:
+		return false
+	}
+}
@@ -0,0 +1,27 @@
+package rules
+
+import (
+	"context"
+
+	"github.com/vietquy/alpha/messaging"
+)
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
+
+// ThingsService specifies an API for accessing the projects of the user in
+// the things service, on behalf of the user identified by the provided token.
+type ThingsService interface {
+	// ViewProject checks that the project having the provided ID exists and
+	// is accessible by the user.
+	ViewProject(ctx context.Context, token, id string) error
+}
+
+// Webhooks specifies an API for delivering the messages to the webhooks.
+type Webhooks interface {
+	// Call posts the message to the webhook having the provided URL.
+	Call(ctx context.Context, url string, msg messaging.Message) error
+}
@@ -0,0 +1,255 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/rules"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var (
+	errSaveDB      = errors.New("Save rule to DB failed")
+	errRetrieveDB  = errors.New("Retrieving rule from DB failed")
+	errUpdateDB    = errors.New("Update rule in DB failed")
+	errRemoveDB    = errors.New("Remove rule from DB failed")
+	errMarshalJSON = errors.New("Marshal rule actions failed")
+)
+
+var _ rules.RuleRepository = (*ruleRepository)(nil)
+
+type ruleRepository struct {
+	db *sqlx.DB
+}
+
+// NewRuleRepository instantiates a PostgreSQL implementation of rule
+// repository.
+func NewRuleRepository(db *sqlx.DB) rules.RuleRepository {
+	return &ruleRepository{
+		db: db,
+	}
+}
+
+func (rr ruleRepository) Save(ctx context.Context, rule rules.Rule) error {
+	q := `INSERT INTO rules (id, owner, name, project, condition, actions, created)
+	      VALUES (:id, :owner, :name, :project, :condition, :actions, :created)`
+
+	dbr, err := toDBRule(rule)
+	if err != nil {
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	if _, err := rr.db.NamedExecContext(ctx, q, dbr); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return errors.Wrap(rules.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(rules.ErrConflict, err)
+			}
+		}
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) Update(ctx context.Context, rule rules.Rule) error {
+	q := `UPDATE rules SET name = :name, project = :project, condition = :condition, actions = :actions
+	      WHERE id = :id AND owner = :owner`
+
+	dbr, err := toDBRule(rule)
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	res, err := rr.db.NamedExecContext(ctx, q, dbr)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(rules.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	if cnt == 0 {
+		return rules.ErrNotFound
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) RetrieveByID(ctx context.Context, owner, id string) (rules.Rule, error) {
+	q := `SELECT id, owner, name, project, condition, actions, created
+	      FROM rules WHERE id = $1 AND owner = $2`
+
+	dbr := dbRule{}
+	if err := rr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbr); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return rules.Rule{}, errors.Wrap(rules.ErrNotFound, err)
+		}
+		return rules.Rule{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toRule(dbr)
+}
+
+func (rr ruleRepository) RetrieveByProject(ctx context.Context, project string) ([]rules.Rule, error) {
+	q := `SELECT id, owner, name, project, condition, actions, created
+	      FROM rules WHERE project = $1`
+
+	rows, err := rr.db.QueryxContext(ctx, q, project)
+	if err != nil {
+		// Messages published to the projects having the invalid ID are not
+		// evaluated against any rule.
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return []rules.Rule{}, nil
+		}
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	return scanRules(rows)
+}
+
+func (rr ruleRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (rules.RulesPage, error) {
+	q := `SELECT id, owner, name, project, condition, actions, created
+	      FROM rules WHERE owner = $1 ORDER BY created, id LIMIT $2 OFFSET $3`
+
+	rows, err := rr.db.QueryxContext(ctx, q, owner, limit, offset)
+	if err != nil {
+		return rules.RulesPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	items, err := scanRules(rows)
+	if err != nil {
+		return rules.RulesPage{}, err
+	}
+
+	var total uint64
+	if err := rr.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM rules WHERE owner = $1`, owner).Scan(&total); err != nil {
+		return rules.RulesPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	page := rules.RulesPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Rules:  items,
+	}
+
+	return page, nil
+}
+
+func (rr ruleRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM rules WHERE id = $1 AND owner = $2`
+
+	if _, err := rr.db.ExecContext(ctx, q, id, owner); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return errors.Wrap(errRemoveDB, err)
+	}
+
+	return nil
+}
+
+func scanRules(rows *sqlx.Rows) ([]rules.Rule, error) {
+	items := []rules.Rule{}
+	for rows.Next() {
+		dbr := dbRule{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+
+		rule, err := toRule(dbr)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, rule)
+	}
+
+	return items, nil
+}
+
+type dbRule struct {
+	ID        string         `db:"id"`
+	Owner     string         `db:"owner"`
+	Name      sql.NullString `db:"name"`
+	Project   string         `db:"project"`
+	Condition string         `db:"condition"`
+	Actions   []byte         `db:"actions"`
+	Created   time.Time      `db:"created"`
+}
+
+type dbAction struct {
+	Type     string `json:"type"`
+	Project  string `json:"project,omitempty"`
+	Subtopic string `json:"subtopic,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+func toDBRule(rule rules.Rule) (dbRule, error) {
+	actions := []dbAction{}
+	for _, a := range rule.Actions {
+		actions = append(actions, dbAction(a))
+	}
+
+	data, err := json.Marshal(actions)
+	if err != nil {
+		return dbRule{}, errors.Wrap(errMarshalJSON, err)
+	}
+
+	dbr := dbRule{
+		ID:        rule.ID,
+		Owner:     rule.Owner,
+		Name:      sql.NullString{String: rule.Name, Valid: rule.Name != ""},
+		Project:   rule.Project,
+		Condition: rule.Condition,
+		Actions:   data,
+		Created:   rule.Created,
+	}
+
+	return dbr, nil
+}
+
+func toRule(dbr dbRule) (rules.Rule, error) {
+	var actions []dbAction
+	if err := json.Unmarshal(dbr.Actions, &actions); err != nil {
+		return rules.Rule{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	rule := rules.Rule{
+		ID:        dbr.ID,
+		Owner:     dbr.Owner,
+		Name:      dbr.Name.String,
+		Project:   dbr.Project,
+		Condition: dbr.Condition,
+		Actions:   []rules.Action{},
+		Created:   dbr.Created,
+	}
+	for _, a := range actions {
+		rule.Actions = append(rule.Actions, rules.Action(a))
+	}
+
+	return rule, nil
+}
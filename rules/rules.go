@@ -0,0 +1,75 @@
+package rules
+
+import (
+	"context"
+	"time"
+)
+
+// Types of the actions triggered by the rules.
+const (
+	// PublishAction republishes the message to another project.
+	PublishAction = "publish"
+
+	// WebhookAction posts the message to the webhook URL.
+	WebhookAction = "webhook"
+
+	// LogAction writes the message to the service log.
+	LogAction = "log"
+)
+
+// Action represents the action triggered when the rule condition is met.
+// Project and Subtopic specify where the message is republished to by the
+// publish action, while URL specifies the webhook called by the webhook
+// action.
+type Action struct {
+	Type     string
+	Project  string
+	Subtopic string
+	URL      string
+}
+
+// Rule represents the condition evaluated against each message published to
+// the project, along with the actions triggered when the condition is met.
+type Rule struct {
+	ID        string
+	Owner     string
+	Name      string
+	Project   string
+	Condition string
+	Actions   []Action
+	Created   time.Time
+}
+
+// RulesPage contains page related metadata as well as the list of rules
+// that belong to this page.
+type RulesPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Rules  []Rule
+}
+
+// RuleRepository specifies a rule persistence API.
+type RuleRepository interface {
+	// Save persists the rule.
+	Save(ctx context.Context, rule Rule) error
+
+	// Update updates the name, the project, the condition and the actions
+	// of the rule.
+	Update(ctx context.Context, rule Rule) error
+
+	// RetrieveByID retrieves the rule having the provided identifier, that
+	// is owned by the specified user.
+	RetrieveByID(ctx context.Context, owner, id string) (Rule, error)
+
+	// RetrieveByProject retrieves all rules evaluated against the messages
+	// published to the project having the provided identifier.
+	RetrieveByProject(ctx context.Context, project string) ([]Rule, error)
+
+	// RetrieveAll retrieves the subset of rules owned by the specified user.
+	RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (RulesPage, error)
+
+	// Remove removes the rule having the provided identifier, that is owned
+	// by the specified user.
+	Remove(ctx context.Context, owner, id string) error
+}
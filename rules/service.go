@@ -0,0 +1,327 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/authn"
+	"github.com/vietquy/alpha/errors"
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/transformer"
+)
+
+// Protocol is the protocol of the messages republished by the rules. Such
+// messages are not evaluated against the rules, so that the rules can't
+// trigger each other endlessly.
+const Protocol = "rules"
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrConflict indicates that the entity already exists.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrThings indicates failed communication with the things service.
+	ErrThings = errors.New("things service request failed")
+
+	// ErrAction indicates the failure of the action triggered by the rule.
+	ErrAction = errors.New("rule action failed")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// AddRule adds the rule to the user identified by the provided key.
+	AddRule(ctx context.Context, token string, rule Rule) (Rule, error)
+
+	// UpdateRule updates the name, the project, the condition and the
+	// actions of the rule.
+	UpdateRule(ctx context.Context, token string, rule Rule) error
+
+	// ViewRule retrieves the rule having the provided ID, that belongs to
+	// the user identified by the provided key.
+	ViewRule(ctx context.Context, token, id string) (Rule, error)
+
+	// ListRules retrieves the subset of rules that belong to the user
+	// identified by the provided key.
+	ListRules(ctx context.Context, token string, offset, limit uint64) (RulesPage, error)
+
+	// RemoveRule removes the rule having the provided ID, that belongs to
+	// the user identified by the provided key.
+	RemoveRule(ctx context.Context, token, id string) error
+
+	// Evaluate evaluates the rules of the project the message is published
+	// to, triggering the actions of the rules whose conditions are met.
+	Evaluate(ctx context.Context, msg messaging.Message) error
+}
+
+var _ Service = (*rulesService)(nil)
+
+type rulesService struct {
+	auth      alpha.AuthNServiceClient
+	rules     RuleRepository
+	things    ThingsService
+	access    alpha.ThingsServiceClient
+	idp       IdentityProvider
+	publisher messaging.Publisher
+	webhooks  Webhooks
+	logger    log.Logger
+}
+
+// New instantiates the rules service implementation. Messages are
+// republished by the publish actions only if the things service policies
+// allow the publisher of the message to publish to the target project.
+func New(auth alpha.AuthNServiceClient, rules RuleRepository, things ThingsService, access alpha.ThingsServiceClient, idp IdentityProvider, publisher messaging.Publisher, webhooks Webhooks, logger log.Logger) Service {
+	return &rulesService{
+		auth:      auth,
+		rules:     rules,
+		things:    things,
+		access:    access,
+		idp:       idp,
+		publisher: publisher,
+		webhooks:  webhooks,
+		logger:    logger,
+	}
+}
+
+func (rs rulesService) AddRule(ctx context.Context, token string, rule Rule) (Rule, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if err := rs.validate(ctx, token, rule); err != nil {
+		return Rule{}, err
+	}
+
+	rule.ID, err = rs.idp.ID()
+	if err != nil {
+		return Rule{}, err
+	}
+
+	rule.Owner = owner
+	rule.Created = time.Now().UTC()
+
+	if err := rs.rules.Save(ctx, rule); err != nil {
+		return Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func (rs rulesService) UpdateRule(ctx context.Context, token string, rule Rule) error {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if _, err := rs.rules.RetrieveByID(ctx, owner, rule.ID); err != nil {
+		return err
+	}
+
+	if err := rs.validate(ctx, token, rule); err != nil {
+		return err
+	}
+
+	rule.Owner = owner
+
+	return rs.rules.Update(ctx, rule)
+}
+
+func (rs rulesService) ViewRule(ctx context.Context, token, id string) (Rule, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return rs.rules.RetrieveByID(ctx, owner, id)
+}
+
+func (rs rulesService) ListRules(ctx context.Context, token string, offset, limit uint64) (RulesPage, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return RulesPage{}, err
+	}
+
+	return rs.rules.RetrieveAll(ctx, owner, offset, limit)
+}
+
+func (rs rulesService) RemoveRule(ctx context.Context, token, id string) error {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return rs.rules.Remove(ctx, owner, id)
+}
+
+func (rs rulesService) Evaluate(ctx context.Context, msg messaging.Message) error {
+	if msg.Protocol == Protocol {
+		return nil
+	}
+
+	rules, err := rs.rules.RetrieveByProject(ctx, msg.Project)
+	if err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	vals := Values(msg)
+
+	// Failures are logged rather than returned, since the redelivered
+	// message would trigger the actions that already succeeded again.
+	for _, rule := range rules {
+		cond, err := Parse(rule.Condition)
+		if err != nil {
+			rs.logger.Warn(fmt.Sprintf("Failed to parse condition of rule %s: %s", rule.ID, err))
+			continue
+		}
+
+		matched := false
+		for _, v := range vals {
+			if cond.Evaluate(v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		for _, action := range rule.Actions {
+			if err := rs.trigger(ctx, rule, action, msg); err != nil {
+				rs.logger.Warn(fmt.Sprintf("Failed to trigger %s action of rule %s: %s", action.Type, rule.ID, errors.Wrap(ErrAction, err)))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (rs rulesService) trigger(ctx context.Context, rule Rule, action Action, msg messaging.Message) error {
+	switch action.Type {
+	case PublishAction:
+		req := &alpha.AccessByIDReq{ThingID: msg.Publisher, ProjectID: action.Project, Subtopic: action.Subtopic}
+		if _, err := rs.access.CanPublish(ctx, req); err != nil {
+			return errors.Wrap(ErrUnauthorizedAccess, err)
+		}
+		m := messaging.Message{
+			Project:   action.Project,
+			Subtopic:  action.Subtopic,
+			Publisher: msg.Publisher,
+			Protocol:  Protocol,
+			Payload:   msg.Payload,
+			Created:   time.Now().UnixNano(),
+			Headers:   msg.Headers,
+		}
+		return rs.publisher.Publish(m.Project, m)
+	case WebhookAction:
+		return rs.webhooks.Call(ctx, action.URL, msg)
+	case LogAction:
+		rs.logger.Info(fmt.Sprintf("Rule %s matched message of thing %s published to project %s and subtopic %s: %s", rule.ID, msg.Publisher, msg.Project, msg.Subtopic, msg.Payload))
+		return nil
+	default:
+		return errors.New(fmt.Sprintf("unknown action type %s", action.Type))
+	}
+}
+
+// validate checks that the condition of the rule can be parsed, and that the
+// user can access the projects the rule is evaluated for and publishes to.
+func (rs rulesService) validate(ctx context.Context, token string, rule Rule) error {
+	if _, err := Parse(rule.Condition); err != nil {
+		return errors.Wrap(ErrMalformedEntity, err)
+	}
+
+	if err := rs.things.ViewProject(ctx, token, rule.Project); err != nil {
+		return err
+	}
+
+	for _, action := range rule.Actions {
+		if action.Type != PublishAction {
+			continue
+		}
+		if action.Project == rule.Project {
+			continue
+		}
+		if err := rs.things.ViewProject(ctx, token, action.Project); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// identify returns the owner of the rules of the user identified by the
+// provided token, see authn.Owner.
+func (rs rulesService) identify(ctx context.Context, token string) (string, error) {
+	res, err := rs.auth.Identify(ctx, &alpha.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return authn.Owner(res.GetValue(), res.GetOrganization()), nil
+}
+
+// Values returns the values the conditions are evaluated against, being the
+// flattened message payload along with the message project, subtopic,
+// publisher and protocol. The payload being the array of objects gives the
+// values for each object, while the payload that is not a JSON object gives
+// the message fields only.
+func Values(msg messaging.Message) []map[string]interface{} {
+	fields := map[string]interface{}{
+		"project":   msg.Project,
+		"subtopic":  msg.Subtopic,
+		"publisher": msg.Publisher,
+		"protocol":  msg.Protocol,
+	}
+
+	var p interface{}
+	if err := json.Unmarshal(msg.Payload, &p); err != nil {
+		return []map[string]interface{}{fields}
+	}
+
+	var objs []interface{}
+	switch val := p.(type) {
+	case map[string]interface{}:
+		objs = []interface{}{val}
+	case []interface{}:
+		objs = val
+	}
+
+	vals := []map[string]interface{}{}
+	for _, o := range objs {
+		obj, ok := o.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		flat, err := transformer.Flatten(obj)
+		if err != nil {
+			continue
+		}
+		for k, v := range fields {
+			flat[k] = v
+		}
+		vals = append(vals, flat)
+	}
+
+	if len(vals) == 0 {
+		return []map[string]interface{}{fields}
+	}
+
+	return vals
+}
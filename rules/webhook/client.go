@@ -0,0 +1,80 @@
+// Package webhook provides the client delivering the messages to webhooks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/rules"
+)
+
+const contentType = "application/json"
+
+var _ rules.Webhooks = (*client)(nil)
+
+type client struct {
+	http *http.Client
+}
+
+// NewClient instantiates the webhook client, posting the messages as JSON
+// documents and failing if the webhook doesn't respond within the timeout.
+func NewClient(timeout time.Duration) rules.Webhooks {
+	return &client{
+		http: &http.Client{Timeout: timeout},
+	}
+}
+
+// message represents the message posted to the webhook. The payload that is
+// a valid JSON is embedded as is, while the other payloads are encoded as
+// base64 strings.
+type message struct {
+	Project   string      `json:"project"`
+	Subtopic  string      `json:"subtopic,omitempty"`
+	Publisher string      `json:"publisher"`
+	Protocol  string      `json:"protocol"`
+	Created   int64       `json:"created"`
+	Payload   interface{} `json:"payload"`
+}
+
+func (c client) Call(ctx context.Context, url string, msg messaging.Message) error {
+	m := message{
+		Project:   msg.Project,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Created:   msg.Created,
+		Payload:   msg.Payload,
+	}
+	if json.Valid(msg.Payload) {
+		m.Payload = json.RawMessage(msg.Payload)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.New(fmt.Sprintf("webhook responded with status %d", res.StatusCode))
+	}
+
+	return nil
+}
@@ -17,6 +17,8 @@ type grpcClient struct {
 	timeout        time.Duration
 	canAccessByKey endpoint.Endpoint
 	canAccessByID  endpoint.Endpoint
+	canPublish     endpoint.Endpoint
+	canSubscribe   endpoint.Endpoint
 	identify       endpoint.Endpoint
 }
 
@@ -42,6 +44,22 @@ func NewClient(conn *grpc.ClientConn, timeout time.Duration) alpha.ThingsService
 			decodeEmptyResponse,
 			empty.Empty{},
 		).Endpoint(),
+		canPublish: kitgrpc.NewClient(
+			conn,
+			svcName,
+			"CanPublish",
+			encodeCanPublishRequest,
+			decodeIdentityResponse,
+			alpha.ThingID{},
+		).Endpoint(),
+		canSubscribe: kitgrpc.NewClient(
+			conn,
+			svcName,
+			"CanSubscribe",
+			encodeCanAccessByIDRequest,
+			decodeEmptyResponse,
+			empty.Empty{},
+		).Endpoint(),
 		identify: kitgrpc.NewClient(
 			conn,
 			svcName,
@@ -81,6 +99,43 @@ func (client grpcClient) CanAccessByID(ctx context.Context, req *alpha.AccessByI
 	return &empty.Empty{}, er.err
 }
 
+func (client grpcClient) CanPublish(ctx context.Context, req *alpha.AccessByIDReq, _ ...grpc.CallOption) (*alpha.ThingID, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	pr := publishReq{
+		thingID:   req.GetThingID(),
+		thingKey:  req.GetToken(),
+		projectID: req.GetProjectID(),
+		subtopic:  req.GetSubtopic(),
+	}
+	res, err := client.canPublish(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	ir := res.(identityRes)
+	return &alpha.ThingID{Value: ir.id}, ir.err
+}
+
+func (client grpcClient) CanSubscribe(ctx context.Context, req *alpha.AccessByIDReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	ar := accessByIDReq{
+		thingID:   req.GetThingID(),
+		projectID: req.GetProjectID(),
+		subtopic:  req.GetSubtopic(),
+	}
+	res, err := client.canSubscribe(ctx, ar)
+	if err != nil {
+		return nil, err
+	}
+
+	er := res.(emptyRes)
+	return &empty.Empty{}, er.err
+}
+
 func (client grpcClient) Identify(ctx context.Context, req *alpha.Token, _ ...grpc.CallOption) (*alpha.ThingID, error) {
 	ctx, cancel := context.WithTimeout(ctx, client.timeout)
 	defer cancel()
@@ -99,9 +154,23 @@ func encodeCanAccessByKeyRequest(_ context.Context, grpcReq interface{}) (interf
 	return &alpha.AccessByKeyReq{Token: req.thingKey, ProjectID: req.projectID}, nil
 }
 
+func encodeCanPublishRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(publishReq)
+	return &alpha.AccessByIDReq{
+		ThingID:   req.thingID,
+		Token:     req.thingKey,
+		ProjectID: req.projectID,
+		Subtopic:  req.subtopic,
+	}, nil
+}
+
 func encodeCanAccessByIDRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(accessByIDReq)
-	return &alpha.AccessByIDReq{ThingID: req.thingID, ProjectID: req.projectID}, nil
+	return &alpha.AccessByIDReq{
+		ThingID:   req.thingID,
+		ProjectID: req.projectID,
+		Subtopic:  req.subtopic,
+	}, nil
 }
 
 func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -33,6 +33,41 @@ func canAccessByIDEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func canPublishEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(publishReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		id := req.thingID
+		if id == "" {
+			thingID, err := svc.CanAccessByKey(ctx, req.projectID, req.thingKey)
+			if err != nil {
+				return identityRes{err: err}, err
+			}
+			id = thingID
+		}
+
+		if err := svc.CanPublish(ctx, req.projectID, id, req.subtopic); err != nil {
+			return identityRes{err: err}, err
+		}
+		return identityRes{id: id, err: nil}, nil
+	}
+}
+
+func canSubscribeEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(accessByIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		err := svc.CanSubscribe(ctx, req.projectID, req.thingID, req.subtopic)
+		return emptyRes{err: err}, err
+	}
+}
+
 func identifyEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(identifyReq)
@@ -16,8 +16,9 @@ func (req AccessByKeyReq) validate() error {
 }
 
 type accessByIDReq struct {
-	thingID string
-	projectID  string
+	thingID   string
+	projectID string
+	subtopic  string
 }
 
 func (req accessByIDReq) validate() error {
@@ -28,6 +29,23 @@ func (req accessByIDReq) validate() error {
 	return nil
 }
 
+// publishReq identifies the publisher by the thing ID, or by the thing key
+// if the ID is empty.
+type publishReq struct {
+	thingID   string
+	thingKey  string
+	projectID string
+	subtopic  string
+}
+
+func (req publishReq) validate() error {
+	if req.projectID == "" || (req.thingID == "" && req.thingKey == "") {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type identifyReq struct {
 	key string
 }
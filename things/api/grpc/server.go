@@ -15,6 +15,8 @@ var _ alpha.ThingsServiceServer = (*grpcServer)(nil)
 type grpcServer struct {
 	canAccessByKey kitgrpc.Handler
 	canAccessByID  kitgrpc.Handler
+	canPublish     kitgrpc.Handler
+	canSubscribe   kitgrpc.Handler
 	identify       kitgrpc.Handler
 }
 
@@ -31,6 +33,16 @@ func NewServer(svc things.Service) alpha.ThingsServiceServer {
 			decodeCanAccessByIDRequest,
 			encodeEmptyResponse,
 		),
+		canPublish: kitgrpc.NewServer(
+			canPublishEndpoint(svc),
+			decodeCanPublishRequest,
+			encodeIdentityResponse,
+		),
+		canSubscribe: kitgrpc.NewServer(
+			canSubscribeEndpoint(svc),
+			decodeCanAccessByIDRequest,
+			encodeEmptyResponse,
+		),
 		identify: kitgrpc.NewServer(
 			identifyEndpoint(svc),
 			decodeIdentifyRequest,
@@ -57,6 +69,24 @@ func (gs *grpcServer) CanAccessByID(ctx context.Context, req *alpha.AccessByIDRe
 	return res.(*empty.Empty), nil
 }
 
+func (gs *grpcServer) CanPublish(ctx context.Context, req *alpha.AccessByIDReq) (*alpha.ThingID, error) {
+	_, res, err := gs.canPublish.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*alpha.ThingID), nil
+}
+
+func (gs *grpcServer) CanSubscribe(ctx context.Context, req *alpha.AccessByIDReq) (*empty.Empty, error) {
+	_, res, err := gs.canSubscribe.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*empty.Empty), nil
+}
+
 func (gs *grpcServer) Identify(ctx context.Context, req *alpha.Token) (*alpha.ThingID, error) {
 	_, res, err := gs.identify.ServeGRPC(ctx, req)
 	if err != nil {
@@ -73,7 +103,21 @@ func decodeCanAccessByKeyRequest(_ context.Context, grpcReq interface{}) (interf
 
 func decodeCanAccessByIDRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*alpha.AccessByIDReq)
-	return accessByIDReq{thingID: req.GetThingID(), projectID: req.GetProjectID()}, nil
+	return accessByIDReq{
+		thingID:   req.GetThingID(),
+		projectID: req.GetProjectID(),
+		subtopic:  req.GetSubtopic(),
+	}, nil
+}
+
+func decodeCanPublishRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*alpha.AccessByIDReq)
+	return publishReq{
+		thingID:   req.GetThingID(),
+		thingKey:  req.GetToken(),
+		projectID: req.GetProjectID(),
+		subtopic:  req.GetSubtopic(),
+	}, nil
 }
 
 func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
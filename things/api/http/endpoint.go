@@ -116,6 +116,169 @@ func updateKeyEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func rotateKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		key, err := svc.RotateKey(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := keyRes{
+			Key:       key.Value,
+			CreatedAt: key.CreatedAt,
+			created:   true,
+		}
+		return res, nil
+	}
+}
+
+func listKeysEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		keys, err := svc.ListKeys(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := keysRes{Keys: []keyRes{}}
+		for _, key := range keys {
+			kr := keyRes{
+				Key:       key.Value,
+				CreatedAt: key.CreatedAt,
+				Revoked:   key.Revoked,
+			}
+			if !key.ExpiresAt.IsZero() {
+				expiresAt := key.ExpiresAt
+				kr.ExpiresAt = &expiresAt
+			}
+			res.Keys = append(res.Keys, kr)
+		}
+
+		return res, nil
+	}
+}
+
+func revokeKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(revokeKeyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeKey(ctx, req.token, req.id, req.key); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func shareEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(shareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		share := things.Share{
+			Resource: req.resource,
+			ID:       req.id,
+			Member:   req.Email,
+			Role:     req.Role,
+		}
+		if err := svc.Share(ctx, req.token, share); err != nil {
+			return nil, err
+		}
+
+		return shareRes{}, nil
+	}
+}
+
+func listSharesEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewShareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		shares, err := svc.ListShares(ctx, req.token, req.resource, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := membersRes{Members: []memberRes{}}
+		for _, share := range shares {
+			res.Members = append(res.Members, memberRes{
+				Email: share.Member,
+				Role:  share.Role,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func unshareEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewShareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if req.member == "" {
+			return nil, things.ErrMalformedEntity
+		}
+
+		if err := svc.Unshare(ctx, req.token, req.resource, req.id, req.member); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listSharedEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listSharedReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		shares, err := svc.ListShared(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := sharedRes{Shared: []sharedResourceRes{}}
+		for _, share := range shares {
+			res.Shared = append(res.Shared, sharedResourceRes{
+				Resource: share.Resource,
+				ID:       share.ID,
+				Owner:    share.Owner,
+				Role:     share.Role,
+			})
+		}
+
+		return res, nil
+	}
+}
+
 func viewThingEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(viewResourceReq)
@@ -130,11 +293,13 @@ func viewThingEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 
 		res := viewThingRes{
-			ID:       thing.ID,
-			Owner:    thing.Owner,
-			Name:     thing.Name,
-			Key:      thing.Key,
-			Metadata: thing.Metadata,
+			ID:        thing.ID,
+			Owner:     thing.Owner,
+			Name:      thing.Name,
+			Key:       thing.Key,
+			Metadata:  thing.Metadata,
+			CreatedAt: thing.CreatedAt,
+			UpdatedAt: thing.UpdatedAt,
 		}
 		return res, nil
 	}
@@ -148,7 +313,7 @@ func listThingsEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		page, err := svc.ListThings(ctx, req.token, req.offset, req.limit, req.name, req.metadata)
+		page, err := svc.ListThings(ctx, req.token, req.pageMetadata)
 		if err != nil {
 			return nil, err
 		}
@@ -163,11 +328,13 @@ func listThingsEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 		for _, thing := range page.Things {
 			view := viewThingRes{
-				ID:       thing.ID,
-				Owner:    thing.Owner,
-				Name:     thing.Name,
-				Key:      thing.Key,
-				Metadata: thing.Metadata,
+				ID:        thing.ID,
+				Owner:     thing.Owner,
+				Name:      thing.Name,
+				Key:       thing.Key,
+				Metadata:  thing.Metadata,
+				CreatedAt: thing.CreatedAt,
+				UpdatedAt: thing.UpdatedAt,
 			}
 			res.Things = append(res.Things, view)
 		}
@@ -199,11 +366,13 @@ func listThingsByProjectEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 		for _, thing := range page.Things {
 			view := viewThingRes{
-				ID:       thing.ID,
-				Owner:    thing.Owner,
-				Key:      thing.Key,
-				Name:     thing.Name,
-				Metadata: thing.Metadata,
+				ID:        thing.ID,
+				Owner:     thing.Owner,
+				Key:       thing.Key,
+				Name:      thing.Name,
+				Metadata:  thing.Metadata,
+				CreatedAt: thing.CreatedAt,
+				UpdatedAt: thing.UpdatedAt,
 			}
 			res.Things = append(res.Things, view)
 		}
@@ -233,6 +402,23 @@ func removeThingEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func removeThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(bulkRemoveReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		results, err := svc.RemoveThings(ctx, req.token, req.IDs...)
+		if err != nil {
+			return nil, err
+		}
+
+		return toBulkRemoveRes(results), nil
+	}
+}
+
 func createProjectEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(createProjectReq)
@@ -334,10 +520,12 @@ func viewProjectEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 
 		res := viewProjectRes{
-			ID:       project.ID,
-			Owner:    project.Owner,
-			Name:     project.Name,
-			Metadata: project.Metadata,
+			ID:        project.ID,
+			Owner:     project.Owner,
+			Name:      project.Name,
+			Metadata:  project.Metadata,
+			CreatedAt: project.CreatedAt,
+			UpdatedAt: project.UpdatedAt,
 		}
 
 		return res, nil
@@ -352,7 +540,7 @@ func listProjectsEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		page, err := svc.ListProjects(ctx, req.token, req.offset, req.limit, req.name, req.metadata)
+		page, err := svc.ListProjects(ctx, req.token, req.pageMetadata)
 		if err != nil {
 			return nil, err
 		}
@@ -368,10 +556,12 @@ func listProjectsEndpoint(svc things.Service) endpoint.Endpoint {
 		// Cast projects
 		for _, project := range page.Projects {
 			view := viewProjectRes{
-				ID:       project.ID,
-				Owner:    project.Owner,
-				Name:     project.Name,
-				Metadata: project.Metadata,
+				ID:        project.ID,
+				Owner:     project.Owner,
+				Name:      project.Name,
+				Metadata:  project.Metadata,
+				CreatedAt: project.CreatedAt,
+				UpdatedAt: project.UpdatedAt,
 			}
 
 			res.Projects = append(res.Projects, view)
@@ -404,10 +594,12 @@ func listProjectsByThingEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 		for _, project := range page.Projects {
 			view := viewProjectRes{
-				ID:       project.ID,
-				Owner:    project.Owner,
-				Name:     project.Name,
-				Metadata: project.Metadata,
+				ID:        project.ID,
+				Owner:     project.Owner,
+				Name:      project.Name,
+				Metadata:  project.Metadata,
+				CreatedAt: project.CreatedAt,
+				UpdatedAt: project.UpdatedAt,
 			}
 			res.Projects = append(res.Projects, view)
 		}
@@ -435,15 +627,45 @@ func removeProjectEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func removeProjectsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(bulkRemoveReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		results, err := svc.RemoveProjects(ctx, req.token, req.IDs...)
+		if err != nil {
+			return nil, err
+		}
+
+		return toBulkRemoveRes(results), nil
+	}
+}
+
+func toBulkRemoveRes(results []things.BulkResult) bulkRemoveRes {
+	res := bulkRemoveRes{Results: []bulkResultRes{}}
+	for _, r := range results {
+		br := bulkResultRes{ID: r.ID}
+		if r.Err != nil {
+			br.Err = r.Err.Error()
+		}
+		res.Results = append(res.Results, br)
+	}
+
+	return res
+}
+
 func connectEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		cr := request.(connectionReq)
+		cr := request.(connectReq)
 
 		if err := cr.validate(); err != nil {
 			return nil, err
 		}
 
-		if err := svc.Connect(ctx, cr.token, []string{cr.projectID}, []string{cr.thingID}); err != nil {
+		if err := svc.Connect(ctx, cr.token, []string{cr.projectID}, []string{cr.thingID}, cr.policy()); err != nil {
 			return nil, err
 		}
 
@@ -459,7 +681,7 @@ func createConnectionsEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if err := svc.Connect(ctx, cr.token, cr.ProjectIDs, cr.ThingIDs); err != nil {
+		if err := svc.Connect(ctx, cr.token, cr.ProjectIDs, cr.ThingIDs, cr.policy()); err != nil {
 			return nil, err
 		}
 
@@ -482,3 +704,131 @@ func disconnectEndpoint(svc things.Service) endpoint.Endpoint {
 		return disconnectionRes{}, nil
 	}
 }
+
+func disconnectManyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(disconnectReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		results, err := svc.DisconnectMany(ctx, req.token, req.ProjectIDs, req.ThingIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		res := disconnectRes{Results: []disconnectResultRes{}}
+		for _, r := range results {
+			dr := disconnectResultRes{
+				ProjectID: r.ProjectID,
+				ThingID:   r.ThingID,
+			}
+			if r.Err != nil {
+				dr.Err = r.Err.Error()
+			}
+			res.Results = append(res.Results, dr)
+		}
+
+		return res, nil
+	}
+}
+
+func exportEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		backup, err := svc.Export(ctx, req.token, req.redact)
+		if err != nil {
+			return nil, err
+		}
+
+		res := backupRes{
+			format:      req.format,
+			Things:      []backupThing{},
+			Projects:    []backupProject{},
+			Connections: []backupConnection{},
+		}
+		for _, th := range backup.Things {
+			res.Things = append(res.Things, backupThing{
+				ID:       th.ID,
+				Name:     th.Name,
+				Key:      th.Key,
+				Metadata: th.Metadata,
+			})
+		}
+		for _, ch := range backup.Projects {
+			res.Projects = append(res.Projects, backupProject{
+				ID:       ch.ID,
+				Name:     ch.Name,
+				Metadata: ch.Metadata,
+			})
+		}
+		for _, conn := range backup.Connections {
+			res.Connections = append(res.Connections, backupConnection{
+				ProjectID: conn.ProjectID,
+				ThingID:   conn.ThingID,
+				Actions:   conn.Policy.Actions,
+				Subtopic:  conn.Policy.Subtopic,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func importEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		report, err := svc.Import(ctx, req.token, req.backup(), req.dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		res := importRes{
+			DryRun:      report.DryRun,
+			Things:      toImportResultsRes(report.Things),
+			Projects:    toImportResultsRes(report.Projects),
+			Connections: []connectionImportRes{},
+		}
+		for _, r := range report.Connections {
+			res.Connections = append(res.Connections, connectionImportRes{
+				ProjectID: r.ProjectID,
+				ThingID:   r.ThingID,
+				Status:    importStatus(r.Created),
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func toImportResultsRes(results []things.ImportResult) []importResultRes {
+	res := []importResultRes{}
+	for _, r := range results {
+		res = append(res, importResultRes{
+			ID:     r.ID,
+			NewID:  r.NewID,
+			Status: importStatus(r.Created),
+		})
+	}
+
+	return res
+}
+
+func importStatus(created bool) string {
+	if created {
+		return "created"
+	}
+
+	return "exists"
+}
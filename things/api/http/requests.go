@@ -6,6 +6,9 @@ import (
 
 const maxLimitSize = 100
 const maxNameSize = 1024
+const maxQuerySize = 1024
+const maxBulkSize = 1000
+const maxImportSize = 10000
 
 type apiReq interface {
 	validate() error
@@ -94,6 +97,75 @@ func (req updateKeyReq) validate() error {
 	return nil
 }
 
+type revokeKeyReq struct {
+	token string
+	id    string
+	key   string
+}
+
+func (req revokeKeyReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.key == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type shareReq struct {
+	token    string
+	resource string
+	id       string
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+func (req shareReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.Email == "" || !things.ValidRole(req.Role) {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewShareReq struct {
+	token    string
+	resource string
+	id       string
+	member   string
+}
+
+func (req viewShareReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listSharedReq struct {
+	token string
+}
+
+func (req listSharedReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
 type createProjectReq struct {
 	token    string
 	Name     string                 `json:"name,omitempty"`
@@ -176,11 +248,9 @@ func (req viewResourceReq) validate() error {
 }
 
 type listResourcesReq struct {
-	token    string
-	offset   uint64
-	limit    uint64
-	name     string
-	metadata map[string]interface{}
+	token        string
+	pageMetadata things.PageMetadata
+	querySize    int
 }
 
 func (req *listResourcesReq) validate() error {
@@ -188,11 +258,20 @@ func (req *listResourcesReq) validate() error {
 		return things.ErrUnauthorizedAccess
 	}
 
-	if req.limit == 0 || req.limit > maxLimitSize {
+	pm := req.pageMetadata
+	if pm.Limit == 0 || pm.Limit > maxLimitSize {
+		return things.ErrMalformedEntity
+	}
+
+	if len(pm.Name) > maxNameSize || req.querySize > maxQuerySize {
 		return things.ErrMalformedEntity
 	}
 
-	if len(req.name) > maxNameSize {
+	if pm.Order != "" && pm.Order != things.OrderName && pm.Order != things.OrderCreated {
+		return things.ErrMalformedEntity
+	}
+
+	if pm.Dir != "" && pm.Dir != things.DirAsc && pm.Dir != things.DirDesc {
 		return things.ErrMalformedEntity
 	}
 
@@ -240,10 +319,36 @@ func (req connectionReq) validate() error {
 	return nil
 }
 
+type connectReq struct {
+	token     string
+	projectID string
+	thingID   string
+	Actions   []string `json:"actions,omitempty"`
+	Subtopic  string   `json:"subtopic,omitempty"`
+}
+
+func (req connectReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.projectID == "" || req.thingID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return req.policy().Validate()
+}
+
+func (req connectReq) policy() things.Policy {
+	return toPolicy(req.Actions, req.Subtopic)
+}
+
 type createConnectionsReq struct {
 	token      string
 	ProjectIDs []string `json:"project_ids,omitempty"`
 	ThingIDs   []string `json:"thing_ids,omitempty"`
+	Actions    []string `json:"actions,omitempty"`
+	Subtopic   string   `json:"subtopic,omitempty"`
 }
 
 func (req createConnectionsReq) validate() error {
@@ -266,5 +371,157 @@ func (req createConnectionsReq) validate() error {
 		}
 	}
 
+	return req.policy().Validate()
+}
+
+func (req createConnectionsReq) policy() things.Policy {
+	return toPolicy(req.Actions, req.Subtopic)
+}
+
+type disconnectReq struct {
+	token      string
+	ProjectIDs []string `json:"project_ids,omitempty"`
+	ThingIDs   []string `json:"thing_ids,omitempty"`
+}
+
+func (req disconnectReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.ProjectIDs) == 0 || len(req.ThingIDs) == 0 {
+		return things.ErrMalformedEntity
+	}
+
+	if len(req.ProjectIDs)*len(req.ThingIDs) > maxBulkSize {
+		return things.ErrMalformedEntity
+	}
+
+	for _, chID := range req.ProjectIDs {
+		if chID == "" {
+			return things.ErrMalformedEntity
+		}
+	}
+	for _, thingID := range req.ThingIDs {
+		if thingID == "" {
+			return things.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type bulkRemoveReq struct {
+	token string
+	IDs   []string `json:"ids,omitempty"`
+}
+
+func (req bulkRemoveReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.IDs) == 0 || len(req.IDs) > maxBulkSize {
+		return things.ErrMalformedEntity
+	}
+
+	for _, id := range req.IDs {
+		if id == "" {
+			return things.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type exportReq struct {
+	token  string
+	format string
+	redact bool
+}
+
+func (req exportReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.format != formatJSON && req.format != formatCSV {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type importReq struct {
+	token       string
+	dryRun      bool
+	Things      []backupThing      `json:"things,omitempty"`
+	Projects    []backupProject    `json:"projects,omitempty"`
+	Connections []backupConnection `json:"connections,omitempty"`
+}
+
+func (req importReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.Things)+len(req.Projects)+len(req.Connections) > maxImportSize {
+		return things.ErrMalformedEntity
+	}
+
+	for _, th := range req.Things {
+		if len(th.Name) > maxNameSize {
+			return things.ErrMalformedEntity
+		}
+	}
+
+	for _, ch := range req.Projects {
+		if len(ch.Name) > maxNameSize {
+			return things.ErrMalformedEntity
+		}
+	}
+
 	return nil
 }
+
+func (req importReq) backup() things.Backup {
+	backup := things.Backup{}
+	for _, th := range req.Things {
+		backup.Things = append(backup.Things, things.Thing{
+			ID:       th.ID,
+			Name:     th.Name,
+			Key:      th.Key,
+			Metadata: th.Metadata,
+		})
+	}
+
+	for _, ch := range req.Projects {
+		backup.Projects = append(backup.Projects, things.Project{
+			ID:       ch.ID,
+			Name:     ch.Name,
+			Metadata: ch.Metadata,
+		})
+	}
+
+	for _, conn := range req.Connections {
+		backup.Connections = append(backup.Connections, things.Connection{
+			ProjectID: conn.ProjectID,
+			ThingID:   conn.ThingID,
+			Policy:    toPolicy(conn.Actions, conn.Subtopic),
+		})
+	}
+
+	return backup
+}
+
+// toPolicy returns connection policy, allowing all the actions if
+// none are specified.
+func toPolicy(actions []string, subtopic string) things.Policy {
+	policy := things.DefaultPolicy()
+	if len(actions) > 0 {
+		policy.Actions = actions
+	}
+	policy.Subtopic = subtopic
+
+	return policy
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/vietquy/alpha"
 )
@@ -11,12 +12,21 @@ var (
 	_ alpha.Response = (*removeRes)(nil)
 	_ alpha.Response = (*thingRes)(nil)
 	_ alpha.Response = (*viewThingRes)(nil)
+	_ alpha.Response = (*keyRes)(nil)
+	_ alpha.Response = (*keysRes)(nil)
+	_ alpha.Response = (*shareRes)(nil)
+	_ alpha.Response = (*membersRes)(nil)
+	_ alpha.Response = (*sharedRes)(nil)
 	_ alpha.Response = (*thingsPageRes)(nil)
 	_ alpha.Response = (*projectRes)(nil)
 	_ alpha.Response = (*viewProjectRes)(nil)
 	_ alpha.Response = (*projectsPageRes)(nil)
 	_ alpha.Response = (*connectionRes)(nil)
 	_ alpha.Response = (*disconnectionRes)(nil)
+	_ alpha.Response = (*disconnectRes)(nil)
+	_ alpha.Response = (*bulkRemoveRes)(nil)
+	_ alpha.Response = (*backupRes)(nil)
+	_ alpha.Response = (*importRes)(nil)
 )
 
 type removeRes struct{}
@@ -85,12 +95,112 @@ func (res thingsRes) Empty() bool {
 	return false
 }
 
+type keyRes struct {
+	Key       string     `json:"key"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Revoked   bool       `json:"revoked"`
+	created   bool
+}
+
+func (res keyRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res keyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res keyRes) Empty() bool {
+	return false
+}
+
+type keysRes struct {
+	Keys []keyRes `json:"keys"`
+}
+
+func (res keysRes) Code() int {
+	return http.StatusOK
+}
+
+func (res keysRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res keysRes) Empty() bool {
+	return false
+}
+
+type shareRes struct{}
+
+func (res shareRes) Code() int {
+	return http.StatusOK
+}
+
+func (res shareRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res shareRes) Empty() bool {
+	return true
+}
+
+type memberRes struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type membersRes struct {
+	Members []memberRes `json:"members"`
+}
+
+func (res membersRes) Code() int {
+	return http.StatusOK
+}
+
+func (res membersRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res membersRes) Empty() bool {
+	return false
+}
+
+type sharedResourceRes struct {
+	Resource string `json:"resource"`
+	ID       string `json:"id"`
+	Owner    string `json:"owner"`
+	Role     string `json:"role"`
+}
+
+type sharedRes struct {
+	Shared []sharedResourceRes `json:"shared"`
+}
+
+func (res sharedRes) Code() int {
+	return http.StatusOK
+}
+
+func (res sharedRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res sharedRes) Empty() bool {
+	return false
+}
+
 type viewThingRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID        string                 `json:"id"`
+	Owner     string                 `json:"-"`
+	Name      string                 `json:"name,omitempty"`
+	Key       string                 `json:"key"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
 }
 
 func (res viewThingRes) Code() int {
@@ -174,11 +284,13 @@ func (res projectsRes) Empty() bool {
 }
 
 type viewProjectRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Things   []viewThingRes         `json:"connected,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID        string                 `json:"id"`
+	Owner     string                 `json:"-"`
+	Name      string                 `json:"name,omitempty"`
+	Things    []viewThingRes         `json:"connected,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
 }
 
 func (res viewProjectRes) Code() int {
@@ -254,6 +366,119 @@ func (res disconnectionRes) Empty() bool {
 	return true
 }
 
+type disconnectResultRes struct {
+	ProjectID string `json:"project_id"`
+	ThingID   string `json:"thing_id"`
+	Err       string `json:"error,omitempty"`
+}
+
+type disconnectRes struct {
+	Results []disconnectResultRes `json:"results"`
+}
+
+func (res disconnectRes) Code() int {
+	return http.StatusOK
+}
+
+func (res disconnectRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res disconnectRes) Empty() bool {
+	return false
+}
+
+type bulkResultRes struct {
+	ID  string `json:"id"`
+	Err string `json:"error,omitempty"`
+}
+
+type bulkRemoveRes struct {
+	Results []bulkResultRes `json:"results"`
+}
+
+func (res bulkRemoveRes) Code() int {
+	return http.StatusOK
+}
+
+func (res bulkRemoveRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res bulkRemoveRes) Empty() bool {
+	return false
+}
+
+type backupThing struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Key      string                 `json:"key,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type backupProject struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type backupConnection struct {
+	ProjectID string   `json:"project_id"`
+	ThingID   string   `json:"thing_id"`
+	Actions   []string `json:"actions,omitempty"`
+	Subtopic  string   `json:"subtopic,omitempty"`
+}
+
+type backupRes struct {
+	format      string
+	Things      []backupThing      `json:"things"`
+	Projects    []backupProject    `json:"projects"`
+	Connections []backupConnection `json:"connections"`
+}
+
+func (res backupRes) Code() int {
+	return http.StatusOK
+}
+
+func (res backupRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res backupRes) Empty() bool {
+	return false
+}
+
+type importResultRes struct {
+	ID     string `json:"id"`
+	NewID  string `json:"new_id,omitempty"`
+	Status string `json:"status"`
+}
+
+type connectionImportRes struct {
+	ProjectID string `json:"project_id"`
+	ThingID   string `json:"thing_id"`
+	Status    string `json:"status"`
+}
+
+type importRes struct {
+	DryRun      bool                  `json:"dry_run"`
+	Things      []importResultRes     `json:"things"`
+	Projects    []importResultRes     `json:"projects"`
+	Connections []connectionImportRes `json:"connections"`
+}
+
+func (res importRes) Code() int {
+	return http.StatusOK
+}
+
+func (res importRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res importRes) Empty() bool {
+	return false
+}
+
 type pageRes struct {
 	Total  uint64 `json:"total"`
 	Offset uint64 `json:"offset"`
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -16,11 +17,22 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	offset      = "offset"
-	limit       = "limit"
-	name        = "name"
-	metadata    = "metadata"
+	contentType  = "application/json"
+	offset       = "offset"
+	limit        = "limit"
+	name         = "name"
+	metadata     = "metadata"
+	order        = "order"
+	dir          = "dir"
+	query        = "query"
+	disconnected = "disconnected"
+	format       = "format"
+	redact       = "redact"
+	dryRun       = "dry_run"
+
+	csvContentType = "text/csv"
+	formatJSON     = "json"
+	formatCSV      = "csv"
 
 	defOffset = 0
 	defLimit  = 10
@@ -53,6 +65,13 @@ func MakeHandler(svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/things/bulk/delete", kithttp.NewServer(
+		removeThingsEndpoint(svc),
+		decodeBulkRemove,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Patch("/things/:id/key", kithttp.NewServer(
 		updateKeyEndpoint(svc),
 		decodeKeyUpdate,
@@ -60,6 +79,27 @@ func MakeHandler(svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/things/:id/keys", kithttp.NewServer(
+		rotateKeyEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/keys", kithttp.NewServer(
+		listKeysEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/keys/:key", kithttp.NewServer(
+		revokeKeyEndpoint(svc),
+		decodeKeyRevocation,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Put("/things/:id", kithttp.NewServer(
 		updateThingEndpoint(svc),
 		decodeThingUpdate,
@@ -109,6 +149,13 @@ func MakeHandler(svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/projects/bulk/delete", kithttp.NewServer(
+		removeProjectsEndpoint(svc),
+		decodeBulkRemove,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Put("/projects/:id", kithttp.NewServer(
 		updateProjectEndpoint(svc),
 		decodeProjectUpdate,
@@ -146,7 +193,7 @@ func MakeHandler(svc things.Service) http.Handler {
 
 	r.Put("/projects/:projectId/things/:thingId", kithttp.NewServer(
 		connectEndpoint(svc),
-		decodeConnection,
+		decodeConnect,
 		encodeResponse,
 		opts...,
 	))
@@ -158,6 +205,13 @@ func MakeHandler(svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/disconnect", kithttp.NewServer(
+		disconnectManyEndpoint(svc),
+		decodeDisconnect,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Delete("/projects/:projectId/things/:thingId", kithttp.NewServer(
 		disconnectEndpoint(svc),
 		decodeConnection,
@@ -165,6 +219,69 @@ func MakeHandler(svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Get("/export", kithttp.NewServer(
+		exportEndpoint(svc),
+		decodeExport,
+		encodeBackup,
+		opts...,
+	))
+
+	r.Post("/import", kithttp.NewServer(
+		importEndpoint(svc),
+		decodeImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/shares", kithttp.NewServer(
+		shareEndpoint(svc),
+		decodeShare(things.ThingResource),
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/shares", kithttp.NewServer(
+		listSharesEndpoint(svc),
+		decodeShareView(things.ThingResource),
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/shares/:member", kithttp.NewServer(
+		unshareEndpoint(svc),
+		decodeShareView(things.ThingResource),
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/projects/:id/shares", kithttp.NewServer(
+		shareEndpoint(svc),
+		decodeShare(things.ProjectResource),
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/projects/:id/shares", kithttp.NewServer(
+		listSharesEndpoint(svc),
+		decodeShareView(things.ProjectResource),
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/projects/:id/shares/:member", kithttp.NewServer(
+		unshareEndpoint(svc),
+		decodeShareView(things.ProjectResource),
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/shared", kithttp.NewServer(
+		listSharedEndpoint(svc),
+		decodeListShared,
+		encodeResponse,
+		opts...,
+	))
+
 	r.GetFunc("/version", alpha.Version("things"))
 
 	return r
@@ -228,6 +345,16 @@ func decodeKeyUpdate(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeKeyRevocation(_ context.Context, r *http.Request) (interface{}, error) {
+	req := revokeKeyReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+		key:   bone.GetValue(r, "key"),
+	}
+
+	return req, nil
+}
+
 func decodeProjectCreation(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
@@ -301,12 +428,44 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
+	ord, err := readStringQuery(r, order)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := readStringQuery(r, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	qs, err := readStringQuery(r, query)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := things.ParseQuery(qs)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidQueryParams, err)
+	}
+
+	dc, err := readBoolQuery(r, disconnected)
+	if err != nil {
+		return nil, err
+	}
+
 	req := listResourcesReq{
-		token:    r.Header.Get("Authorization"),
-		offset:   o,
-		limit:    l,
-		name:     n,
-		metadata: m,
+		token: r.Header.Get("Authorization"),
+		pageMetadata: things.PageMetadata{
+			Offset:       o,
+			Limit:        l,
+			Name:         n,
+			Order:        ord,
+			Dir:          d,
+			Metadata:     m,
+			Query:        q,
+			Disconnected: dc,
+		},
+		querySize: len(qs),
 	}
 
 	return req, nil
@@ -343,6 +502,67 @@ func decodeConnection(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeConnect(_ context.Context, r *http.Request) (interface{}, error) {
+	req := connectReq{
+		token:     r.Header.Get("Authorization"),
+		projectID: bone.GetValue(r, "projectId"),
+		thingID:   bone.GetValue(r, "thingId"),
+	}
+
+	// The policy is optional, so is the request body.
+	if r.ContentLength == 0 {
+		return req, nil
+	}
+
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return nil, errors.Wrap(things.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeShare(resource string) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+			return nil, errUnsupportedContentType
+		}
+
+		req := shareReq{
+			token:    r.Header.Get("Authorization"),
+			resource: resource,
+			id:       bone.GetValue(r, "id"),
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, errors.Wrap(things.ErrMalformedEntity, err)
+		}
+
+		return req, nil
+	}
+}
+
+func decodeShareView(resource string) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		req := viewShareReq{
+			token:    r.Header.Get("Authorization"),
+			resource: resource,
+			id:       bone.GetValue(r, "id"),
+			member:   bone.GetValue(r, "member"),
+		}
+
+		return req, nil
+	}
+}
+
+func decodeListShared(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listSharedReq{token: r.Header.Get("Authorization")}
+
+	return req, nil
+}
+
 func decodeCreateConnections(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
@@ -356,6 +576,83 @@ func decodeCreateConnections(_ context.Context, r *http.Request) (interface{}, e
 	return req, nil
 }
 
+func decodeDisconnect(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := disconnectReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(things.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeBulkRemove(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := bulkRemoveReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(things.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeExport(_ context.Context, r *http.Request) (interface{}, error) {
+	f, err := readStringQuery(r, format)
+	if err != nil {
+		return nil, err
+	}
+	if f == "" {
+		f = formatJSON
+	}
+
+	rd, err := readBoolQuery(r, redact)
+	if err != nil {
+		return nil, err
+	}
+
+	req := exportReq{
+		token:  r.Header.Get("Authorization"),
+		format: f,
+		redact: rd,
+	}
+
+	return req, nil
+}
+
+func decodeImport(_ context.Context, r *http.Request) (interface{}, error) {
+	dr, err := readBoolQuery(r, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	req := importReq{
+		token:  r.Header.Get("Authorization"),
+		dryRun: dr,
+	}
+
+	ct := r.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(ct, contentType):
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, errors.Wrap(things.ErrMalformedEntity, err)
+		}
+	case strings.Contains(ct, csvContentType):
+		if err := decodeBackupCSV(r.Body, &req); err != nil {
+			return nil, errors.Wrap(things.ErrMalformedEntity, err)
+		}
+	default:
+		return nil, errUnsupportedContentType
+	}
+
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -374,6 +671,165 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
+// Backup is encoded in CSV format as a single table, where the first
+// column specifies whether the row describes a thing, a project or a
+// connection, and the rest of the columns not related to the row type are
+// left empty. Metadata is JSON-encoded, while the connection actions are
+// separated by the "|" character.
+var csvHeader = []string{"type", "id", "name", "key", "metadata", "project_id", "thing_id", "actions", "subtopic"}
+
+const (
+	csvThing      = "thing"
+	csvProject    = "project"
+	csvConnection = "connection"
+	csvActionsSep = "|"
+)
+
+func encodeBackup(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	res, ok := response.(backupRes)
+	if !ok || res.format != formatCSV {
+		return encodeResponse(ctx, w, response)
+	}
+
+	w.Header().Set("Content-Type", csvContentType)
+	w.WriteHeader(res.Code())
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, th := range res.Things {
+		md, err := encodeCSVMetadata(th.Metadata)
+		if err != nil {
+			return err
+		}
+		if err := cw.Write([]string{csvThing, th.ID, th.Name, th.Key, md, "", "", "", ""}); err != nil {
+			return err
+		}
+	}
+
+	for _, ch := range res.Projects {
+		md, err := encodeCSVMetadata(ch.Metadata)
+		if err != nil {
+			return err
+		}
+		if err := cw.Write([]string{csvProject, ch.ID, ch.Name, "", md, "", "", "", ""}); err != nil {
+			return err
+		}
+	}
+
+	for _, conn := range res.Connections {
+		actions := strings.Join(conn.Actions, csvActionsSep)
+		if err := cw.Write([]string{csvConnection, "", "", "", "", conn.ProjectID, conn.ThingID, actions, conn.Subtopic}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func encodeCSVMetadata(m map[string]interface{}) (string, error) {
+	if len(m) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// decodeBackupCSV decodes the backup encoded by encodeBackup. Columns are
+// looked up by the header names, so that their order doesn't matter.
+func decodeBackupCSV(r io.Reader, req *importReq) error {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return err
+	}
+
+	cols := map[string]int{}
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := cols["type"]; !ok {
+		return things.ErrMalformedEntity
+	}
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		col := func(name string) string {
+			i, ok := cols[name]
+			if !ok || i >= len(rec) {
+				return ""
+			}
+			return rec[i]
+		}
+
+		switch col("type") {
+		case csvThing:
+			md, err := decodeCSVMetadata(col("metadata"))
+			if err != nil {
+				return err
+			}
+			req.Things = append(req.Things, backupThing{
+				ID:       col("id"),
+				Name:     col("name"),
+				Key:      col("key"),
+				Metadata: md,
+			})
+		case csvProject:
+			md, err := decodeCSVMetadata(col("metadata"))
+			if err != nil {
+				return err
+			}
+			req.Projects = append(req.Projects, backupProject{
+				ID:       col("id"),
+				Name:     col("name"),
+				Metadata: md,
+			})
+		case csvConnection:
+			conn := backupConnection{
+				ProjectID: col("project_id"),
+				ThingID:   col("thing_id"),
+				Subtopic:  col("subtopic"),
+			}
+			if actions := col("actions"); actions != "" {
+				conn.Actions = strings.Split(actions, csvActionsSep)
+			}
+			req.Connections = append(req.Connections, conn)
+		default:
+			return things.ErrMalformedEntity
+		}
+	}
+}
+
+func decodeCSVMetadata(s string) (map[string]interface{}, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch errorVal := err.(type) {
 	case errors.Error:
@@ -440,6 +896,24 @@ func readStringQuery(r *http.Request, key string) (string, error) {
 	return vals[0], nil
 }
 
+func readBoolQuery(r *http.Request, key string) (bool, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return false, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(vals[0])
+	if err != nil {
+		return false, errInvalidQueryParams
+	}
+
+	return b, nil
+}
+
 func readMetadataQuery(r *http.Request, key string) (map[string]interface{}, error) {
 	vals := bone.GetQuery(r, key)
 	if len(vals) > 1 {
@@ -60,6 +60,45 @@ func (lm *loggingMiddleware) UpdateKey(ctx context.Context, token, id, key strin
 	return lm.svc.UpdateKey(ctx, token, id, key)
 }
 
+func (lm *loggingMiddleware) RotateKey(ctx context.Context, token, id string) (key things.ThingKey, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method rotate_key for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RotateKey(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListKeys(ctx context.Context, token, id string) (_ []things.ThingKey, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_keys for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListKeys(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) RevokeKey(ctx context.Context, token, id, key string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_key for thing %s and key %s took %s to complete", id, key, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeKey(ctx, token, id, key)
+}
+
 func (lm *loggingMiddleware) ViewThing(ctx context.Context, token, id string) (thing things.Thing, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method view_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
@@ -73,11 +112,11 @@ func (lm *loggingMiddleware) ViewThing(ctx context.Context, token, id string) (t
 	return lm.svc.ViewThing(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata things.Metadata) (_ things.Page, err error) {
+func (lm *loggingMiddleware) ListThings(ctx context.Context, token string, pm things.PageMetadata) (_ things.Page, err error) {
 	defer func(begin time.Time) {
 		nlog := ""
-		if name != "" {
-			nlog = fmt.Sprintf("with name %s ", name)
+		if pm.Name != "" {
+			nlog = fmt.Sprintf("with name %s ", pm.Name)
 		}
 		message := fmt.Sprintf("Method list_things %sfor token %s took %s to complete", nlog, token, time.Since(begin))
 		if err != nil {
@@ -87,7 +126,7 @@ func (lm *loggingMiddleware) ListThings(ctx context.Context, token string, offse
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListThings(ctx, token, offset, limit, name, metadata)
+	return lm.svc.ListThings(ctx, token, pm)
 }
 
 func (lm *loggingMiddleware) ListThingsByProject(ctx context.Context, token, id string, offset, limit uint64) (_ things.Page, err error) {
@@ -115,6 +154,19 @@ func (lm *loggingMiddleware) RemoveThing(ctx context.Context, token, id string)
 	return lm.svc.RemoveThing(ctx, token, id)
 }
 
+func (lm *loggingMiddleware) RemoveThings(ctx context.Context, token string, ids ...string) (results []things.BulkResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_things for token %s and things %s took %s to complete", token, ids, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveThings(ctx, token, ids...)
+}
+
 func (lm *loggingMiddleware) CreateProjects(ctx context.Context, token string, projects ...things.Project) (saved []things.Project, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method create_projects for token %s and projects %s took %s to complete", token, saved, time.Since(begin))
@@ -154,11 +206,11 @@ func (lm *loggingMiddleware) ViewProject(ctx context.Context, token, id string)
 	return lm.svc.ViewProject(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) ListProjects(ctx context.Context, token string, offset, limit uint64, name string, metadata things.Metadata) (_ things.ProjectsPage, err error) {
+func (lm *loggingMiddleware) ListProjects(ctx context.Context, token string, pm things.PageMetadata) (_ things.ProjectsPage, err error) {
 	defer func(begin time.Time) {
 		nlog := ""
-		if name != "" {
-			nlog = fmt.Sprintf("with name %s ", name)
+		if pm.Name != "" {
+			nlog = fmt.Sprintf("with name %s ", pm.Name)
 		}
 		message := fmt.Sprintf("Method list_projects %sfor token %s took %s to complete", nlog, token, time.Since(begin))
 		if err != nil {
@@ -168,7 +220,7 @@ func (lm *loggingMiddleware) ListProjects(ctx context.Context, token string, off
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListProjects(ctx, token, offset, limit, name, metadata)
+	return lm.svc.ListProjects(ctx, token, pm)
 }
 
 func (lm *loggingMiddleware) ListProjectsByThing(ctx context.Context, token, id string, offset, limit uint64) (_ things.ProjectsPage, err error) {
@@ -196,7 +248,20 @@ func (lm *loggingMiddleware) RemoveProject(ctx context.Context, token, id string
 	return lm.svc.RemoveProject(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) Connect(ctx context.Context, token string, chIDs, thIDs []string) (err error) {
+func (lm *loggingMiddleware) RemoveProjects(ctx context.Context, token string, ids ...string) (results []things.BulkResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_projects for token %s and projects %s took %s to complete", token, ids, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveProjects(ctx, token, ids...)
+}
+
+func (lm *loggingMiddleware) Connect(ctx context.Context, token string, chIDs, thIDs []string, policy things.Policy) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method connect for token %s, projects %s and things %s took %s to complete", token, chIDs, thIDs, time.Since(begin))
 		if err != nil {
@@ -206,7 +271,7 @@ func (lm *loggingMiddleware) Connect(ctx context.Context, token string, chIDs, t
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Connect(ctx, token, chIDs, thIDs)
+	return lm.svc.Connect(ctx, token, chIDs, thIDs, policy)
 }
 
 func (lm *loggingMiddleware) Disconnect(ctx context.Context, token, projectID, thingID string) (err error) {
@@ -222,6 +287,97 @@ func (lm *loggingMiddleware) Disconnect(ctx context.Context, token, projectID, t
 	return lm.svc.Disconnect(ctx, token, projectID, thingID)
 }
 
+func (lm *loggingMiddleware) DisconnectMany(ctx context.Context, token string, chIDs, thIDs []string) (results []things.DisconnectResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method disconnect_many for token %s, projects %s and things %s took %s to complete", token, chIDs, thIDs, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DisconnectMany(ctx, token, chIDs, thIDs)
+}
+
+func (lm *loggingMiddleware) Share(ctx context.Context, token string, share things.Share) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method share of %s %s with %s as %s took %s to complete", share.Resource, share.ID, share.Member, share.Role, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Share(ctx, token, share)
+}
+
+func (lm *loggingMiddleware) Unshare(ctx context.Context, token, resource, id, member string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unshare of %s %s with %s took %s to complete", resource, id, member, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Unshare(ctx, token, resource, id, member)
+}
+
+func (lm *loggingMiddleware) ListShares(ctx context.Context, token, resource, id string) (_ []things.Share, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_shares for token %s and %s %s took %s to complete", token, resource, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListShares(ctx, token, resource, id)
+}
+
+func (lm *loggingMiddleware) ListShared(ctx context.Context, token string) (_ []things.Share, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_shared for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListShared(ctx, token)
+}
+
+func (lm *loggingMiddleware) Export(ctx context.Context, token string, redact bool) (_ things.Backup, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Export(ctx, token, redact)
+}
+
+func (lm *loggingMiddleware) Import(ctx context.Context, token string, backup things.Backup, dryRun bool) (_ things.ImportReport, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import for token %s with dry run %t took %s to complete", token, dryRun, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Import(ctx, token, backup, dryRun)
+}
+
 func (lm *loggingMiddleware) CanAccessByKey(ctx context.Context, id, key string) (thing string, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method can_access for project %s and thing %s took %s to complete", id, thing, time.Since(begin))
@@ -247,6 +403,33 @@ func (lm *loggingMiddleware) CanAccessByID(ctx context.Context, projectID, thing
 
 	return lm.svc.CanAccessByID(ctx, projectID, thingID)
 }
+
+func (lm *loggingMiddleware) CanPublish(ctx context.Context, projectID, thingID, subtopic string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method can_publish for project %s, thing %s and subtopic %s took %s to complete", projectID, thingID, subtopic, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CanPublish(ctx, projectID, thingID, subtopic)
+}
+
+func (lm *loggingMiddleware) CanSubscribe(ctx context.Context, projectID, thingID, subtopic string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method can_subscribe for project %s, thing %s and subtopic %s took %s to complete", projectID, thingID, subtopic, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CanSubscribe(ctx, projectID, thingID, subtopic)
+}
+
 func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id string, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method identify for key %s and thing %s took %s to complete", key, id, time.Since(begin))
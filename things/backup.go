@@ -0,0 +1,68 @@
+package things
+
+// Backup contains all the things, projects and connections owned by the user.
+// Connections refer to the things and projects by their IDs in the backup.
+type Backup struct {
+	Things      []Thing
+	Projects    []Project
+	Connections []Connection
+}
+
+// ImportResult represents the result of the import of the single thing or
+// project. ID is the identifier of the resource in the backup, while NewID
+// identifies the created or the matching existing resource. NewID of the
+// resource to be created is empty in dry-run mode.
+type ImportResult struct {
+	ID      string
+	NewID   string
+	Created bool
+}
+
+// ConnectionImportResult represents the result of the import of the single
+// connection, referring to the connected thing and project by their IDs in
+// the backup.
+type ConnectionImportResult struct {
+	Connection
+	Created bool
+}
+
+// ImportReport contains the results of the import of each of the things,
+// projects and connections found in the backup.
+type ImportReport struct {
+	DryRun      bool
+	Things      []ImportResult
+	Projects    []ImportResult
+	Connections []ConnectionImportResult
+}
+
+// Validate returns ErrMalformedEntity if the backup contains things or
+// projects without unique IDs, connections referring to the resources
+// missing in the backup or invalid connection policies.
+func (b Backup) Validate() error {
+	ths := map[string]bool{}
+	for _, th := range b.Things {
+		if th.ID == "" || ths[th.ID] {
+			return ErrMalformedEntity
+		}
+		ths[th.ID] = true
+	}
+
+	chs := map[string]bool{}
+	for _, ch := range b.Projects {
+		if ch.ID == "" || chs[ch.ID] {
+			return ErrMalformedEntity
+		}
+		chs[ch.ID] = true
+	}
+
+	for _, conn := range b.Connections {
+		if !chs[conn.ProjectID] || !ths[conn.ThingID] {
+			return ErrMalformedEntity
+		}
+		if err := conn.Policy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
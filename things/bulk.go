@@ -0,0 +1,27 @@
+package things
+
+// BulkResult represents the result of the bulk operation on the single
+// thing or project. Err is nil if the operation succeeded, e.g. it's set to
+// ErrNotFound if the resource doesn't exist or to ErrUnauthorizedAccess if
+// the user isn't allowed to modify it.
+type BulkResult struct {
+	ID  string
+	Err error
+}
+
+// Connection represents the connection of the thing to the project. The
+// connected thing and project have the same owner.
+type Connection struct {
+	ProjectID string
+	ThingID   string
+	Owner     string
+	Policy    Policy
+}
+
+// DisconnectResult represents the result of the bulk disconnect of the
+// single project-thing pair. Err is nil if the pair is disconnected.
+type DisconnectResult struct {
+	ProjectID string
+	ThingID   string
+	Err       error
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+type entry struct {
+	key     string
+	value   string
+	expires time.Time
+}
+
+// lru is a size-bounded least recently used cache whose entries expire
+// after the configured TTL. Zero TTL disables expiration. It is not safe
+// for concurrent use, so callers are responsible for the synchronization.
+type lru struct {
+	size    int
+	ttl     time.Duration
+	ll      *list.List
+	items   map[string]*list.Element
+	onEvict func(key, value string)
+}
+
+func newLRU(size int, ttl time.Duration, onEvict func(key, value string)) *lru {
+	return &lru{
+		size:    size,
+		ttl:     ttl,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+		onEvict: onEvict,
+	}
+}
+
+func (c *lru) set(key, value string) {
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expires = expires
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry{key: key, value: value, expires: expires})
+	if c.size > 0 && c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *lru) get(key string) (string, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+
+	e := el.Value.(*entry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.removeElement(el)
+		return "", false
+	}
+
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *lru) remove(key string) {
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+func (c *lru) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	e := el.Value.(*entry)
+	delete(c.items, e.key)
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value)
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vietquy/alpha/things"
+)
+
+const (
+	sep       = ":"
+	policySep = "|"
+	actionSep = ","
+)
+
+var _ things.ProjectCache = (*projectCache)(nil)
+
+type projectCache struct {
+	mu       sync.Mutex
+	conns    *lru
+	projects map[string]map[string]bool
+	things   map[string]map[string]bool
+}
+
+// NewProjectCache returns in-memory LRU cache of project-thing connections
+// and their policies. The cache holds at most size connections, each of them
+// valid for the given ttl.
+func NewProjectCache(size int, ttl time.Duration) things.ProjectCache {
+	pc := &projectCache{
+		projects: make(map[string]map[string]bool),
+		things:   make(map[string]map[string]bool),
+	}
+	pc.conns = newLRU(size, ttl, pc.evict)
+
+	return pc
+}
+
+func (pc *projectCache) Connect(_ context.Context, projectID, thingID string, policy things.Policy) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.conns.set(connKey(projectID, thingID), encodePolicy(policy))
+	add(pc.projects, projectID, thingID)
+	add(pc.things, thingID, projectID)
+
+	return nil
+}
+
+func (pc *projectCache) Policy(_ context.Context, projectID, thingID string) (things.Policy, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	val, ok := pc.conns.get(connKey(projectID, thingID))
+	if !ok {
+		return things.Policy{}, things.ErrNotFound
+	}
+
+	return decodePolicy(val), nil
+}
+
+func (pc *projectCache) Disconnect(_ context.Context, projectID, thingID string) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.conns.remove(connKey(projectID, thingID))
+	return nil
+}
+
+func (pc *projectCache) Remove(_ context.Context, projectID string) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	for thingID := range pc.projects[projectID] {
+		pc.conns.remove(connKey(projectID, thingID))
+	}
+
+	return nil
+}
+
+func (pc *projectCache) RemoveThing(_ context.Context, thingID string) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	for projectID := range pc.things[thingID] {
+		pc.conns.remove(connKey(projectID, thingID))
+	}
+
+	return nil
+}
+
+// evict is called by the underlying LRU with pc.mu held.
+func (pc *projectCache) evict(key, _ string) {
+	ids := strings.SplitN(key, sep, 2)
+	if len(ids) != 2 {
+		return
+	}
+
+	del(pc.projects, ids[0], ids[1])
+	del(pc.things, ids[1], ids[0])
+}
+
+func connKey(projectID, thingID string) string {
+	return fmt.Sprintf("%s%s%s", projectID, sep, thingID)
+}
+
+// encodePolicy encodes the policy as actions joined by actionSep, followed
+// by policySep and the subtopic pattern. Actions never contain policySep,
+// so the first policySep always separates the two parts.
+func encodePolicy(policy things.Policy) string {
+	return strings.Join(policy.Actions, actionSep) + policySep + policy.Subtopic
+}
+
+func decodePolicy(val string) things.Policy {
+	parts := strings.SplitN(val, policySep, 2)
+	policy := things.Policy{}
+	if parts[0] != "" {
+		policy.Actions = strings.Split(parts[0], actionSep)
+	}
+	if len(parts) == 2 {
+		policy.Subtopic = parts[1]
+	}
+
+	return policy
+}
+
+func add(index map[string]map[string]bool, key, val string) {
+	if _, ok := index[key]; !ok {
+		index[key] = make(map[string]bool)
+	}
+	index[key][val] = true
+}
+
+func del(index map[string]map[string]bool, key, val string) {
+	vals, ok := index[key]
+	if !ok {
+		return
+	}
+
+	delete(vals, val)
+	if len(vals) == 0 {
+		delete(index, key)
+	}
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vietquy/alpha/things"
+)
+
+var _ things.ThingCache = (*thingCache)(nil)
+
+type thingCache struct {
+	mu   sync.Mutex
+	keys *lru
+	ids  map[string]map[string]bool
+}
+
+// NewThingCache returns in-memory LRU cache of thing keys. The cache
+// holds at most size entries, each of them valid for the given ttl.
+func NewThingCache(size int, ttl time.Duration) things.ThingCache {
+	tc := &thingCache{
+		ids: make(map[string]map[string]bool),
+	}
+	tc.keys = newLRU(size, ttl, tc.evict)
+
+	return tc
+}
+
+func (tc *thingCache) Save(_ context.Context, key, id string) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	// Entries are indexed by key, so that saving a key leaves the other
+	// keys of the thing cached. The key is removed first in case it was
+	// cached for another thing.
+	tc.keys.remove(key)
+	tc.keys.set(key, id)
+	add(tc.ids, id, key)
+
+	return nil
+}
+
+func (tc *thingCache) ID(_ context.Context, key string) (string, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	id, ok := tc.keys.get(key)
+	if !ok {
+		return "", things.ErrNotFound
+	}
+
+	return id, nil
+}
+
+func (tc *thingCache) Remove(_ context.Context, id string) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	for key := range tc.ids[id] {
+		tc.keys.remove(key)
+	}
+
+	return nil
+}
+
+// evict is called by the underlying LRU with tc.mu held.
+func (tc *thingCache) evict(key, id string) {
+	del(tc.ids, id, key)
+}
@@ -0,0 +1,86 @@
+// Package client provides the client of the things service HTTP API, used by
+// the services checking the access to the things and the projects on behalf
+// of the user.
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vietquy/alpha/errors"
+)
+
+const (
+	thingsPrefix   = "/things/"
+	projectsPrefix = "/projects/"
+)
+
+// Errors holds the errors returned by the client, so that they are the ones
+// of the service using it.
+type Errors struct {
+	// Unauthorized is returned when the user can't access the entity.
+	Unauthorized error
+
+	// NotFound is returned when the entity doesn't exist.
+	NotFound error
+
+	// Failed wraps the errors of the failed requests.
+	Failed error
+}
+
+// Client is the client of the things service HTTP API.
+type Client struct {
+	url  string
+	http *http.Client
+	errs Errors
+}
+
+// New instantiates the client of the things service HTTP API served at the
+// provided URL, returning the provided errors.
+func New(url string, timeout time.Duration, errs Errors) *Client {
+	return &Client{
+		url:  strings.TrimSuffix(url, "/"),
+		http: &http.Client{Timeout: timeout},
+		errs: errs,
+	}
+}
+
+// ViewThing checks that the thing having the provided ID exists and is
+// accessible by the user.
+func (c Client) ViewThing(ctx context.Context, token, id string) error {
+	return c.view(ctx, token, thingsPrefix+id)
+}
+
+// ViewProject checks that the project having the provided ID exists and is
+// accessible by the user.
+func (c Client) ViewProject(ctx context.Context, token, id string) error {
+	return c.view(ctx, token, projectsPrefix+id)
+}
+
+func (c Client) view(ctx context.Context, token, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return errors.Wrap(c.errs.Failed, err)
+	}
+	req.Header.Set("Authorization", token)
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(c.errs.Failed, err)
+	}
+	res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusForbidden:
+		return c.errs.Unauthorized
+	case http.StatusNotFound:
+		return c.errs.NotFound
+	default:
+		return errors.Wrap(c.errs.Failed, errors.New(fmt.Sprintf("unexpected status %d", res.StatusCode)))
+	}
+}
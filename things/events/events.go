@@ -0,0 +1,409 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/vietquy/alpha/errors"
+)
+
+const (
+	thingPrefix       = "thing."
+	thingCreate       = thingPrefix + "create"
+	thingUpdate       = thingPrefix + "update"
+	thingUpdateKey    = thingPrefix + "update_key"
+	thingRevokeKey    = thingPrefix + "revoke_key"
+	thingRemove       = thingPrefix + "remove"
+	projectPrefix     = "project."
+	projectCreate     = projectPrefix + "create"
+	projectUpdate     = projectPrefix + "update"
+	projectRemove     = projectPrefix + "remove"
+	projectConnect    = projectPrefix + "connect"
+	projectDisconnect = projectPrefix + "disconnect"
+	share             = "share"
+	unshare           = "unshare"
+)
+
+var (
+	// ErrDecodeEvent indicates that received event couldn't be decoded.
+	ErrDecodeEvent = errors.New("failed to decode event")
+
+	errUnknownOperation = errors.New("unknown event operation")
+)
+
+// Event represents things service lifecycle event.
+type Event interface {
+	// Operation returns the name of the operation that caused the event,
+	// e.g. thing.create or project.connect.
+	Operation() string
+
+	// Encode returns the event payload.
+	Encode() map[string]interface{}
+}
+
+// CreateThingEvent is published when the thing is created.
+type CreateThingEvent struct {
+	ID         string                 `json:"id"`
+	Owner      string                 `json:"owner"`
+	Name       string                 `json:"name,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	OccurredAt int64                  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (cte CreateThingEvent) Operation() string {
+	return thingCreate
+}
+
+// Encode returns event payload.
+func (cte CreateThingEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":          cte.ID,
+		"owner":       cte.Owner,
+		"occurred_at": cte.OccurredAt,
+	}
+	if cte.Name != "" {
+		val["name"] = cte.Name
+	}
+	if cte.Metadata != nil {
+		val["metadata"] = cte.Metadata
+	}
+
+	return val
+}
+
+// UpdateThingEvent is published when the thing name or metadata is updated.
+type UpdateThingEvent struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	OccurredAt int64                  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (ute UpdateThingEvent) Operation() string {
+	return thingUpdate
+}
+
+// Encode returns event payload.
+func (ute UpdateThingEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":          ute.ID,
+		"occurred_at": ute.OccurredAt,
+	}
+	if ute.Name != "" {
+		val["name"] = ute.Name
+	}
+	if ute.Metadata != nil {
+		val["metadata"] = ute.Metadata
+	}
+
+	return val
+}
+
+// UpdateKeyEvent is published when the thing key is updated or rotated.
+// The key itself is never part of the event.
+type UpdateKeyEvent struct {
+	ID         string `json:"id"`
+	OccurredAt int64  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (uke UpdateKeyEvent) Operation() string {
+	return thingUpdateKey
+}
+
+// Encode returns event payload.
+func (uke UpdateKeyEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          uke.ID,
+		"occurred_at": uke.OccurredAt,
+	}
+}
+
+// RevokeKeyEvent is published when one of the thing keys is revoked.
+type RevokeKeyEvent struct {
+	ID         string `json:"id"`
+	OccurredAt int64  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (rke RevokeKeyEvent) Operation() string {
+	return thingRevokeKey
+}
+
+// Encode returns event payload.
+func (rke RevokeKeyEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          rke.ID,
+		"occurred_at": rke.OccurredAt,
+	}
+}
+
+// RemoveThingEvent is published when the thing is removed.
+type RemoveThingEvent struct {
+	ID         string `json:"id"`
+	OccurredAt int64  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (rte RemoveThingEvent) Operation() string {
+	return thingRemove
+}
+
+// Encode returns event payload.
+func (rte RemoveThingEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          rte.ID,
+		"occurred_at": rte.OccurredAt,
+	}
+}
+
+// CreateProjectEvent is published when the project is created.
+type CreateProjectEvent struct {
+	ID         string                 `json:"id"`
+	Owner      string                 `json:"owner"`
+	Name       string                 `json:"name,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	OccurredAt int64                  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (cpe CreateProjectEvent) Operation() string {
+	return projectCreate
+}
+
+// Encode returns event payload.
+func (cpe CreateProjectEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":          cpe.ID,
+		"owner":       cpe.Owner,
+		"occurred_at": cpe.OccurredAt,
+	}
+	if cpe.Name != "" {
+		val["name"] = cpe.Name
+	}
+	if cpe.Metadata != nil {
+		val["metadata"] = cpe.Metadata
+	}
+
+	return val
+}
+
+// UpdateProjectEvent is published when the project name or metadata is
+// updated.
+type UpdateProjectEvent struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	OccurredAt int64                  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (upe UpdateProjectEvent) Operation() string {
+	return projectUpdate
+}
+
+// Encode returns event payload.
+func (upe UpdateProjectEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":          upe.ID,
+		"occurred_at": upe.OccurredAt,
+	}
+	if upe.Name != "" {
+		val["name"] = upe.Name
+	}
+	if upe.Metadata != nil {
+		val["metadata"] = upe.Metadata
+	}
+
+	return val
+}
+
+// RemoveProjectEvent is published when the project is removed.
+type RemoveProjectEvent struct {
+	ID         string `json:"id"`
+	OccurredAt int64  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (rpe RemoveProjectEvent) Operation() string {
+	return projectRemove
+}
+
+// Encode returns event payload.
+func (rpe RemoveProjectEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          rpe.ID,
+		"occurred_at": rpe.OccurredAt,
+	}
+}
+
+// ConnectEvent is published when the thing is connected to the project.
+type ConnectEvent struct {
+	ProjectID  string   `json:"project_id"`
+	ThingID    string   `json:"thing_id"`
+	Actions    []string `json:"actions"`
+	Subtopic   string   `json:"subtopic,omitempty"`
+	OccurredAt int64    `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (ce ConnectEvent) Operation() string {
+	return projectConnect
+}
+
+// Encode returns event payload.
+func (ce ConnectEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"project_id":  ce.ProjectID,
+		"thing_id":    ce.ThingID,
+		"actions":     ce.Actions,
+		"occurred_at": ce.OccurredAt,
+	}
+	if ce.Subtopic != "" {
+		val["subtopic"] = ce.Subtopic
+	}
+
+	return val
+}
+
+// DisconnectEvent is published when the thing is disconnected from the
+// project.
+type DisconnectEvent struct {
+	ProjectID  string `json:"project_id"`
+	ThingID    string `json:"thing_id"`
+	OccurredAt int64  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (de DisconnectEvent) Operation() string {
+	return projectDisconnect
+}
+
+// Encode returns event payload.
+func (de DisconnectEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"project_id":  de.ProjectID,
+		"thing_id":    de.ThingID,
+		"occurred_at": de.OccurredAt,
+	}
+}
+
+// ShareEvent is published when the thing or the project is shared with
+// another user. Its operation is prefixed by the shared resource, e.g.
+// thing.share.
+type ShareEvent struct {
+	Resource   string `json:"resource"`
+	ID         string `json:"id"`
+	Member     string `json:"member"`
+	Role       string `json:"role"`
+	OccurredAt int64  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (se ShareEvent) Operation() string {
+	return se.Resource + "." + share
+}
+
+// Encode returns event payload.
+func (se ShareEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"resource":    se.Resource,
+		"id":          se.ID,
+		"member":      se.Member,
+		"role":        se.Role,
+		"occurred_at": se.OccurredAt,
+	}
+}
+
+// UnshareEvent is published when the member access to the shared thing or
+// project is revoked.
+type UnshareEvent struct {
+	Resource   string `json:"resource"`
+	ID         string `json:"id"`
+	Member     string `json:"member"`
+	OccurredAt int64  `json:"occurred_at"`
+}
+
+// Operation returns event operation.
+func (ue UnshareEvent) Operation() string {
+	return ue.Resource + "." + unshare
+}
+
+// Encode returns event payload.
+func (ue UnshareEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"resource":    ue.Resource,
+		"id":          ue.ID,
+		"member":      ue.Member,
+		"occurred_at": ue.OccurredAt,
+	}
+}
+
+// Decode parses event published under the given operation.
+func Decode(operation string, data []byte) (Event, error) {
+	var ev Event
+	var err error
+	switch operation {
+	case thingCreate:
+		var e CreateThingEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case thingUpdate:
+		var e UpdateThingEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case thingUpdateKey:
+		var e UpdateKeyEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case thingRevokeKey:
+		var e RevokeKeyEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case thingRemove:
+		var e RemoveThingEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case projectCreate:
+		var e CreateProjectEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case projectUpdate:
+		var e UpdateProjectEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case projectRemove:
+		var e RemoveProjectEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case projectConnect:
+		var e ConnectEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case projectDisconnect:
+		var e DisconnectEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case thingPrefix + share, projectPrefix + share:
+		var e ShareEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	case thingPrefix + unshare, projectPrefix + unshare:
+		var e UnshareEvent
+		err = json.Unmarshal(data, &e)
+		ev = e
+	default:
+		return nil, errors.Wrap(ErrDecodeEvent, errUnknownOperation)
+	}
+	if err != nil {
+		return nil, errors.Wrap(ErrDecodeEvent, err)
+	}
+
+	return ev, nil
+}
+
+func now() int64 {
+	return time.Now().UnixNano()
+}
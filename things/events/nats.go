@@ -0,0 +1,126 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	broker "github.com/nats-io/nats.go"
+	"github.com/vietquy/alpha/errors"
+	log "github.com/vietquy/alpha/logger"
+)
+
+const subjectPrefix = "events.things"
+
+// SubjectAll represents subject to subscribe for all things service events.
+const SubjectAll = subjectPrefix + ".>"
+
+var errEmptySubject = errors.New("empty subject")
+
+// Publisher specifies things service event publishing API.
+type Publisher interface {
+	// Publish publishes the event to the events.things.<operation> subject.
+	Publish(ev Event) error
+
+	// Close closes the underlying connection.
+	Close()
+}
+
+// EventHandler represents Event handler for Subscriber.
+type EventHandler func(ev Event) error
+
+// Subscriber specifies things service event subscription API.
+type Subscriber interface {
+	// Subscribe consumes the events published under the given subject.
+	// Use SubjectAll to receive all the events, or narrow it down using
+	// the operation, e.g. events.things.project.* for project events.
+	Subscribe(subject string, handler EventHandler) error
+
+	// Close closes the underlying connection.
+	Close()
+}
+
+var _ Publisher = (*publisher)(nil)
+
+type publisher struct {
+	conn *broker.Conn
+}
+
+// NewPublisher returns NATS things service event publisher.
+func NewPublisher(url string) (Publisher, error) {
+	conn, err := broker.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &publisher{conn: conn}, nil
+}
+
+func (pub *publisher) Publish(ev Event) error {
+	data, err := json.Marshal(ev.Encode())
+	if err != nil {
+		return err
+	}
+
+	return pub.conn.Publish(fmt.Sprintf("%s.%s", subjectPrefix, ev.Operation()), data)
+}
+
+func (pub *publisher) Close() {
+	pub.conn.Close()
+}
+
+var _ Subscriber = (*subscriber)(nil)
+
+type subscriber struct {
+	conn   *broker.Conn
+	queue  string
+	logger log.Logger
+}
+
+// NewSubscriber returns NATS things service event subscriber. If queue is
+// not empty, the events are load balanced between the subscribers sharing
+// the same queue.
+func NewSubscriber(url, queue string, logger log.Logger) (Subscriber, error) {
+	conn, err := broker.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriber{
+		conn:   conn,
+		queue:  queue,
+		logger: logger,
+	}, nil
+}
+
+func (sub *subscriber) Subscribe(subject string, handler EventHandler) error {
+	if subject == "" {
+		return errEmptySubject
+	}
+
+	if sub.queue != "" {
+		_, err := sub.conn.QueueSubscribe(subject, sub.queue, sub.natsHandler(handler))
+		return err
+	}
+
+	_, err := sub.conn.Subscribe(subject, sub.natsHandler(handler))
+	return err
+}
+
+func (sub *subscriber) Close() {
+	sub.conn.Close()
+}
+
+func (sub *subscriber) natsHandler(h EventHandler) broker.MsgHandler {
+	return func(m *broker.Msg) {
+		op := strings.TrimPrefix(m.Subject, subjectPrefix+".")
+		ev, err := Decode(op, m.Data)
+		if err != nil {
+			sub.logger.Warn(fmt.Sprintf("Failed to decode received event: %s", err))
+			return
+		}
+		if err := h(ev); err != nil {
+			sub.logger.Warn(fmt.Sprintf("Failed to handle event: %s", err))
+		}
+	}
+}
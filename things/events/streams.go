@@ -0,0 +1,399 @@
+package events
+
+import (
+	"context"
+
+	"github.com/vietquy/alpha/things"
+)
+
+var _ things.Service = (*eventStore)(nil)
+
+type eventStore struct {
+	svc things.Service
+	pub Publisher
+}
+
+// NewEventStoreMiddleware returns wrapper around things service that sends
+// events to the event stream after each successful state change.
+func NewEventStoreMiddleware(svc things.Service, pub Publisher) things.Service {
+	return &eventStore{
+		svc: svc,
+		pub: pub,
+	}
+}
+
+func (es eventStore) CreateThings(ctx context.Context, token string, ths ...things.Thing) ([]things.Thing, error) {
+	sths, err := es.svc.CreateThings(ctx, token, ths...)
+	if err != nil {
+		return sths, err
+	}
+
+	for _, th := range sths {
+		ev := CreateThingEvent{
+			ID:         th.ID,
+			Owner:      th.Owner,
+			Name:       th.Name,
+			Metadata:   th.Metadata,
+			OccurredAt: now(),
+		}
+		if err := es.pub.Publish(ev); err != nil {
+			return sths, err
+		}
+	}
+
+	return sths, nil
+}
+
+func (es eventStore) UpdateThing(ctx context.Context, token string, thing things.Thing) error {
+	if err := es.svc.UpdateThing(ctx, token, thing); err != nil {
+		return err
+	}
+
+	ev := UpdateThingEvent{
+		ID:         thing.ID,
+		Name:       thing.Name,
+		Metadata:   thing.Metadata,
+		OccurredAt: now(),
+	}
+
+	return es.pub.Publish(ev)
+}
+
+func (es eventStore) UpdateKey(ctx context.Context, token, id, key string) error {
+	if err := es.svc.UpdateKey(ctx, token, id, key); err != nil {
+		return err
+	}
+
+	return es.pub.Publish(UpdateKeyEvent{ID: id, OccurredAt: now()})
+}
+
+func (es eventStore) RotateKey(ctx context.Context, token, id string) (things.ThingKey, error) {
+	key, err := es.svc.RotateKey(ctx, token, id)
+	if err != nil {
+		return key, err
+	}
+
+	return key, es.pub.Publish(UpdateKeyEvent{ID: id, OccurredAt: now()})
+}
+
+func (es eventStore) ListKeys(ctx context.Context, token, id string) ([]things.ThingKey, error) {
+	return es.svc.ListKeys(ctx, token, id)
+}
+
+func (es eventStore) RevokeKey(ctx context.Context, token, id, key string) error {
+	if err := es.svc.RevokeKey(ctx, token, id, key); err != nil {
+		return err
+	}
+
+	return es.pub.Publish(RevokeKeyEvent{ID: id, OccurredAt: now()})
+}
+
+func (es eventStore) ViewThing(ctx context.Context, token, id string) (things.Thing, error) {
+	return es.svc.ViewThing(ctx, token, id)
+}
+
+func (es eventStore) ListThings(ctx context.Context, token string, pm things.PageMetadata) (things.Page, error) {
+	return es.svc.ListThings(ctx, token, pm)
+}
+
+func (es eventStore) ListThingsByProject(ctx context.Context, token, project string, offset, limit uint64) (things.Page, error) {
+	return es.svc.ListThingsByProject(ctx, token, project, offset, limit)
+}
+
+func (es eventStore) RemoveThing(ctx context.Context, token, id string) error {
+	if err := es.svc.RemoveThing(ctx, token, id); err != nil {
+		return err
+	}
+
+	return es.pub.Publish(RemoveThingEvent{ID: id, OccurredAt: now()})
+}
+
+func (es eventStore) RemoveThings(ctx context.Context, token string, ids ...string) ([]things.BulkResult, error) {
+	results, err := es.svc.RemoveThings(ctx, token, ids...)
+	if err != nil {
+		return results, err
+	}
+
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		if err := es.pub.Publish(RemoveThingEvent{ID: res.ID, OccurredAt: now()}); err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+func (es eventStore) CreateProjects(ctx context.Context, token string, projects ...things.Project) ([]things.Project, error) {
+	sprs, err := es.svc.CreateProjects(ctx, token, projects...)
+	if err != nil {
+		return sprs, err
+	}
+
+	for _, pr := range sprs {
+		ev := CreateProjectEvent{
+			ID:         pr.ID,
+			Owner:      pr.Owner,
+			Name:       pr.Name,
+			Metadata:   pr.Metadata,
+			OccurredAt: now(),
+		}
+		if err := es.pub.Publish(ev); err != nil {
+			return sprs, err
+		}
+	}
+
+	return sprs, nil
+}
+
+func (es eventStore) UpdateProject(ctx context.Context, token string, project things.Project) error {
+	if err := es.svc.UpdateProject(ctx, token, project); err != nil {
+		return err
+	}
+
+	ev := UpdateProjectEvent{
+		ID:         project.ID,
+		Name:       project.Name,
+		Metadata:   project.Metadata,
+		OccurredAt: now(),
+	}
+
+	return es.pub.Publish(ev)
+}
+
+func (es eventStore) ViewProject(ctx context.Context, token, id string) (things.Project, error) {
+	return es.svc.ViewProject(ctx, token, id)
+}
+
+func (es eventStore) ListProjects(ctx context.Context, token string, pm things.PageMetadata) (things.ProjectsPage, error) {
+	return es.svc.ListProjects(ctx, token, pm)
+}
+
+func (es eventStore) ListProjectsByThing(ctx context.Context, token, thing string, offset, limit uint64) (things.ProjectsPage, error) {
+	return es.svc.ListProjectsByThing(ctx, token, thing, offset, limit)
+}
+
+func (es eventStore) RemoveProject(ctx context.Context, token, id string) error {
+	if err := es.svc.RemoveProject(ctx, token, id); err != nil {
+		return err
+	}
+
+	return es.pub.Publish(RemoveProjectEvent{ID: id, OccurredAt: now()})
+}
+
+func (es eventStore) RemoveProjects(ctx context.Context, token string, ids ...string) ([]things.BulkResult, error) {
+	results, err := es.svc.RemoveProjects(ctx, token, ids...)
+	if err != nil {
+		return results, err
+	}
+
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		if err := es.pub.Publish(RemoveProjectEvent{ID: res.ID, OccurredAt: now()}); err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+func (es eventStore) Connect(ctx context.Context, token string, chIDs, thIDs []string, policy things.Policy) error {
+	if err := es.svc.Connect(ctx, token, chIDs, thIDs, policy); err != nil {
+		return err
+	}
+
+	for _, chID := range chIDs {
+		for _, thID := range thIDs {
+			ev := ConnectEvent{
+				ProjectID:  chID,
+				ThingID:    thID,
+				Actions:    policy.Actions,
+				Subtopic:   policy.Subtopic,
+				OccurredAt: now(),
+			}
+			if err := es.pub.Publish(ev); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (es eventStore) Disconnect(ctx context.Context, token, projectID, thingID string) error {
+	if err := es.svc.Disconnect(ctx, token, projectID, thingID); err != nil {
+		return err
+	}
+
+	ev := DisconnectEvent{
+		ProjectID:  projectID,
+		ThingID:    thingID,
+		OccurredAt: now(),
+	}
+
+	return es.pub.Publish(ev)
+}
+
+func (es eventStore) DisconnectMany(ctx context.Context, token string, chIDs, thIDs []string) ([]things.DisconnectResult, error) {
+	results, err := es.svc.DisconnectMany(ctx, token, chIDs, thIDs)
+	if err != nil {
+		return results, err
+	}
+
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		ev := DisconnectEvent{
+			ProjectID:  res.ProjectID,
+			ThingID:    res.ThingID,
+			OccurredAt: now(),
+		}
+		if err := es.pub.Publish(ev); err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+func (es eventStore) Share(ctx context.Context, token string, share things.Share) error {
+	if err := es.svc.Share(ctx, token, share); err != nil {
+		return err
+	}
+
+	ev := ShareEvent{
+		Resource:   share.Resource,
+		ID:         share.ID,
+		Member:     share.Member,
+		Role:       share.Role,
+		OccurredAt: now(),
+	}
+
+	return es.pub.Publish(ev)
+}
+
+func (es eventStore) Unshare(ctx context.Context, token, resource, id, member string) error {
+	if err := es.svc.Unshare(ctx, token, resource, id, member); err != nil {
+		return err
+	}
+
+	ev := UnshareEvent{
+		Resource:   resource,
+		ID:         id,
+		Member:     member,
+		OccurredAt: now(),
+	}
+
+	return es.pub.Publish(ev)
+}
+
+func (es eventStore) ListShares(ctx context.Context, token, resource, id string) ([]things.Share, error) {
+	return es.svc.ListShares(ctx, token, resource, id)
+}
+
+func (es eventStore) ListShared(ctx context.Context, token string) ([]things.Share, error) {
+	return es.svc.ListShared(ctx, token)
+}
+
+func (es eventStore) Export(ctx context.Context, token string, redact bool) (things.Backup, error) {
+	return es.svc.Export(ctx, token, redact)
+}
+
+func (es eventStore) Import(ctx context.Context, token string, backup things.Backup, dryRun bool) (things.ImportReport, error) {
+	report, err := es.svc.Import(ctx, token, backup, dryRun)
+	if err != nil || report.DryRun {
+		return report, err
+	}
+
+	thIDs := map[string]string{}
+	for _, res := range report.Things {
+		thIDs[res.ID] = res.NewID
+		if !res.Created {
+			continue
+		}
+
+		th, err := es.svc.ViewThing(ctx, token, res.NewID)
+		if err != nil {
+			return report, err
+		}
+
+		ev := CreateThingEvent{
+			ID:         th.ID,
+			Owner:      th.Owner,
+			Name:       th.Name,
+			Metadata:   th.Metadata,
+			OccurredAt: now(),
+		}
+		if err := es.pub.Publish(ev); err != nil {
+			return report, err
+		}
+	}
+
+	chIDs := map[string]string{}
+	for _, res := range report.Projects {
+		chIDs[res.ID] = res.NewID
+		if !res.Created {
+			continue
+		}
+
+		pr, err := es.svc.ViewProject(ctx, token, res.NewID)
+		if err != nil {
+			return report, err
+		}
+
+		ev := CreateProjectEvent{
+			ID:         pr.ID,
+			Owner:      pr.Owner,
+			Name:       pr.Name,
+			Metadata:   pr.Metadata,
+			OccurredAt: now(),
+		}
+		if err := es.pub.Publish(ev); err != nil {
+			return report, err
+		}
+	}
+
+	for _, res := range report.Connections {
+		if !res.Created {
+			continue
+		}
+
+		ev := ConnectEvent{
+			ProjectID:  chIDs[res.ProjectID],
+			ThingID:    thIDs[res.ThingID],
+			Actions:    res.Policy.Actions,
+			Subtopic:   res.Policy.Subtopic,
+			OccurredAt: now(),
+		}
+		if err := es.pub.Publish(ev); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func (es eventStore) CanAccessByKey(ctx context.Context, projectID, key string) (string, error) {
+	return es.svc.CanAccessByKey(ctx, projectID, key)
+}
+
+func (es eventStore) CanAccessByID(ctx context.Context, projectID, thingID string) error {
+	return es.svc.CanAccessByID(ctx, projectID, thingID)
+}
+
+func (es eventStore) CanPublish(ctx context.Context, projectID, thingID, subtopic string) error {
+	return es.svc.CanPublish(ctx, projectID, thingID, subtopic)
+}
+
+func (es eventStore) CanSubscribe(ctx context.Context, projectID, thingID, subtopic string) error {
+	return es.svc.CanSubscribe(ctx, projectID, thingID, subtopic)
+}
+
+func (es eventStore) Identify(ctx context.Context, key string) (string, error) {
+	return es.svc.Identify(ctx, key)
+}
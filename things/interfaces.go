@@ -2,6 +2,7 @@ package things
 
 import (
 	"context"
+	"time"
 )
 
 // IdentityProvider specifies an API for generating unique identifiers.
@@ -17,11 +18,32 @@ type Metadata map[string]interface{}
 // Thing represents a thing. Each thing is owned by one user, and
 // it is assigned with the unique identifier and (temporary) access key.
 type Thing struct {
-	ID       string
-	Owner    string
-	Name     string
-	Key      string
-	Metadata Metadata
+	ID        string
+	Owner     string
+	Name      string
+	Key       string
+	Metadata  Metadata
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ThingKey represents one of the thing access keys. A thing can hold
+// several keys at once, e.g. while the previous key is still within the
+// grace period after the rotation.
+type ThingKey struct {
+	Value     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Revoked   bool
+}
+
+// Valid returns true if the key is neither revoked, nor expired.
+func (k ThingKey) Valid() bool {
+	if k.Revoked {
+		return false
+	}
+
+	return k.ExpiresAt.IsZero() || k.ExpiresAt.After(time.Now())
 }
 
 // Page contains page related metadata as well as list of things that
@@ -34,10 +56,12 @@ type Page struct {
 // Project represents a "communication group". This group contains the
 // things that can exchange messages between eachother.
 type Project struct {
-	ID       string
-	Owner    string
-	Name     string
-	Metadata map[string]interface{}
+	ID        string
+	Owner     string
+	Name      string
+	Metadata  map[string]interface{}
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // ProjectsPage contains page related metadata as well as list of projects that
@@ -58,19 +82,33 @@ type ThingRepository interface {
 	// returned to indicate operation failure.
 	Update(ctx context.Context, t Thing) error
 
-	// UpdateKey updates key value of the existing thing. A non-nil error is
-	// returned to indicate operation failure.
+	// UpdateKey updates key value of the existing thing. All the other keys
+	// of the thing are revoked. A non-nil error is returned to indicate
+	// operation failure.
 	UpdateKey(ctx context.Context, owner, id, key string) error
 
+	// RotateKey sets the new key of the existing thing, while the other
+	// valid keys of the thing stay valid until the provided expiration time.
+	RotateKey(ctx context.Context, owner, id string, key ThingKey, expiresAt time.Time) error
+
+	// RetrieveKeys retrieves all the keys of the thing, including the
+	// expired and revoked ones.
+	RetrieveKeys(ctx context.Context, owner, id string) ([]ThingKey, error)
+
+	// RevokeKey revokes the provided key of the thing.
+	RevokeKey(ctx context.Context, owner, id, key string) error
+
 	// RetrieveByID retrieves the thing having the provided identifier, that is owned
 	// by the specified user.
 	RetrieveByID(ctx context.Context, owner, id string) (Thing, error)
 
-	// RetrieveByKey returns thing ID for given thing key.
+	// RetrieveByKey returns thing ID for given thing key. Expired and
+	// revoked keys are not taken into account.
 	RetrieveByKey(ctx context.Context, key string) (string, error)
 
-	// RetrieveAll retrieves the subset of things owned by the specified user.
-	RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, m Metadata) (Page, error)
+	// RetrieveAll retrieves the subset of things owned by the specified
+	// owner or shared with the specified member, matching the page filters.
+	RetrieveAll(ctx context.Context, owner, member string, pm PageMetadata) (Page, error)
 
 	// RetrieveByProject retrieves the subset of things owned by the specified
 	// user and connected to specified project.
@@ -79,6 +117,11 @@ type ThingRepository interface {
 	// Remove removes the thing having the provided identifier, that is owned
 	// by the specified user.
 	Remove(ctx context.Context, owner, id string) error
+
+	// RemoveMany removes the things identified by their IDs and owners using
+	// a transaction. It returns the IDs of the removed things, while the
+	// non-existent things are skipped.
+	RemoveMany(ctx context.Context, ths ...Thing) ([]string, error)
 }
 
 // ProjectRepository specifies a project persistence API.
@@ -96,8 +139,9 @@ type ProjectRepository interface {
 	// by the specified user.
 	RetrieveByID(context.Context, string, string) (Project, error)
 
-	// RetrieveAll retrieves the subset of projects owned by the specified user.
-	RetrieveAll(context.Context, string, uint64, uint64, string, Metadata) (ProjectsPage, error)
+	// RetrieveAll retrieves the subset of projects owned by the specified
+	// owner or shared with the specified member, matching the page filters.
+	RetrieveAll(context.Context, string, string, PageMetadata) (ProjectsPage, error)
 
 	// RetrieveByThing retrieves the subset of projects owned by the specified
 	// user and have specified thing connected to them.
@@ -107,20 +151,67 @@ type ProjectRepository interface {
 	// by the specified user.
 	Remove(context.Context, string, string) error
 
-	// Connect adds things to the project's list of connected things.
-	Connect(context.Context, string, []string, []string) error
+	// RemoveMany removes the projects identified by their IDs and owners
+	// using a transaction. It returns the IDs of the removed projects, while
+	// the non-existent projects are skipped.
+	RemoveMany(context.Context, ...Project) ([]string, error)
+
+	// Connect adds things to the project's list of connected things. Each
+	// of the connections is restricted by the provided policy.
+	Connect(context.Context, string, []string, []string, Policy) error
 
 	// Disconnect removes thing from the project's list of connected
 	// things.
 	Disconnect(context.Context, string, string, string) error
 
+	// DisconnectMany removes the connections using a transaction. It returns
+	// the removed connections, while the non-existent connections are
+	// skipped.
+	DisconnectMany(context.Context, ...Connection) ([]Connection, error)
+
 	// HasThing determines whether the thing with the provided access key, is
 	// "connected" to the specified project. If that's the case, it returns
-	// thing's ID.
+	// thing's ID. Expired and revoked keys are not taken into account.
 	HasThing(context.Context, string, string) (string, error)
 
-	// HasThingByID determines whether the thing with the provided ID, is
-	// "connected" to the specified project. If that's the case, then
-	// returned error will be nil.
-	HasThingByID(context.Context, string, string) error
+	// RetrieveConnections retrieves all the connections between the things
+	// and the projects owned by the specified user, along with their
+	// policies.
+	RetrieveConnections(context.Context, string) ([]Connection, error)
+
+	// RetrievePolicy retrieves the policy of the connection between the
+	// specified project and the thing with the provided ID. If the thing is
+	// not connected to the project, ErrUnauthorizedAccess is returned.
+	RetrievePolicy(context.Context, string, string) (Policy, error)
+}
+
+// ThingCache contains thing caching interface.
+type ThingCache interface {
+	// Save stores pair thing key, thing id.
+	Save(ctx context.Context, key, id string) error
+
+	// ID returns thing ID for given key.
+	ID(ctx context.Context, key string) (string, error)
+
+	// Remove removes all keys of the thing with the provided ID from cache.
+	Remove(ctx context.Context, id string) error
+}
+
+// ProjectCache contains project-thing connection caching interface.
+type ProjectCache interface {
+	// Connect stores the project-thing connection along with its policy.
+	Connect(ctx context.Context, projectID, thingID string, policy Policy) error
+
+	// Policy returns the policy of the project-thing connection, or
+	// ErrNotFound if the connection is not cached.
+	Policy(ctx context.Context, projectID, thingID string) (Policy, error)
+
+	// Disconnect removes the project-thing connection from cache.
+	Disconnect(ctx context.Context, projectID, thingID string) error
+
+	// Remove removes the project and all of its connections from cache.
+	Remove(ctx context.Context, projectID string) error
+
+	// RemoveThing removes all of the connections of the thing from cache.
+	RemoveThing(ctx context.Context, thingID string) error
 }
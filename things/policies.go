@@ -0,0 +1,122 @@
+package things
+
+import "strings"
+
+const (
+	// ActionPublish allows the connected thing to publish messages to the
+	// project.
+	ActionPublish = "publish"
+
+	// ActionSubscribe allows the connected thing to subscribe to the project
+	// messages.
+	ActionSubscribe = "subscribe"
+)
+
+const (
+	subtopicSep       = "."
+	subtopicWildcard  = "*"
+	subtopicRemaining = ">"
+)
+
+// Policy describes what the thing connected to the project is allowed to do.
+// Subtopic is an optional pattern, in the form of dot-separated subtopic
+// tokens, restricting the project subtopics the thing can access. The "*"
+// token matches any single subtopic token, while the trailing ">" token
+// matches one or more remaining tokens. Empty pattern matches all the
+// project subtopics, including messages published without a subtopic.
+type Policy struct {
+	Actions  []string
+	Subtopic string
+}
+
+// DefaultPolicy returns the policy allowing both publishing and subscribing
+// to all the project subtopics.
+func DefaultPolicy() Policy {
+	return Policy{Actions: []string{ActionPublish, ActionSubscribe}}
+}
+
+// Validate returns ErrMalformedEntity if the policy contains an unknown
+// action or a malformed subtopic pattern.
+func (p Policy) Validate() error {
+	if len(p.Actions) == 0 {
+		return ErrMalformedEntity
+	}
+
+	for _, action := range p.Actions {
+		if action != ActionPublish && action != ActionSubscribe {
+			return ErrMalformedEntity
+		}
+	}
+
+	if p.Subtopic == "" {
+		return nil
+	}
+
+	tokens := strings.Split(p.Subtopic, subtopicSep)
+	for i, token := range tokens {
+		switch {
+		case token == "":
+			return ErrMalformedEntity
+		case token == subtopicRemaining && i != len(tokens)-1:
+			return ErrMalformedEntity
+		case len(token) > 1 && strings.ContainsAny(token, subtopicWildcard+subtopicRemaining):
+			return ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+// Allows returns true if the policy permits the action on the given subtopic.
+// The subtopic may contain wildcards itself (e.g. when subscribing), in which
+// case it is allowed only if it doesn't match more than the policy pattern.
+func (p Policy) Allows(action, subtopic string) bool {
+	if !p.hasAction(action) {
+		return false
+	}
+
+	if p.Subtopic == "" {
+		return true
+	}
+
+	if subtopic == "" {
+		return false
+	}
+
+	pattern := strings.Split(p.Subtopic, subtopicSep)
+	tokens := strings.Split(subtopic, subtopicSep)
+	for i, pt := range pattern {
+		if pt == subtopicRemaining {
+			return len(tokens) > i
+		}
+
+		if i >= len(tokens) {
+			return false
+		}
+
+		switch tokens[i] {
+		case subtopicRemaining:
+			return false
+		case subtopicWildcard:
+			if pt != subtopicWildcard {
+				return false
+			}
+		default:
+			if pt != subtopicWildcard && pt != tokens[i] {
+				return false
+			}
+		}
+	}
+
+	return len(tokens) == len(pattern)
+}
+
+func (p Policy) hasAction(action string) bool {
+	for _, a := range p.Actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
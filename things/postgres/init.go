@@ -88,6 +88,99 @@ func migrateDB(db *sqlx.DB) error {
 					`,
 				},
 			},
+			{
+				Id: "things_4",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS things_keys (
+						key         VARCHAR(4096) PRIMARY KEY,
+						thing_id    UUID NOT NULL,
+						thing_owner VARCHAR(254) NOT NULL,
+						created_at  TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+						expires_at  TIMESTAMP,
+						revoked     BOOLEAN NOT NULL DEFAULT FALSE,
+						FOREIGN KEY (thing_id, thing_owner) REFERENCES things (id, owner) ON DELETE CASCADE ON UPDATE CASCADE
+					)`,
+					`CREATE INDEX IF NOT EXISTS things_keys_thing_idx ON things_keys (thing_id, thing_owner)`,
+					`INSERT INTO things_keys (key, thing_id, thing_owner)
+					 SELECT key, id, owner FROM things
+					 ON CONFLICT (key) DO NOTHING`,
+				},
+				Down: []string{
+					"DROP TABLE things_keys",
+				},
+			},
+			{
+				Id: "things_5",
+				Up: []string{
+					`ALTER TABLE IF EXISTS connections
+					 ADD COLUMN IF NOT EXISTS actions  VARCHAR(32)[] NOT NULL DEFAULT '{publish,subscribe}',
+					 ADD COLUMN IF NOT EXISTS subtopic VARCHAR(1024) NOT NULL DEFAULT ''`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS connections
+					 DROP COLUMN IF EXISTS actions,
+					 DROP COLUMN IF EXISTS subtopic`,
+				},
+			},
+			{
+				Id: "things_6",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS shares (
+						resource    VARCHAR(32),
+						resource_id UUID,
+						owner       VARCHAR(254) NOT NULL,
+						member      VARCHAR(254),
+						role        VARCHAR(32) NOT NULL,
+						PRIMARY KEY (resource, resource_id, member)
+					)`,
+					`CREATE INDEX IF NOT EXISTS shares_member_idx ON shares (member)`,
+				},
+				Down: []string{
+					"DROP TABLE shares",
+				},
+			},
+			{
+				Id: "things_7",
+				// Resources owned by the user email are moved to the personal
+				// organization of the user, see authn.PersonalOrganization.
+				// Connections and keys follow the owner by cascading updates.
+				Up: []string{
+					`UPDATE things SET owner = md5(owner)::uuid::text WHERE owner LIKE '%@%'`,
+					`UPDATE projects SET owner = md5(owner)::uuid::text WHERE owner LIKE '%@%'`,
+					`UPDATE shares SET owner = md5(owner)::uuid::text WHERE owner LIKE '%@%'`,
+				},
+			},
+			{
+				Id: "things_8",
+				Up: []string{
+					`ALTER TABLE IF EXISTS things
+					 ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+					 ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC')`,
+					`ALTER TABLE IF EXISTS projects
+					 ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+					 ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC')`,
+					`CREATE INDEX IF NOT EXISTS things_created_at_idx ON things (owner, created_at)`,
+					`CREATE INDEX IF NOT EXISTS projects_created_at_idx ON projects (owner, created_at)`,
+					`CREATE INDEX IF NOT EXISTS connections_thing_idx ON connections (thing_id, thing_owner)`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS things DROP COLUMN IF EXISTS created_at, DROP COLUMN IF EXISTS updated_at`,
+					`ALTER TABLE IF EXISTS projects DROP COLUMN IF EXISTS created_at, DROP COLUMN IF EXISTS updated_at`,
+				},
+			},
+			{
+				Id: "things_9",
+				// Keys are kept in things_keys only.
+				Up: []string{
+					`ALTER TABLE IF EXISTS things DROP COLUMN IF EXISTS key`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS things ADD COLUMN IF NOT EXISTS key VARCHAR(4096)`,
+					`UPDATE things SET key = (SELECT tk.key FROM things_keys tk
+					 WHERE tk.thing_id = things.id AND tk.thing_owner = things.owner
+					 ORDER BY tk.revoked, tk.created_at DESC LIMIT 1)`,
+				},
+			},
 		},
 	}
 
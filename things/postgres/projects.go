@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/gofrs/uuid"
@@ -41,9 +42,11 @@ type projectRepository struct {
 }
 
 type dbConnection struct {
-	Project string `db:"project"`
-	Thing   string `db:"thing"`
-	Owner   string `db:"owner"`
+	Project  string         `db:"project"`
+	Thing    string         `db:"thing"`
+	Owner    string         `db:"owner"`
+	Actions  pq.StringArray `db:"actions"`
+	Subtopic string         `db:"subtopic"`
 }
 
 // NewProjectRepository instantiates a PostgreSQL implementation of project
@@ -60,8 +63,8 @@ func (cr projectRepository) Save(ctx context.Context, projects ...things.Project
 		return nil, errors.Wrap(ErrSaveProject, err)
 	}
 
-	q := `INSERT INTO projects (id, owner, name, metadata)
-		  VALUES (:id, :owner, :name, :metadata);`
+	q := `INSERT INTO projects (id, owner, name, metadata, created_at, updated_at)
+		  VALUES (:id, :owner, :name, :metadata, :created_at, :updated_at);`
 
 	for _, project := range projects {
 		dbch := toDBProject(project)
@@ -90,7 +93,8 @@ func (cr projectRepository) Save(ctx context.Context, projects ...things.Project
 }
 
 func (cr projectRepository) Update(ctx context.Context, project things.Project) error {
-	q := `UPDATE projects SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+	q := `UPDATE projects SET name = :name, metadata = :metadata, updated_at = :updated_at
+	      WHERE owner = :owner AND id = :id;`
 
 	dbch := toDBProject(project)
 
@@ -120,7 +124,7 @@ func (cr projectRepository) Update(ctx context.Context, project things.Project)
 }
 
 func (cr projectRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Project, error) {
-	q := `SELECT name, metadata FROM projects WHERE id = $1 AND owner = $2;`
+	q := `SELECT name, metadata, created_at, updated_at FROM projects WHERE id = $1 AND owner = $2;`
 
 	dbch := dbProject{
 		ID:    id,
@@ -138,23 +142,21 @@ func (cr projectRepository) RetrieveByID(ctx context.Context, owner, id string)
 	return toProject(dbch), nil
 }
 
-func (cr projectRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata things.Metadata) (things.ProjectsPage, error) {
-	nq, name := getNameQuery(name)
-	m, mq, err := getMetadataQuery(metadata)
+func (cr projectRepository) RetrieveAll(ctx context.Context, owner, member string, pm things.PageMetadata) (things.ProjectsPage, error) {
+	params := map[string]interface{}{
+		"owner":  owner,
+		"member": member,
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+	}
+
+	fq, err := pageQuery(pm, params)
 	if err != nil {
 		return things.ProjectsPage{}, errors.Wrap(ErrSelectProject, err)
 	}
 
-	q := fmt.Sprintf(`SELECT id, name, metadata FROM projects
-	      WHERE owner = :owner %s%s ORDER BY id LIMIT :limit OFFSET :offset;`, mq, nq)
-
-	params := map[string]interface{}{
-		"owner":    owner,
-		"limit":    limit,
-		"offset":   offset,
-		"name":     name,
-		"metadata": m,
-	}
+	q := fmt.Sprintf(`SELECT id, owner, name, metadata, created_at, updated_at FROM projects
+	      WHERE %s%s %s LIMIT :limit OFFSET :offset;`, ownedOrShared(things.ProjectResource), fq, orderQuery(pm, ""))
 	rows, err := cr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
 		return things.ProjectsPage{}, errors.Wrap(ErrSelectProject, err)
@@ -172,7 +174,7 @@ func (cr projectRepository) RetrieveAll(ctx context.Context, owner string, offse
 		items = append(items, ch)
 	}
 
-	cq := fmt.Sprintf(`SELECT COUNT(*) FROM projects WHERE owner = :owner %s%s;`, nq, mq)
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM projects WHERE %s%s;`, ownedOrShared(things.ProjectResource), fq)
 
 	total, err := total(ctx, cr.db, cq, params)
 	if err != nil {
@@ -183,8 +185,8 @@ func (cr projectRepository) RetrieveAll(ctx context.Context, owner string, offse
 		Projects: items,
 		PageMetadata: things.PageMetadata{
 			Total:  total,
-			Offset: offset,
-			Limit:  limit,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
 		},
 	}
 
@@ -197,7 +199,7 @@ func (cr projectRepository) RetrieveByThing(ctx context.Context, owner, thing st
 		return things.ProjectsPage{}, things.ErrNotFound
 	}
 
-	q := `SELECT id, name, metadata
+	q := `SELECT id, name, metadata, created_at, updated_at
 	      FROM projects ch
 	      INNER JOIN connections co
 		  ON ch.id = co.project_id
@@ -261,21 +263,55 @@ func (cr projectRepository) Remove(ctx context.Context, owner, id string) error
 	return nil
 }
 
-func (cr projectRepository) Connect(ctx context.Context, owner string, chIDs, thIDs []string) error {
+func (cr projectRepository) RemoveMany(ctx context.Context, chs ...things.Project) ([]string, error) {
+	tx, err := cr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(ErrDeleteProject, err)
+	}
+
+	q := `DELETE FROM projects WHERE id = $1 AND owner = $2 RETURNING id;`
+
+	removed := []string{}
+	for _, ch := range chs {
+		if _, err := uuid.FromString(ch.ID); err != nil {
+			continue
+		}
+
+		var id string
+		if err := tx.QueryRowxContext(ctx, q, ch.ID, ch.Owner).Scan(&id); err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			tx.Rollback()
+			return nil, errors.Wrap(ErrDeleteProject, err)
+		}
+		removed = append(removed, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(ErrDeleteProject, err)
+	}
+
+	return removed, nil
+}
+
+func (cr projectRepository) Connect(ctx context.Context, owner string, chIDs, thIDs []string, policy things.Policy) error {
 	tx, err := cr.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return errors.Wrap(ErrDeleteProject, err)
 	}
 
-	q := `INSERT INTO connections (project_id, project_owner, thing_id, thing_owner)
-	      VALUES (:project, :owner, :thing, :owner);`
+	q := `INSERT INTO connections (project_id, project_owner, thing_id, thing_owner, actions, subtopic)
+	      VALUES (:project, :owner, :thing, :owner, :actions, :subtopic);`
 
 	for _, chID := range chIDs {
 		for _, thID := range thIDs {
 			dbco := dbConnection{
-				Project: chID,
-				Thing:   thID,
-				Owner:   owner,
+				Project:  chID,
+				Thing:    thID,
+				Owner:    owner,
+				Actions:  policy.Actions,
+				Subtopic: policy.Subtopic,
 			}
 
 			_, err := tx.NamedExecContext(ctx, q, dbco)
@@ -331,9 +367,58 @@ func (cr projectRepository) Disconnect(ctx context.Context, owner, projectID, th
 	return nil
 }
 
+func (cr projectRepository) DisconnectMany(ctx context.Context, conns ...things.Connection) ([]things.Connection, error) {
+	tx, err := cr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(ErrDeleteConnection, err)
+	}
+
+	q := `DELETE FROM connections
+	      WHERE project_id = :project AND project_owner = :owner
+	      AND thing_id = :thing AND thing_owner = :owner`
+
+	removed := []things.Connection{}
+	for _, conn := range conns {
+		if _, err := uuid.FromString(conn.ProjectID); err != nil {
+			continue
+		}
+		if _, err := uuid.FromString(conn.ThingID); err != nil {
+			continue
+		}
+
+		dbco := dbConnection{
+			Project: conn.ProjectID,
+			Thing:   conn.ThingID,
+			Owner:   conn.Owner,
+		}
+
+		res, err := tx.NamedExecContext(ctx, q, dbco)
+		if err != nil {
+			tx.Rollback()
+			return nil, errors.Wrap(ErrDeleteConnection, err)
+		}
+
+		cnt, err := res.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return nil, errors.Wrap(ErrDeleteConnection, err)
+		}
+
+		if cnt > 0 {
+			removed = append(removed, conn)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(ErrDeleteConnection, err)
+	}
+
+	return removed, nil
+}
+
 func (cr projectRepository) HasThing(ctx context.Context, projectID, key string) (string, error) {
 	var thingID string
-	q := `SELECT id FROM things WHERE key = $1`
+	q := `SELECT thing_id FROM things_keys WHERE key = $1 AND ` + validKey
 	if err := cr.db.QueryRowxContext(ctx, q, key).Scan(&thingID); err != nil {
 		return "", errors.Wrap(ErrHasThing, err)
 
@@ -346,8 +431,54 @@ func (cr projectRepository) HasThing(ctx context.Context, projectID, key string)
 	return thingID, nil
 }
 
-func (cr projectRepository) HasThingByID(ctx context.Context, projectID, thingID string) error {
-	return cr.hasThing(ctx, projectID, thingID)
+func (cr projectRepository) RetrieveConnections(ctx context.Context, owner string) ([]things.Connection, error) {
+	q := `SELECT project_id AS project, thing_id AS thing, project_owner AS owner, actions, subtopic
+	      FROM connections WHERE project_owner = $1 AND thing_owner = $1
+	      ORDER BY project_id, thing_id;`
+
+	rows, err := cr.db.QueryxContext(ctx, q, owner)
+	if err != nil {
+		return nil, errors.Wrap(ErrSelectProject, err)
+	}
+	defer rows.Close()
+
+	conns := []things.Connection{}
+	for rows.Next() {
+		dbco := dbConnection{}
+		if err := rows.StructScan(&dbco); err != nil {
+			return nil, errors.Wrap(ErrSelectProject, err)
+		}
+
+		conn := things.Connection{
+			ProjectID: dbco.Project,
+			ThingID:   dbco.Thing,
+			Owner:     dbco.Owner,
+			Policy: things.Policy{
+				Actions:  dbco.Actions,
+				Subtopic: dbco.Subtopic,
+			},
+		}
+		conns = append(conns, conn)
+	}
+
+	return conns, nil
+}
+
+func (cr projectRepository) RetrievePolicy(ctx context.Context, projectID, thingID string) (things.Policy, error) {
+	q := `SELECT actions, subtopic FROM connections WHERE project_id = $1 AND thing_id = $2;`
+
+	dbco := dbConnection{}
+	if err := cr.db.QueryRowxContext(ctx, q, projectID, thingID).StructScan(&dbco); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Policy{}, things.ErrUnauthorizedAccess
+		}
+		return things.Policy{}, errors.Wrap(ErrHasThing, err)
+	}
+
+	return things.Policy{
+		Actions:  dbco.Actions,
+		Subtopic: dbco.Subtopic,
+	}, nil
 }
 
 func (cr projectRepository) hasThing(ctx context.Context, projectID, thingID string) error {
@@ -402,27 +533,33 @@ func (m dbMetadata) Value() (driver.Value, error) {
 }
 
 type dbProject struct {
-	ID       string     `db:"id"`
-	Owner    string     `db:"owner"`
-	Name     string     `db:"name"`
-	Metadata dbMetadata `db:"metadata"`
+	ID        string     `db:"id"`
+	Owner     string     `db:"owner"`
+	Name      string     `db:"name"`
+	Metadata  dbMetadata `db:"metadata"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
 }
 
 func toDBProject(ch things.Project) dbProject {
 	return dbProject{
-		ID:       ch.ID,
-		Owner:    ch.Owner,
-		Name:     ch.Name,
-		Metadata: ch.Metadata,
+		ID:        ch.ID,
+		Owner:     ch.Owner,
+		Name:      ch.Name,
+		Metadata:  ch.Metadata,
+		CreatedAt: ch.CreatedAt,
+		UpdatedAt: ch.UpdatedAt,
 	}
 }
 
 func toProject(ch dbProject) things.Project {
 	return things.Project{
-		ID:       ch.ID,
-		Owner:    ch.Owner,
-		Name:     ch.Name,
-		Metadata: ch.Metadata,
+		ID:        ch.ID,
+		Owner:     ch.Owner,
+		Name:      ch.Name,
+		Metadata:  ch.Metadata,
+		CreatedAt: ch.CreatedAt,
+		UpdatedAt: ch.UpdatedAt,
 	}
 }
 
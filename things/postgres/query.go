@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/things"
+)
+
+// pageQuery returns the query conditions matching the page filters, adding
+// the filter values to the named parameters.
+func pageQuery(pm things.PageMetadata, params map[string]interface{}) (string, error) {
+	nq, name := getNameQuery(pm.Name)
+	m, mq, err := getMetadataQuery(pm.Metadata)
+	if err != nil {
+		return "", err
+	}
+	params["name"] = name
+	params["metadata"] = m
+
+	if pm.Query == nil {
+		return nq + mq, nil
+	}
+
+	n := 0
+	qq, err := metadataQuery(*pm.Query, params, &n)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%s AND %s", nq, mq, qq), nil
+}
+
+// metadataQuery returns the query condition matching the metadata query.
+// The parameter n counts the query parameters in order to name them
+// uniquely.
+func metadataQuery(q things.Query, params map[string]interface{}, n *int) (string, error) {
+	switch q.Op {
+	case things.QueryAnd, things.QueryOr:
+		conds := []string{}
+		for _, t := range q.Terms {
+			c, err := metadataQuery(t, params, n)
+			if err != nil {
+				return "", err
+			}
+			conds = append(conds, c)
+		}
+		return fmt.Sprintf("(%s)", strings.Join(conds, " "+strings.ToUpper(q.Op)+" ")), nil
+	}
+
+	path := param(params, n, pq.StringArray(q.Path))
+	switch q.Op {
+	case things.QueryExists:
+		return fmt.Sprintf("(metadata #> %s IS NOT NULL)", path), nil
+	case things.QueryEq, things.QueryNe, things.QueryLt, things.QueryLe, things.QueryGt, things.QueryGe:
+	default:
+		return "", things.ErrMalformedEntity
+	}
+
+	op := q.Op
+	if op == things.QueryNe {
+		op = "<>"
+	}
+	value := param(params, n, q.Value)
+
+	if !q.Numeric() {
+		return fmt.Sprintf("(metadata #>> %s %s %s)", path, op, value), nil
+	}
+
+	// Non-numeric values don't match, rather than failing the cast. CAST is
+	// used since "::" is an escape sequence in the named queries.
+	return fmt.Sprintf(`(CASE WHEN jsonb_typeof(metadata #> %s) = 'number'
+		THEN CAST(metadata #>> %s AS NUMERIC) %s %s ELSE FALSE END)`, path, path, op, value), nil
+}
+
+func param(params map[string]interface{}, n *int, value interface{}) string {
+	name := fmt.Sprintf("q%d", *n)
+	*n++
+	params[name] = value
+	return ":" + name
+}
+
+// orderQuery returns the ORDER BY clause of the page. Resources are ordered
+// by ID by default, which is also used to break the ties.
+func orderQuery(pm things.PageMetadata, prefix string) string {
+	dir := "ASC"
+	if pm.Dir == things.DirDesc {
+		dir = "DESC"
+	}
+
+	col := ""
+	switch pm.Order {
+	case things.OrderName:
+		col = "name"
+	case things.OrderCreated:
+		col = "created_at"
+	default:
+		return fmt.Sprintf("ORDER BY %sid %s", prefix, dir)
+	}
+
+	return fmt.Sprintf("ORDER BY %s%s %s, %sid %s", prefix, col, dir, prefix, dir)
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/things"
+)
+
+var (
+	// ErrSaveShare indicates error while saving share to database
+	ErrSaveShare = errors.New("save share to db error")
+	// ErrSelectShare indicates error while reading share from database
+	ErrSelectShare = errors.New("select share from db error")
+	// ErrDeleteShare indicates error while deleting share from database
+	ErrDeleteShare = errors.New("delete share from db error")
+)
+
+var _ things.ShareRepository = (*shareRepository)(nil)
+
+type shareRepository struct {
+	db *sqlx.DB
+}
+
+type dbShare struct {
+	Resource string `db:"resource"`
+	ID       string `db:"resource_id"`
+	Owner    string `db:"owner"`
+	Member   string `db:"member"`
+	Role     string `db:"role"`
+}
+
+// NewShareRepository instantiates a PostgreSQL implementation of share
+// repository.
+func NewShareRepository(db *sqlx.DB) things.ShareRepository {
+	return &shareRepository{
+		db: db,
+	}
+}
+
+func (sr shareRepository) Save(ctx context.Context, share things.Share) error {
+	q := `INSERT INTO shares (resource, resource_id, owner, member, role)
+	      VALUES (:resource, :resource_id, :owner, :member, :role)
+	      ON CONFLICT (resource, resource_id, member) DO UPDATE SET role = :role;`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, toDBShare(share)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return things.ErrMalformedEntity
+		}
+		return errors.Wrap(ErrSaveShare, err)
+	}
+
+	return nil
+}
+
+func (sr shareRepository) Retrieve(ctx context.Context, resource, id, member string) (things.Share, error) {
+	q := `SELECT resource, resource_id, owner, member, role FROM shares
+	      WHERE resource = $1 AND resource_id = $2 AND member = $3;`
+
+	dbs := dbShare{}
+	if err := sr.db.QueryRowxContext(ctx, q, resource, id, member).StructScan(&dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Share{}, errors.Wrap(things.ErrNotFound, err)
+		}
+		return things.Share{}, errors.Wrap(ErrSelectShare, err)
+	}
+
+	return toShare(dbs), nil
+}
+
+func (sr shareRepository) RetrieveByResource(ctx context.Context, owner, resource, id string) ([]things.Share, error) {
+	q := `SELECT resource, resource_id, owner, member, role FROM shares
+	      WHERE owner = $1 AND resource = $2 AND resource_id = $3 ORDER BY member;`
+
+	return sr.retrieve(ctx, q, owner, resource, id)
+}
+
+func (sr shareRepository) RetrieveByMember(ctx context.Context, member string) ([]things.Share, error) {
+	q := `SELECT resource, resource_id, owner, member, role FROM shares
+	      WHERE member = $1 ORDER BY resource, resource_id;`
+
+	return sr.retrieve(ctx, q, member)
+}
+
+func (sr shareRepository) Remove(ctx context.Context, owner, resource, id, member string) error {
+	q := `DELETE FROM shares
+	      WHERE owner = $1 AND resource = $2 AND resource_id = $3 AND member = $4;`
+
+	res, err := sr.db.ExecContext(ctx, q, owner, resource, id, member)
+	if err != nil {
+		return errors.Wrap(ErrDeleteShare, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(ErrDeleteShare, err)
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (sr shareRepository) RemoveAll(ctx context.Context, resource, id string) error {
+	q := `DELETE FROM shares WHERE resource = $1 AND resource_id = $2;`
+
+	if _, err := sr.db.ExecContext(ctx, q, resource, id); err != nil {
+		return errors.Wrap(ErrDeleteShare, err)
+	}
+
+	return nil
+}
+
+func (sr shareRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]things.Share, error) {
+	rows, err := sr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, errors.Wrap(ErrSelectShare, err)
+	}
+	defer rows.Close()
+
+	shares := []things.Share{}
+	for rows.Next() {
+		dbs := dbShare{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, errors.Wrap(ErrSelectShare, err)
+		}
+		shares = append(shares, toShare(dbs))
+	}
+
+	return shares, nil
+}
+
+// ownedOrShared returns the query condition matching the resources owned by
+// the :owner named parameter or shared with the :member named parameter.
+func ownedOrShared(resource string) string {
+	return fmt.Sprintf(`(owner = :owner OR id IN
+		(SELECT resource_id FROM shares WHERE resource = '%s' AND member = :member))`, resource)
+}
+
+func toDBShare(share things.Share) dbShare {
+	return dbShare{
+		Resource: share.Resource,
+		ID:       share.ID,
+		Owner:    share.Owner,
+		Member:   share.Member,
+		Role:     share.Role,
+	}
+}
+
+func toShare(dbs dbShare) things.Share {
+	return things.Share{
+		Resource: dbs.Resource,
+		ID:       dbs.ID,
+		Owner:    dbs.Owner,
+		Member:   dbs.Member,
+		Role:     dbs.Role,
+	}
+}
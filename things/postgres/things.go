@@ -5,7 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq" // required for DB access
@@ -55,28 +56,25 @@ func (tr thingRepository) Save(ctx context.Context, ths ...things.Thing) ([]thin
 		return nil, errors.Wrap(ErrSaveDb, err)
 	}
 
-	q := `INSERT INTO things (id, owner, name, key, metadata)
-		  VALUES (:id, :owner, :name, :key, :metadata);`
+	q := `INSERT INTO things (id, owner, name, metadata, created_at, updated_at)
+		  VALUES (:id, :owner, :name, :metadata, :created_at, :updated_at);`
 
 	for _, thing := range ths {
 		dbth, err := toDBThing(thing)
 		if err != nil {
+			tx.Rollback()
 			return []things.Thing{}, err
 		}
 
 		if _, err := tx.NamedExecContext(ctx, q, dbth); err != nil {
 			tx.Rollback()
-			pqErr, ok := err.(*pq.Error)
-			if ok {
-				switch pqErr.Code.Name() {
-				case errInvalid, errTruncation:
-					return []things.Thing{}, errors.Wrap(things.ErrMalformedEntity, err)
-				case errDuplicate:
-					return []things.Thing{}, errors.Wrap(things.ErrConflict, err)
-				}
-			}
+			return []things.Thing{}, saveError(err)
+		}
 
-			return []things.Thing{}, errors.Wrap(ErrSaveDb, err)
+		dbk := toDBKey(thing.ID, thing.Owner, things.ThingKey{Value: thing.Key})
+		if err := insertKey(ctx, tx, dbk); err != nil {
+			tx.Rollback()
+			return []things.Thing{}, saveError(err)
 		}
 	}
 
@@ -88,7 +86,8 @@ func (tr thingRepository) Save(ctx context.Context, ths ...things.Thing) ([]thin
 }
 
 func (tr thingRepository) Update(ctx context.Context, t things.Thing) error {
-	q := `UPDATE things SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+	q := `UPDATE things SET name = :name, metadata = :metadata, updated_at = :updated_at
+	      WHERE owner = :owner AND id = :id;`
 
 	dbth, err := toDBThing(t)
 	if err != nil {
@@ -121,26 +120,110 @@ func (tr thingRepository) Update(ctx context.Context, t things.Thing) error {
 }
 
 func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string) error {
-	q := `UPDATE things SET key = :key WHERE owner = :owner AND id = :id;`
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(ErrUpdateDb, err)
+	}
 
-	dbth := dbThing{
-		ID:    id,
-		Owner: owner,
-		Key:   key,
+	q := `UPDATE things_keys SET revoked = TRUE WHERE thing_id = :id AND thing_owner = :owner;`
+	res, err := tx.NamedExecContext(ctx, q, dbThing{ID: id, Owner: owner})
+	if err != nil {
+		tx.Rollback()
+		return keyError(err)
 	}
 
-	res, err := tr.db.NamedExecContext(ctx, q, dbth)
+	// Every thing has at least one key, revoked or not.
+	cnt, err := res.RowsAffected()
 	if err != nil {
-		pqErr, ok := err.(*pq.Error)
-		if ok {
-			switch pqErr.Code.Name() {
-			case errInvalid:
-				return errors.Wrap(things.ErrMalformedEntity, err)
-			case errDuplicate:
-				return errors.Wrap(things.ErrConflict, err)
-			}
+		tx.Rollback()
+		return errors.Wrap(ErrUpdateDb, err)
+	}
+	if cnt == 0 {
+		tx.Rollback()
+		return things.ErrNotFound
+	}
+
+	if err := upsertKey(ctx, tx, toDBKey(id, owner, things.ThingKey{Value: key})); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(ErrUpdateDb, err)
+	}
+
+	return nil
+}
+
+func (tr thingRepository) RotateKey(ctx context.Context, owner, id string, key things.ThingKey, expiresAt time.Time) error {
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(ErrUpdateDb, err)
+	}
+
+	// Shorten the lifetime of the existing keys to the end of the grace
+	// period, but never extend it.
+	q := `UPDATE things_keys SET expires_at = :expires_at
+	      WHERE thing_id = :thing_id AND thing_owner = :thing_owner AND revoked = FALSE
+	      AND (expires_at IS NULL OR expires_at > :expires_at);`
+	grace := toDBKey(id, owner, things.ThingKey{ExpiresAt: expiresAt})
+	if _, err := tx.NamedExecContext(ctx, q, grace); err != nil {
+		tx.Rollback()
+		return keyError(err)
+	}
+
+	if err := upsertKey(ctx, tx, toDBKey(id, owner, key)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(ErrUpdateDb, err)
+	}
+
+	return nil
+}
+
+func (tr thingRepository) RetrieveKeys(ctx context.Context, owner, id string) ([]things.ThingKey, error) {
+	if _, err := uuid.FromString(id); err != nil {
+		return nil, things.ErrNotFound
+	}
+
+	q := `SELECT key, thing_id, thing_owner, created_at, expires_at, revoked FROM things_keys
+	      WHERE thing_id = $1 AND thing_owner = $2 ORDER BY created_at DESC;`
+
+	rows, err := tr.db.QueryxContext(ctx, q, id, owner)
+	if err != nil {
+		return nil, errors.Wrap(ErrSelectDb, err)
+	}
+	defer rows.Close()
+
+	keys := []things.ThingKey{}
+	for rows.Next() {
+		var dbk dbKey
+		if err := rows.StructScan(&dbk); err != nil {
+			return nil, errors.Wrap(ErrSelectDb, err)
 		}
+		keys = append(keys, toKey(dbk))
+	}
+
+	if len(keys) == 0 {
+		return nil, things.ErrNotFound
+	}
+
+	return keys, nil
+}
+
+func (tr thingRepository) RevokeKey(ctx context.Context, owner, id, key string) error {
+	q := `UPDATE things_keys SET revoked = TRUE
+	      WHERE key = :key AND thing_id = :thing_id AND thing_owner = :thing_owner;`
 
+	res, err := tr.db.NamedExecContext(ctx, q, toDBKey(id, owner, things.ThingKey{Value: key}))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(things.ErrNotFound, err)
+		}
 		return errors.Wrap(ErrUpdateDb, err)
 	}
 
@@ -157,7 +240,7 @@ func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string)
 }
 
 func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Thing, error) {
-	q := `SELECT name, key, metadata FROM things WHERE id = $1 AND owner = $2;`
+	q := `SELECT name, ` + currentKey("things") + `, metadata, created_at, updated_at FROM things WHERE id = $1 AND owner = $2;`
 
 	dbth := dbThing{
 		ID:    id,
@@ -179,7 +262,7 @@ func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (t
 }
 
 func (tr thingRepository) RetrieveByKey(ctx context.Context, key string) (string, error) {
-	q := `SELECT id FROM things WHERE key = $1;`
+	q := `SELECT thing_id FROM things_keys WHERE key = $1 AND ` + validKey + `;`
 
 	var id string
 	if err := tr.db.QueryRowxContext(ctx, q, key).Scan(&id); err != nil {
@@ -192,24 +275,27 @@ func (tr thingRepository) RetrieveByKey(ctx context.Context, key string) (string
 	return id, nil
 }
 
-func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, tm things.Metadata) (things.Page, error) {
-	nq, name := getNameQuery(name)
-	m, mq, err := getMetadataQuery(tm)
+func (tr thingRepository) RetrieveAll(ctx context.Context, owner, member string, pm things.PageMetadata) (things.Page, error) {
+	params := map[string]interface{}{
+		"owner":  owner,
+		"member": member,
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+	}
+
+	fq, err := pageQuery(pm, params)
 	if err != nil {
 		return things.Page{}, errors.Wrap(ErrSelectDb, err)
 	}
 
-	q := fmt.Sprintf(`SELECT id, name, key, metadata FROM things
-		  WHERE owner = :owner %s%s ORDER BY id LIMIT :limit OFFSET :offset;`, mq, nq)
-
-	params := map[string]interface{}{
-		"owner":    owner,
-		"limit":    limit,
-		"offset":   offset,
-		"name":     name,
-		"metadata": m,
+	if pm.Disconnected {
+		fq += ` AND NOT EXISTS (SELECT 1 FROM connections co
+		        WHERE co.thing_id = things.id AND co.thing_owner = things.owner)`
 	}
 
+	q := fmt.Sprintf(`SELECT id, owner, name, %s, metadata, created_at, updated_at FROM things
+		  WHERE %s%s %s LIMIT :limit OFFSET :offset;`, currentKey("things"), ownedOrShared(things.ThingResource), fq, orderQuery(pm, ""))
+
 	rows, err := tr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
 		return things.Page{}, errors.Wrap(ErrSelectDb, err)
@@ -231,7 +317,7 @@ func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, offset,
 		items = append(items, th)
 	}
 
-	cq := fmt.Sprintf(`SELECT COUNT(*) FROM things WHERE owner = :owner %s%s;`, nq, mq)
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM things WHERE %s%s;`, ownedOrShared(things.ThingResource), fq)
 
 	total, err := total(ctx, tr.db, cq, params)
 	if err != nil {
@@ -242,8 +328,8 @@ func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, offset,
 		Things: items,
 		PageMetadata: things.PageMetadata{
 			Total:  total,
-			Offset: offset,
-			Limit:  limit,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
 		},
 	}
 
@@ -256,7 +342,7 @@ func (tr thingRepository) RetrieveByProject(ctx context.Context, owner, project
 		return things.Page{}, things.ErrNotFound
 	}
 
-	q := `SELECT id, name, key, metadata
+	q := `SELECT id, name, ` + currentKey("th") + `, metadata, created_at, updated_at
 	      FROM things th
 	      INNER JOIN connections co
 		  ON th.id = co.thing_id
@@ -326,12 +412,158 @@ func (tr thingRepository) Remove(ctx context.Context, owner, id string) error {
 	return nil
 }
 
+func (tr thingRepository) RemoveMany(ctx context.Context, ths ...things.Thing) ([]string, error) {
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(ErrDeleteDb, err)
+	}
+
+	q := `DELETE FROM things WHERE id = $1 AND owner = $2 RETURNING id;`
+
+	removed := []string{}
+	for _, th := range ths {
+		if _, err := uuid.FromString(th.ID); err != nil {
+			continue
+		}
+
+		var id string
+		if err := tx.QueryRowxContext(ctx, q, th.ID, th.Owner).Scan(&id); err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			tx.Rollback()
+			return nil, errors.Wrap(ErrDeleteDb, err)
+		}
+		removed = append(removed, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(ErrDeleteDb, err)
+	}
+
+	return removed, nil
+}
+
+type dbKey struct {
+	Key        string       `db:"key"`
+	ThingID    string       `db:"thing_id"`
+	ThingOwner string       `db:"thing_owner"`
+	CreatedAt  time.Time    `db:"created_at"`
+	ExpiresAt  sql.NullTime `db:"expires_at"`
+	Revoked    bool         `db:"revoked"`
+}
+
+func toDBKey(id, owner string, key things.ThingKey) dbKey {
+	createdAt := key.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	return dbKey{
+		Key:        key.Value,
+		ThingID:    id,
+		ThingOwner: owner,
+		CreatedAt:  createdAt.UTC(),
+		ExpiresAt:  sql.NullTime{Time: key.ExpiresAt.UTC(), Valid: !key.ExpiresAt.IsZero()},
+		Revoked:    key.Revoked,
+	}
+}
+
+func toKey(dbk dbKey) things.ThingKey {
+	key := things.ThingKey{
+		Value:     dbk.Key,
+		CreatedAt: dbk.CreatedAt,
+		Revoked:   dbk.Revoked,
+	}
+	if dbk.ExpiresAt.Valid {
+		key.ExpiresAt = dbk.ExpiresAt.Time
+	}
+
+	return key
+}
+
+// validKey is the condition that filters out expired and revoked keys.
+const validKey = `revoked = FALSE AND (expires_at IS NULL OR expires_at > (NOW() AT TIME ZONE 'UTC'))`
+
+// currentKey returns the column of the most recent valid key of the thing
+// having the provided table alias, since the keys are kept in things_keys.
+func currentKey(alias string) string {
+	return fmt.Sprintf(`COALESCE((SELECT tk.key FROM things_keys tk
+	        WHERE tk.thing_id = %[1]s.id AND tk.thing_owner = %[1]s.owner AND %[2]s
+	        ORDER BY tk.created_at DESC LIMIT 1), '') AS key`, alias, validKey)
+}
+
+func insertKey(ctx context.Context, tx *sqlx.Tx, dbk dbKey) error {
+	q := `INSERT INTO things_keys (key, thing_id, thing_owner, created_at, expires_at, revoked)
+	      VALUES (:key, :thing_id, :thing_owner, :created_at, :expires_at, :revoked);`
+
+	_, err := tx.NamedExecContext(ctx, q, dbk)
+	return err
+}
+
+// upsertKey inserts the key, or reinstates it if the thing used it before,
+// e.g. the key that was revoked. Key of another thing is the conflict.
+func upsertKey(ctx context.Context, tx *sqlx.Tx, dbk dbKey) error {
+	q := `INSERT INTO things_keys (key, thing_id, thing_owner, created_at, expires_at, revoked)
+	      VALUES (:key, :thing_id, :thing_owner, :created_at, :expires_at, :revoked)
+	      ON CONFLICT (key) DO UPDATE SET created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at, revoked = EXCLUDED.revoked
+	      WHERE things_keys.thing_id = EXCLUDED.thing_id AND things_keys.thing_owner = EXCLUDED.thing_owner;`
+
+	res, err := tx.NamedExecContext(ctx, q, dbk)
+	if err != nil {
+		return keyError(err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(ErrUpdateDb, err)
+	}
+
+	if cnt == 0 {
+		return things.ErrConflict
+	}
+
+	return nil
+}
+
+func keyError(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if ok {
+		switch pqErr.Code.Name() {
+		case errInvalid, errTruncation:
+			return errors.Wrap(things.ErrMalformedEntity, err)
+		case errDuplicate:
+			return errors.Wrap(things.ErrConflict, err)
+		case errFK:
+			return errors.Wrap(things.ErrNotFound, err)
+		}
+	}
+
+	return errors.Wrap(ErrUpdateDb, err)
+}
+
+func saveError(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if ok {
+		switch pqErr.Code.Name() {
+		case errInvalid, errTruncation:
+			return errors.Wrap(things.ErrMalformedEntity, err)
+		case errDuplicate:
+			return errors.Wrap(things.ErrConflict, err)
+		}
+	}
+
+	return errors.Wrap(ErrSaveDb, err)
+}
+
 type dbThing struct {
-	ID       string `db:"id"`
-	Owner    string `db:"owner"`
-	Name     string `db:"name"`
-	Key      string `db:"key"`
-	Metadata []byte `db:"metadata"`
+	ID        string    `db:"id"`
+	Owner     string    `db:"owner"`
+	Name      string    `db:"name"`
+	Key       string    `db:"key"`
+	Metadata  []byte    `db:"metadata"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
 }
 
 func toDBThing(th things.Thing) (dbThing, error) {
@@ -345,11 +577,13 @@ func toDBThing(th things.Thing) (dbThing, error) {
 	}
 
 	return dbThing{
-		ID:       th.ID,
-		Owner:    th.Owner,
-		Name:     th.Name,
-		Key:      th.Key,
-		Metadata: data,
+		ID:        th.ID,
+		Owner:     th.Owner,
+		Name:      th.Name,
+		Key:       th.Key,
+		Metadata:  data,
+		CreatedAt: th.CreatedAt,
+		UpdatedAt: th.UpdatedAt,
 	}, nil
 }
 
@@ -360,10 +594,12 @@ func toThing(dbth dbThing) (things.Thing, error) {
 	}
 
 	return things.Thing{
-		ID:       dbth.ID,
-		Owner:    dbth.Owner,
-		Name:     dbth.Name,
-		Key:      dbth.Key,
-		Metadata: metadata,
+		ID:        dbth.ID,
+		Owner:     dbth.Owner,
+		Name:      dbth.Name,
+		Key:       dbth.Key,
+		Metadata:  metadata,
+		CreatedAt: dbth.CreatedAt,
+		UpdatedAt: dbth.UpdatedAt,
 	}, nil
 }
@@ -0,0 +1,270 @@
+package things
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// QueryAnd matches the resources matching all the query terms.
+	QueryAnd = "and"
+
+	// QueryOr matches the resources matching any of the query terms.
+	QueryOr = "or"
+
+	// QueryExists matches the resources having the metadata path set.
+	QueryExists = "exists"
+
+	// QueryEq, QueryNe, QueryLt, QueryLe, QueryGt and QueryGe compare the
+	// metadata value found at the path to the query value. Ordering
+	// comparisons are numeric only.
+	QueryEq = "="
+	QueryNe = "!="
+	QueryLt = "<"
+	QueryLe = "<="
+	QueryGt = ">"
+	QueryGe = ">="
+)
+
+const maxQueryDepth = 16
+
+// Query represents the parsed metadata query, e.g.:
+//
+//	floor >= 2 and (building = "B" or serial exists)
+//
+// Compound queries (QueryAnd and QueryOr operators) combine their terms,
+// while conditions test the metadata value found at the dot-separated path.
+// Value is either string or float64. Keywords are case-insensitive.
+type Query struct {
+	Op    string
+	Terms []Query
+	Path  []string
+	Value interface{}
+}
+
+// Numeric returns true if the condition compares numeric values.
+func (q Query) Numeric() bool {
+	_, ok := q.Value.(float64)
+	return ok
+}
+
+// ParseQuery parses the metadata query. Empty query results in nil query
+// matching all the resources. ErrMalformedEntity is returned if the query
+// is malformed.
+func ParseQuery(s string) (*Query, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	tokens, err := lexQuery(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := queryParser{tokens: tokens}
+	q, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.tokens) {
+		return nil, ErrMalformedEntity
+	}
+
+	return &q, nil
+}
+
+type queryToken struct {
+	value  string
+	quoted bool
+}
+
+func lexQuery(s string) ([]queryToken, error) {
+	var tokens []queryToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, queryToken{value: string(c)})
+			i++
+		case c == '!' || c == '<' || c == '>' || c == '=':
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' && c != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, ErrMalformedEntity
+			}
+			tokens = append(tokens, queryToken{value: op})
+			i += len(op)
+		case c == '"':
+			end := i + 1
+			for ; end < len(s) && s[end] != '"'; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(s) {
+				return nil, ErrMalformedEntity
+			}
+			v, err := strconv.Unquote(s[i : end+1])
+			if err != nil {
+				return nil, ErrMalformedEntity
+			}
+			tokens = append(tokens, queryToken{value: v, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for ; end < len(s) && !strings.ContainsRune(" \t()!<>=\"", rune(s[end])); end++ {
+			}
+			tokens = append(tokens, queryToken{value: s[i:end]})
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *queryParser) next() (queryToken, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+// keyword returns true and consumes the next token if it's the given
+// unquoted keyword.
+func (p *queryParser) keyword(kw string) bool {
+	t, ok := p.peek()
+	if !ok || t.quoted || !strings.EqualFold(t.value, kw) {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *queryParser) or(depth int) (Query, error) {
+	return p.compound(depth, QueryOr, p.and)
+}
+
+func (p *queryParser) and(depth int) (Query, error) {
+	return p.compound(depth, QueryAnd, p.unary)
+}
+
+func (p *queryParser) compound(depth int, op string, term func(int) (Query, error)) (Query, error) {
+	first, err := term(depth)
+	if err != nil {
+		return Query{}, err
+	}
+
+	terms := []Query{first}
+	for p.keyword(op) {
+		t, err := term(depth)
+		if err != nil {
+			return Query{}, err
+		}
+		terms = append(terms, t)
+	}
+
+	if len(terms) == 1 {
+		return first, nil
+	}
+
+	return Query{Op: op, Terms: terms}, nil
+}
+
+func (p *queryParser) unary(depth int) (Query, error) {
+	t, ok := p.peek()
+	if !ok {
+		return Query{}, ErrMalformedEntity
+	}
+
+	if t.value == "(" && !t.quoted {
+		if depth >= maxQueryDepth {
+			return Query{}, ErrMalformedEntity
+		}
+		p.pos++
+		q, err := p.or(depth + 1)
+		if err != nil {
+			return Query{}, err
+		}
+		if t, ok := p.next(); !ok || t.value != ")" || t.quoted {
+			return Query{}, ErrMalformedEntity
+		}
+		return q, nil
+	}
+
+	return p.condition()
+}
+
+func (p *queryParser) condition() (Query, error) {
+	t, _ := p.next()
+	path, err := queryPath(t)
+	if err != nil {
+		return Query{}, err
+	}
+
+	if p.keyword(QueryExists) {
+		return Query{Op: QueryExists, Path: path}, nil
+	}
+
+	op, ok := p.next()
+	if !ok || op.quoted {
+		return Query{}, ErrMalformedEntity
+	}
+	switch op.value {
+	case QueryEq, QueryNe, QueryLt, QueryLe, QueryGt, QueryGe:
+	default:
+		return Query{}, ErrMalformedEntity
+	}
+
+	v, ok := p.next()
+	if !ok || !v.quoted && (v.value == "" || strings.ContainsAny(v.value, "()!<>=")) {
+		return Query{}, ErrMalformedEntity
+	}
+
+	q := Query{Op: op.value, Path: path, Value: v.value}
+	if !v.quoted {
+		if f, err := strconv.ParseFloat(v.value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			q.Value = f
+		}
+	}
+
+	if op.value != QueryEq && op.value != QueryNe && !q.Numeric() {
+		return Query{}, ErrMalformedEntity
+	}
+
+	return q, nil
+}
+
+func queryPath(t queryToken) ([]string, error) {
+	if t.quoted || t.value == "" || strings.ContainsAny(t.value, "()!<>=") {
+		return nil, ErrMalformedEntity
+	}
+
+	path := strings.Split(t.value, ".")
+	for _, p := range path {
+		if p == "" || strings.IndexFunc(p, unicode.IsControl) >= 0 {
+			return nil, ErrMalformedEntity
+		}
+	}
+
+	return path, nil
+}
@@ -2,7 +2,9 @@ package things
 
 import (
 	"context"
+	"time"
 
+	"github.com/vietquy/alpha/authn"
 	"github.com/vietquy/alpha/errors"
 
 	"github.com/vietquy/alpha"
@@ -31,6 +33,9 @@ var (
 
 	// ErrCreateProjects indicates error in creating Project
 	ErrCreateProjects = errors.New("create project failed")
+
+	// ErrRotateKey indicates error in rotating thing key
+	ErrRotateKey = errors.New("rotate thing key failed")
 )
 
 // Service specifies an API that must be fullfiled by the domain service
@@ -47,13 +52,26 @@ type Service interface {
 	// returned to indicate operation failure.
 	UpdateKey(ctx context.Context, token, id, key string) error
 
+	// RotateKey issues a new key for the thing identified by the provided ID,
+	// that belongs to the user identified by the provided key. The existing
+	// keys of the thing stay valid for the configured grace period.
+	RotateKey(ctx context.Context, token, id string) (ThingKey, error)
+
+	// ListKeys retrieves all the keys of the thing identified by the provided
+	// ID, that belongs to the user identified by the provided key.
+	ListKeys(ctx context.Context, token, id string) ([]ThingKey, error)
+
+	// RevokeKey revokes the key of the thing identified by the provided ID,
+	// that belongs to the user identified by the provided key.
+	RevokeKey(ctx context.Context, token, id, key string) error
+
 	// ViewThing retrieves data about the thing identified with the provided
 	// ID, that belongs to the user identified by the provided key.
 	ViewThing(ctx context.Context, token, id string) (Thing, error)
 
 	// ListThings retrieves data about subset of things that belongs to the
-	// user identified by the provided key.
-	ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata Metadata) (Page, error)
+	// user identified by the provided key and match the page filters.
+	ListThings(ctx context.Context, token string, pm PageMetadata) (Page, error)
 
 	// ListThingsByProject retrieves data about subset of things that are
 	// connected to specified project and belong to the user identified by
@@ -64,6 +82,10 @@ type Service interface {
 	// belongs to the user identified by the provided key.
 	RemoveThing(ctx context.Context, token, id string) error
 
+	// RemoveThings removes the things identified by the provided IDs at once,
+	// returning the result of the removal of each of them.
+	RemoveThings(ctx context.Context, token string, ids ...string) ([]BulkResult, error)
+
 	// CreateProjects adds a list of projects to the user identified by the provided key.
 	CreateProjects(ctx context.Context, token string, projects ...Project) ([]Project, error)
 
@@ -76,8 +98,8 @@ type Service interface {
 	ViewProject(ctx context.Context, token, id string) (Project, error)
 
 	// ListProjects retrieves data about subset of projects that belongs to the
-	// user identified by the provided key.
-	ListProjects(ctx context.Context, token string, offset, limit uint64, name string, m Metadata) (ProjectsPage, error)
+	// user identified by the provided key and match the page filters.
+	ListProjects(ctx context.Context, token string, pm PageMetadata) (ProjectsPage, error)
 
 	// ListProjectsByThing retrieves data about subset of projects that have
 	// specified thing connected to them and belong to the user identified by
@@ -88,13 +110,47 @@ type Service interface {
 	// belongs to the user identified by the provided key.
 	RemoveProject(ctx context.Context, token, id string) error
 
-	// Connect adds things to the project's list of connected things.
-	Connect(ctx context.Context, token string, chIDs, thIDs []string) error
+	// RemoveProjects removes the projects identified by the provided IDs at
+	// once, returning the result of the removal of each of them.
+	RemoveProjects(ctx context.Context, token string, ids ...string) ([]BulkResult, error)
+
+	// Connect adds things to the project's list of connected things. The
+	// policy restricts what the connected things can do on the projects.
+	Connect(ctx context.Context, token string, chIDs, thIDs []string, policy Policy) error
 
 	// Disconnect removes thing from the project's list of connected
 	// things.
 	Disconnect(ctx context.Context, token, projectID, thingID string) error
 
+	// DisconnectMany disconnects each of the things from each of the projects
+	// at once, returning the result of each of the disconnections.
+	DisconnectMany(ctx context.Context, token string, chIDs, thIDs []string) ([]DisconnectResult, error)
+
+	// Share shares the thing or the project with another user with the
+	// given role. Only the resource owner and admins can share it.
+	Share(ctx context.Context, token string, share Share) error
+
+	// Unshare revokes the access of the member to the shared resource.
+	Unshare(ctx context.Context, token, resource, id, member string) error
+
+	// ListShares retrieves all the shares of the resource.
+	ListShares(ctx context.Context, token, resource, id string) ([]Share, error)
+
+	// ListShared retrieves all the resources shared with the user identified
+	// by the provided token.
+	ListShared(ctx context.Context, token string) ([]Share, error)
+
+	// Export retrieves all the things, projects and connections owned by the
+	// user identified by the provided key. Thing keys are omitted if redact
+	// is set.
+	Export(ctx context.Context, token string, redact bool) (Backup, error)
+
+	// Import recreates the things, projects and connections found in the
+	// backup for the user identified by the provided key. Resources matching
+	// the existing ones are not recreated, so that importing the same backup
+	// again has no effect. Nothing is created in dry-run mode.
+	Import(ctx context.Context, token string, backup Backup, dryRun bool) (ImportReport, error)
+
 	// CanAccessByKey determines whether the project can be accessed using the
 	// provided key and returns thing's id if access is allowed.
 	CanAccessByKey(ctx context.Context, projectID, key string) (string, error)
@@ -103,50 +159,98 @@ type Service interface {
 	// the given thing and returns error if it cannot.
 	CanAccessByID(ctx context.Context, projectID, thingID string) error
 
+	// CanPublish determines whether the given thing is allowed to publish
+	// messages to the project subtopic.
+	CanPublish(ctx context.Context, projectID, thingID, subtopic string) error
+
+	// CanSubscribe determines whether the given thing is allowed to subscribe
+	// to the project subtopic. The subtopic may contain wildcards.
+	CanSubscribe(ctx context.Context, projectID, thingID, subtopic string) error
+
 	// Identify returns thing ID for given thing key.
 	Identify(ctx context.Context, key string) (string, error)
 }
 
-// PageMetadata contains page metadata that helps navigation.
+const (
+	// OrderName orders the listed resources by name.
+	OrderName = "name"
+
+	// OrderCreated orders the listed resources by creation time.
+	OrderCreated = "created"
+
+	// DirAsc orders the listed resources in ascending order.
+	DirAsc = "asc"
+
+	// DirDesc orders the listed resources in descending order.
+	DirDesc = "desc"
+)
+
+// PageMetadata contains page metadata that helps navigation, along with
+// the filters used to list the page. Metadata matches the resources
+// containing the given metadata, while Query matches the resources by the
+// metadata query. Disconnected matches the things not connected to any
+// project and is ignored when listing projects.
 type PageMetadata struct {
-	Total  uint64
-	Offset uint64
-	Limit  uint64
-	Name   string
+	Total        uint64
+	Offset       uint64
+	Limit        uint64
+	Name         string
+	Order        string
+	Dir          string
+	Metadata     Metadata
+	Query        *Query
+	Disconnected bool
 }
 
+// backupPageSize is the number of resources retrieved at once when
+// exporting the user resources.
+const backupPageSize = 100
+
 var _ Service = (*thingsService)(nil)
 
 type thingsService struct {
 	auth         alpha.AuthNServiceClient
 	things       ThingRepository
 	projects     ProjectRepository
+	shares       ShareRepository
+	thingCache   ThingCache
+	projectCache ProjectCache
 	idp          IdentityProvider
+	keyGrace     time.Duration
 }
 
-// New instantiates the things service implementation.
-func New(auth alpha.AuthNServiceClient, things ThingRepository, projects ProjectRepository, idp IdentityProvider) Service {
+// New instantiates the things service implementation. Parameter keyGrace
+// specifies for how long the existing thing keys stay valid after the key
+// rotation.
+func New(auth alpha.AuthNServiceClient, things ThingRepository, projects ProjectRepository, shares ShareRepository, tcache ThingCache, pcache ProjectCache, idp IdentityProvider, keyGrace time.Duration) Service {
 	return &thingsService{
 		auth:         auth,
 		things:       things,
 		projects:     projects,
+		shares:       shares,
+		thingCache:   tcache,
+		projectCache: pcache,
 		idp:          idp,
+		keyGrace:     keyGrace,
 	}
 }
 
 func (ts *thingsService) CreateThings(ctx context.Context, token string, things ...Thing) ([]Thing, error) {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	id, err := ts.identify(ctx, token)
 	if err != nil {
-		return []Thing{}, ErrUnauthorizedAccess
+		return []Thing{}, err
 	}
 
+	now := time.Now().UTC()
 	for i := range things {
+		things[i].CreatedAt = now
+		things[i].UpdatedAt = now
 		things[i].ID, err = ts.idp.ID()
 		if err != nil {
 			return []Thing{}, errors.Wrap(ErrCreateThings, err)
 		}
 
-		things[i].Owner = res.GetValue()
+		things[i].Owner = id.owner
 
 		if things[i].Key == "" {
 			things[i].Key, err = ts.idp.ID()
@@ -160,170 +264,880 @@ func (ts *thingsService) CreateThings(ctx context.Context, token string, things
 }
 
 func (ts *thingsService) UpdateThing(ctx context.Context, token string, thing Thing) error {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ThingResource, thing.ID, RoleEditor)
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return err
 	}
 
-	thing.Owner = res.GetValue()
+	thing.Owner = owner
+	thing.UpdatedAt = time.Now().UTC()
 
 	return ts.things.Update(ctx, thing)
 }
 
 func (ts *thingsService) UpdateKey(ctx context.Context, token, id, key string) error {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ThingResource, id, RoleEditor)
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return err
+	}
+
+	if err := ts.things.UpdateKey(ctx, owner, id, key); err != nil {
+		return err
 	}
 
-	owner := res.GetValue()
+	return ts.thingCache.Remove(ctx, id)
+}
+
+func (ts *thingsService) RotateKey(ctx context.Context, token, id string) (ThingKey, error) {
+	owner, err := ts.resourceOwner(ctx, token, ThingResource, id, RoleEditor)
+	if err != nil {
+		return ThingKey{}, err
+	}
 
-	return ts.things.UpdateKey(ctx, owner, id, key)
+	value, err := ts.idp.ID()
+	if err != nil {
+		return ThingKey{}, errors.Wrap(ErrRotateKey, err)
+	}
+
+	now := time.Now().UTC()
+	key := ThingKey{
+		Value:     value,
+		CreatedAt: now,
+	}
+
+	if err := ts.things.RotateKey(ctx, owner, id, key, now.Add(ts.keyGrace)); err != nil {
+		return ThingKey{}, err
+	}
+
+	// Cached keys are dropped so that the expiration of the previous keys
+	// is enforced no later than the cache entry TTL.
+	if err := ts.thingCache.Remove(ctx, id); err != nil {
+		return ThingKey{}, err
+	}
+
+	return key, nil
+}
+
+func (ts *thingsService) ListKeys(ctx context.Context, token, id string) ([]ThingKey, error) {
+	owner, err := ts.resourceOwner(ctx, token, ThingResource, id, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
 
+	return ts.things.RetrieveKeys(ctx, owner, id)
+}
+
+func (ts *thingsService) RevokeKey(ctx context.Context, token, id, key string) error {
+	owner, err := ts.resourceOwner(ctx, token, ThingResource, id, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	if err := ts.things.RevokeKey(ctx, owner, id, key); err != nil {
+		return err
+	}
+
+	return ts.thingCache.Remove(ctx, id)
 }
 
 func (ts *thingsService) ViewThing(ctx context.Context, token, id string) (Thing, error) {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ThingResource, id, RoleViewer)
 	if err != nil {
-		return Thing{}, ErrUnauthorizedAccess
+		return Thing{}, err
 	}
 
-	return ts.things.RetrieveByID(ctx, res.GetValue(), id)
+	return ts.things.RetrieveByID(ctx, owner, id)
 }
 
-func (ts *thingsService) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata Metadata) (Page, error) {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+func (ts *thingsService) ListThings(ctx context.Context, token string, pm PageMetadata) (Page, error) {
+	id, err := ts.identify(ctx, token)
 	if err != nil {
-		return Page{}, errors.Wrap(ErrUnauthorizedAccess, err)
+		return Page{}, err
 	}
 
-	// tp, err := ts.things.RetrieveAll(ctx, res.GetValue(), offset, limit, name, metadata)
-	// return tp, errors.Wrap(ErrUnauthorizedAccess, err)
-	return ts.things.RetrieveAll(ctx, res.GetValue(), offset, limit, name, metadata)
+	return ts.things.RetrieveAll(ctx, id.owner, id.user, pm)
 }
 
 func (ts *thingsService) ListThingsByProject(ctx context.Context, token, project string, offset, limit uint64) (Page, error) {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ProjectResource, project, RoleViewer)
 	if err != nil {
-		return Page{}, errors.Wrap(ErrUnauthorizedAccess, err)
+		return Page{}, err
 	}
 
-	return ts.things.RetrieveByProject(ctx, res.GetValue(), project, offset, limit)
+	return ts.things.RetrieveByProject(ctx, owner, project, offset, limit)
 }
 
 func (ts *thingsService) RemoveThing(ctx context.Context, token, id string) error {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ThingResource, id, RoleAdmin)
 	if err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+		return err
 	}
 
-	return ts.things.Remove(ctx, res.GetValue(), id)
+	if err := ts.things.Remove(ctx, owner, id); err != nil {
+		return err
+	}
+
+	if err := ts.shares.RemoveAll(ctx, ThingResource, id); err != nil {
+		return err
+	}
+
+	if err := ts.thingCache.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	return ts.projectCache.RemoveThing(ctx, id)
+}
+
+func (ts *thingsService) RemoveThings(ctx context.Context, token string, ids ...string) ([]BulkResult, error) {
+	user, err := ts.identify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	results, owners, err := ts.bulkOwners(ctx, user, ThingResource, ids, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	ths := []Thing{}
+	for id, owner := range owners {
+		ths = append(ths, Thing{ID: id, Owner: owner})
+	}
+
+	removed, err := ts.things.RemoveMany(ctx, ths...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range removed {
+		if err := ts.shares.RemoveAll(ctx, ThingResource, id); err != nil {
+			return nil, err
+		}
+
+		if err := ts.thingCache.Remove(ctx, id); err != nil {
+			return nil, err
+		}
+
+		if err := ts.projectCache.RemoveThing(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return bulkResults(results, removed), nil
 }
 
 func (ts *thingsService) CreateProjects(ctx context.Context, token string, projects ...Project) ([]Project, error) {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	id, err := ts.identify(ctx, token)
 	if err != nil {
-		return []Project{}, ErrUnauthorizedAccess
+		return []Project{}, err
 	}
 
+	now := time.Now().UTC()
 	for i := range projects {
+		projects[i].CreatedAt = now
+		projects[i].UpdatedAt = now
 		projects[i].ID, err = ts.idp.ID()
 		if err != nil {
 			return []Project{}, errors.Wrap(ErrCreateProjects, err)
 		}
 
-		projects[i].Owner = res.GetValue()
+		projects[i].Owner = id.owner
 	}
 
 	return ts.projects.Save(ctx, projects...)
 }
 
 func (ts *thingsService) UpdateProject(ctx context.Context, token string, project Project) error {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ProjectResource, project.ID, RoleEditor)
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return err
 	}
 
-	project.Owner = res.GetValue()
+	project.Owner = owner
+	project.UpdatedAt = time.Now().UTC()
+
 	return ts.projects.Update(ctx, project)
 }
 
 func (ts *thingsService) ViewProject(ctx context.Context, token, id string) (Project, error) {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ProjectResource, id, RoleViewer)
 	if err != nil {
-		return Project{}, ErrUnauthorizedAccess
+		return Project{}, err
 	}
 
-	return ts.projects.RetrieveByID(ctx, res.GetValue(), id)
+	return ts.projects.RetrieveByID(ctx, owner, id)
 }
 
-func (ts *thingsService) ListProjects(ctx context.Context, token string, offset, limit uint64, name string, m Metadata) (ProjectsPage, error) {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+func (ts *thingsService) ListProjects(ctx context.Context, token string, pm PageMetadata) (ProjectsPage, error) {
+	id, err := ts.identify(ctx, token)
 	if err != nil {
-		return ProjectsPage{}, ErrUnauthorizedAccess
+		return ProjectsPage{}, err
 	}
 
-	return ts.projects.RetrieveAll(ctx, res.GetValue(), offset, limit, name, m)
+	return ts.projects.RetrieveAll(ctx, id.owner, id.user, pm)
 }
 
 func (ts *thingsService) ListProjectsByThing(ctx context.Context, token, thing string, offset, limit uint64) (ProjectsPage, error) {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ThingResource, thing, RoleViewer)
 	if err != nil {
-		return ProjectsPage{}, ErrUnauthorizedAccess
+		return ProjectsPage{}, err
 	}
 
-	return ts.projects.RetrieveByThing(ctx, res.GetValue(), thing, offset, limit)
+	return ts.projects.RetrieveByThing(ctx, owner, thing, offset, limit)
 }
 
 func (ts *thingsService) RemoveProject(ctx context.Context, token, id string) error {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ProjectResource, id, RoleAdmin)
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return err
+	}
+
+	if err := ts.projects.Remove(ctx, owner, id); err != nil {
+		return err
 	}
 
-	return ts.projects.Remove(ctx, res.GetValue(), id)
+	if err := ts.shares.RemoveAll(ctx, ProjectResource, id); err != nil {
+		return err
+	}
+
+	return ts.projectCache.Remove(ctx, id)
 }
 
-func (ts *thingsService) Connect(ctx context.Context, token string, chIDs, thIDs []string) error {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+func (ts *thingsService) RemoveProjects(ctx context.Context, token string, ids ...string) ([]BulkResult, error) {
+	user, err := ts.identify(ctx, token)
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return nil, err
+	}
+
+	results, owners, err := ts.bulkOwners(ctx, user, ProjectResource, ids, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	chs := []Project{}
+	for id, owner := range owners {
+		chs = append(chs, Project{ID: id, Owner: owner})
 	}
 
-	return ts.projects.Connect(ctx, res.GetValue(), chIDs, thIDs)
+	removed, err := ts.projects.RemoveMany(ctx, chs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range removed {
+		if err := ts.shares.RemoveAll(ctx, ProjectResource, id); err != nil {
+			return nil, err
+		}
+
+		if err := ts.projectCache.Remove(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return bulkResults(results, removed), nil
+}
+
+func (ts *thingsService) Connect(ctx context.Context, token string, chIDs, thIDs []string, policy Policy) error {
+	id, err := ts.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	owner, err := ts.connectionOwner(ctx, id, chIDs, thIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := ts.projects.Connect(ctx, owner, chIDs, thIDs, policy); err != nil {
+		return err
+	}
+
+	for _, chID := range chIDs {
+		for _, thID := range thIDs {
+			if err := ts.projectCache.Connect(ctx, chID, thID, policy); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 func (ts *thingsService) Disconnect(ctx context.Context, token, projectID, thingID string) error {
-	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	owner, err := ts.resourceOwner(ctx, token, ProjectResource, projectID, RoleEditor)
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return err
+	}
+
+	if err := ts.projects.Disconnect(ctx, owner, projectID, thingID); err != nil {
+		return err
+	}
+
+	return ts.projectCache.Disconnect(ctx, projectID, thingID)
+}
+
+func (ts *thingsService) DisconnectMany(ctx context.Context, token string, chIDs, thIDs []string) ([]DisconnectResult, error) {
+	user, err := ts.identify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	chResults, owners, err := ts.bulkOwners(ctx, user, ProjectResource, chIDs, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	conns := []Connection{}
+	for chID, owner := range owners {
+		for _, thID := range thIDs {
+			conns = append(conns, Connection{ProjectID: chID, ThingID: thID, Owner: owner})
+		}
+	}
+
+	removed, err := ts.projects.DisconnectMany(ctx, conns...)
+	if err != nil {
+		return nil, err
+	}
+
+	disconnected := map[[2]string]bool{}
+	for _, conn := range removed {
+		if err := ts.projectCache.Disconnect(ctx, conn.ProjectID, conn.ThingID); err != nil {
+			return nil, err
+		}
+		disconnected[[2]string{conn.ProjectID, conn.ThingID}] = true
+	}
+
+	results := []DisconnectResult{}
+	for _, chRes := range chResults {
+		for _, thID := range thIDs {
+			res := DisconnectResult{
+				ProjectID: chRes.ID,
+				ThingID:   thID,
+				Err:       chRes.Err,
+			}
+			if res.Err == nil && !disconnected[[2]string{chRes.ID, thID}] {
+				res.Err = ErrNotFound
+			}
+			results = append(results, res)
+		}
+	}
+
+	return results, nil
+}
+
+func (ts *thingsService) Share(ctx context.Context, token string, share Share) error {
+	id, err := ts.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	owner, err := ts.shareOwner(ctx, id, share.Resource, share.ID, RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if share.Member == id.user {
+		return ErrMalformedEntity
+	}
+
+	if err := ts.exists(ctx, owner, share.Resource, share.ID); err != nil {
+		return err
+	}
+
+	share.Owner = owner
+	return ts.shares.Save(ctx, share)
+}
+
+func (ts *thingsService) Unshare(ctx context.Context, token, resource, id, member string) error {
+	owner, err := ts.resourceOwner(ctx, token, resource, id, RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	return ts.shares.Remove(ctx, owner, resource, id, member)
+}
+
+func (ts *thingsService) ListShares(ctx context.Context, token, resource, id string) ([]Share, error) {
+	owner, err := ts.resourceOwner(ctx, token, resource, id, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.exists(ctx, owner, resource, id); err != nil {
+		return nil, err
+	}
+
+	return ts.shares.RetrieveByResource(ctx, owner, resource, id)
+}
+
+func (ts *thingsService) ListShared(ctx context.Context, token string) ([]Share, error) {
+	id, err := ts.identify(ctx, token)
+	if err != nil {
+		return nil, err
 	}
 
-	return ts.projects.Disconnect(ctx, res.GetValue(), projectID, thingID)
+	return ts.shares.RetrieveByMember(ctx, id.user)
+}
+
+func (ts *thingsService) Export(ctx context.Context, token string, redact bool) (Backup, error) {
+	id, err := ts.identify(ctx, token)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	backup, err := ts.backup(ctx, id.owner)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	if redact {
+		for i := range backup.Things {
+			backup.Things[i].Key = ""
+		}
+	}
+
+	return backup, nil
+}
+
+func (ts *thingsService) Import(ctx context.Context, token string, backup Backup, dryRun bool) (ImportReport, error) {
+	if err := backup.Validate(); err != nil {
+		return ImportReport{}, err
+	}
+
+	id, err := ts.identify(ctx, token)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	current, err := ts.backup(ctx, id.owner)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	report := ImportReport{DryRun: dryRun}
+
+	ths, err := ts.importThings(ctx, current.Things, backup.Things)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	report.Things = ths
+
+	report.Projects = importProjects(current.Projects, backup.Projects)
+
+	if !dryRun {
+		if err := ts.createImported(ctx, token, report.Things, backup.Things, report.Projects, backup.Projects); err != nil {
+			return ImportReport{}, err
+		}
+	}
+
+	thIDs := importedIDs(report.Things)
+	chIDs := importedIDs(report.Projects)
+	connected := map[[2]string]bool{}
+	for _, conn := range current.Connections {
+		connected[[2]string{conn.ProjectID, conn.ThingID}] = true
+	}
+
+	for _, conn := range backup.Connections {
+		chID, thID := chIDs[conn.ProjectID], thIDs[conn.ThingID]
+		res := ConnectionImportResult{
+			Connection: conn,
+			Created:    chID == "" || thID == "" || !connected[[2]string{chID, thID}],
+		}
+		report.Connections = append(report.Connections, res)
+
+		if dryRun || !res.Created {
+			continue
+		}
+
+		if err := ts.Connect(ctx, token, []string{chID}, []string{thID}, conn.Policy); err != nil {
+			return ImportReport{}, err
+		}
+		connected[[2]string{chID, thID}] = true
+	}
+
+	return report, nil
 }
 
 func (ts *thingsService) CanAccessByKey(ctx context.Context, projectID, key string) (string, error) {
+	if thingID, err := ts.thingCache.ID(ctx, key); err == nil {
+		if _, err := ts.projectCache.Policy(ctx, projectID, thingID); err == nil {
+			return thingID, nil
+		}
+	}
+
 	thingID, err := ts.projects.HasThing(ctx, projectID, key)
 	if err != nil {
 		return "", ErrUnauthorizedAccess
 	}
 
+	if err := ts.thingCache.Save(ctx, key, thingID); err != nil {
+		return "", err
+	}
+
+	if _, err := ts.policy(ctx, projectID, thingID); err != nil {
+		return "", err
+	}
+
 	return thingID, nil
 }
 
 func (ts *thingsService) CanAccessByID(ctx context.Context, projectID, thingID string) error {
-	if err := ts.projects.HasThingByID(ctx, projectID, thingID); err != nil {
+	_, err := ts.policy(ctx, projectID, thingID)
+	return err
+}
+
+func (ts *thingsService) CanPublish(ctx context.Context, projectID, thingID, subtopic string) error {
+	return ts.authorize(ctx, projectID, thingID, ActionPublish, subtopic)
+}
+
+func (ts *thingsService) CanSubscribe(ctx context.Context, projectID, thingID, subtopic string) error {
+	return ts.authorize(ctx, projectID, thingID, ActionSubscribe, subtopic)
+}
+
+func (ts *thingsService) authorize(ctx context.Context, projectID, thingID, action, subtopic string) error {
+	policy, err := ts.policy(ctx, projectID, thingID)
+	if err != nil {
+		return err
+	}
+
+	if !policy.Allows(action, subtopic) {
 		return ErrUnauthorizedAccess
 	}
 
 	return nil
 }
 
+// policy returns the connection policy, reading through the project cache.
+func (ts *thingsService) policy(ctx context.Context, projectID, thingID string) (Policy, error) {
+	if policy, err := ts.projectCache.Policy(ctx, projectID, thingID); err == nil {
+		return policy, nil
+	}
+
+	policy, err := ts.projects.RetrievePolicy(ctx, projectID, thingID)
+	if err != nil {
+		return Policy{}, ErrUnauthorizedAccess
+	}
+
+	if err := ts.projectCache.Connect(ctx, projectID, thingID, policy); err != nil {
+		return Policy{}, err
+	}
+
+	return policy, nil
+}
+
 func (ts *thingsService) Identify(ctx context.Context, key string) (string, error) {
+	if id, err := ts.thingCache.ID(ctx, key); err == nil {
+		return id, nil
+	}
+
 	id, err := ts.things.RetrieveByKey(ctx, key)
 	if err != nil {
 		return "", ErrUnauthorizedAccess
 	}
 
+	if err := ts.thingCache.Save(ctx, key, id); err != nil {
+		return "", err
+	}
+
 	return id, nil
 }
+
+// identity represents the user identified by the token, along with the
+// owner of the user resources, see authn.Owner.
+type identity struct {
+	user  string
+	owner string
+}
+
+// identify returns the identity of the user identified by the provided
+// token.
+func (ts *thingsService) identify(ctx context.Context, token string) (identity, error) {
+	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	if err != nil {
+		return identity{}, ErrUnauthorizedAccess
+	}
+
+	id := identity{
+		user:  res.GetValue(),
+		owner: authn.Owner(res.GetValue(), res.GetOrganization()),
+	}
+
+	return id, nil
+}
+
+// resourceOwner returns the owner of the resource the user identified by the
+// provided token can access with at least the given role.
+func (ts *thingsService) resourceOwner(ctx context.Context, token, resource, id, role string) (string, error) {
+	user, err := ts.identify(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	return ts.shareOwner(ctx, user, resource, id, role)
+}
+
+// shareOwner returns the owner of the resource if it's shared with the user
+// with at least the given role. If the resource is not shared with the user,
+// the user organization is considered to be the owner, leaving it to the
+// repositories to scope the access by the owner.
+func (ts *thingsService) shareOwner(ctx context.Context, user identity, resource, id, role string) (string, error) {
+	share, err := ts.shares.Retrieve(ctx, resource, id, user.user)
+	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return user.owner, nil
+		}
+		return "", err
+	}
+
+	if !share.Allows(role) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return share.Owner, nil
+}
+
+// connectionOwner returns the owner of the projects and things the user is
+// allowed to connect. Connected projects and things must have the same
+// owner.
+func (ts *thingsService) connectionOwner(ctx context.Context, user identity, chIDs, thIDs []string) (string, error) {
+	owner := ""
+	check := func(resource, id string) error {
+		o, err := ts.shareOwner(ctx, user, resource, id, RoleEditor)
+		if err != nil {
+			return err
+		}
+		if owner != "" && o != owner {
+			return ErrUnauthorizedAccess
+		}
+		owner = o
+		return nil
+	}
+
+	for _, chID := range chIDs {
+		if err := check(ProjectResource, chID); err != nil {
+			return "", err
+		}
+	}
+
+	for _, thID := range thIDs {
+		if err := check(ThingResource, thID); err != nil {
+			return "", err
+		}
+	}
+
+	return owner, nil
+}
+
+// backup returns all the things, projects and connections owned by the
+// given owner.
+func (ts *thingsService) backup(ctx context.Context, owner string) (Backup, error) {
+	backup := Backup{}
+
+	pm := PageMetadata{Limit: backupPageSize}
+	for {
+		page, err := ts.things.RetrieveAll(ctx, owner, "", pm)
+		if err != nil {
+			return Backup{}, err
+		}
+		backup.Things = append(backup.Things, page.Things...)
+
+		pm.Offset += pm.Limit
+		if pm.Offset >= page.Total {
+			break
+		}
+	}
+
+	pm = PageMetadata{Limit: backupPageSize}
+	for {
+		page, err := ts.projects.RetrieveAll(ctx, owner, "", pm)
+		if err != nil {
+			return Backup{}, err
+		}
+		backup.Projects = append(backup.Projects, page.Projects...)
+
+		pm.Offset += pm.Limit
+		if pm.Offset >= page.Total {
+			break
+		}
+	}
+
+	conns, err := ts.projects.RetrieveConnections(ctx, owner)
+	if err != nil {
+		return Backup{}, err
+	}
+	backup.Connections = conns
+
+	return backup, nil
+}
+
+// importThings matches the imported things to the existing ones by ID, then
+// by key, and by name if the imported thing key is omitted. ErrConflict is
+// returned if the imported thing key belongs to the thing of another user.
+func (ts *thingsService) importThings(ctx context.Context, existing, imported []Thing) ([]ImportResult, error) {
+	byID := map[string]string{}
+	byKey := map[string]string{}
+	byName := map[string]string{}
+	for _, th := range existing {
+		byID[th.ID] = th.ID
+		byKey[th.Key] = th.ID
+		if _, ok := byName[th.Name]; !ok && th.Name != "" {
+			byName[th.Name] = th.ID
+		}
+	}
+
+	results := []ImportResult{}
+	for _, th := range imported {
+		res := ImportResult{ID: th.ID}
+		switch {
+		case byID[th.ID] != "":
+			res.NewID = byID[th.ID]
+		case th.Key != "":
+			res.NewID = byKey[th.Key]
+			if res.NewID == "" {
+				if _, err := ts.things.RetrieveByKey(ctx, th.Key); err == nil {
+					return nil, ErrConflict
+				}
+			}
+		default:
+			res.NewID = byName[th.Name]
+		}
+		res.Created = res.NewID == ""
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// importProjects matches the imported projects to the existing ones by ID,
+// and by name otherwise.
+func importProjects(existing, imported []Project) []ImportResult {
+	byID := map[string]string{}
+	byName := map[string]string{}
+	for _, ch := range existing {
+		byID[ch.ID] = ch.ID
+		if _, ok := byName[ch.Name]; !ok && ch.Name != "" {
+			byName[ch.Name] = ch.ID
+		}
+	}
+
+	results := []ImportResult{}
+	for _, ch := range imported {
+		res := ImportResult{ID: ch.ID, NewID: byID[ch.ID]}
+		if res.NewID == "" && ch.Name != "" {
+			res.NewID = byName[ch.Name]
+		}
+		res.Created = res.NewID == ""
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// createImported creates the imported things and projects not matching the
+// existing ones, setting the IDs of the created resources in the results.
+func (ts *thingsService) createImported(ctx context.Context, token string, thResults []ImportResult, ths []Thing, chResults []ImportResult, chs []Project) error {
+	newThs, thIdx := []Thing{}, []int{}
+	for i, res := range thResults {
+		if res.Created {
+			th := ths[i]
+			newThs = append(newThs, Thing{Name: th.Name, Key: th.Key, Metadata: th.Metadata})
+			thIdx = append(thIdx, i)
+		}
+	}
+
+	if len(newThs) > 0 {
+		saved, err := ts.CreateThings(ctx, token, newThs...)
+		if err != nil {
+			return err
+		}
+		for i, th := range saved {
+			thResults[thIdx[i]].NewID = th.ID
+		}
+	}
+
+	newChs, chIdx := []Project{}, []int{}
+	for i, res := range chResults {
+		if res.Created {
+			ch := chs[i]
+			newChs = append(newChs, Project{Name: ch.Name, Metadata: ch.Metadata})
+			chIdx = append(chIdx, i)
+		}
+	}
+
+	if len(newChs) > 0 {
+		saved, err := ts.CreateProjects(ctx, token, newChs...)
+		if err != nil {
+			return err
+		}
+		for i, ch := range saved {
+			chResults[chIdx[i]].NewID = ch.ID
+		}
+	}
+
+	return nil
+}
+
+// importedIDs maps the IDs of the resources in the backup to the IDs of the
+// imported resources.
+func importedIDs(results []ImportResult) map[string]string {
+	ids := map[string]string{}
+	for _, res := range results {
+		ids[res.ID] = res.NewID
+	}
+
+	return ids
+}
+
+// bulkOwners returns the owners of the resources the user can access with at
+// least the given role, along with the results of the bulk operation having the access
+// errors set for the rest of the resources.
+func (ts *thingsService) bulkOwners(ctx context.Context, user identity, resource string, ids []string, role string) ([]BulkResult, map[string]string, error) {
+	results := make([]BulkResult, len(ids))
+	owners := map[string]string{}
+	for i, id := range ids {
+		results[i].ID = id
+		owner, err := ts.shareOwner(ctx, user, resource, id, role)
+		if err != nil {
+			if !errors.Contains(err, ErrUnauthorizedAccess) {
+				return nil, nil, err
+			}
+			results[i].Err = err
+			continue
+		}
+		owners[id] = owner
+	}
+
+	return results, owners, nil
+}
+
+// bulkResults sets ErrNotFound as the result of the bulk operation on the
+// accessible resources which haven't been removed.
+func bulkResults(results []BulkResult, removed []string) []BulkResult {
+	ok := map[string]bool{}
+	for _, id := range removed {
+		ok[id] = true
+	}
+
+	for i := range results {
+		if results[i].Err == nil && !ok[results[i].ID] {
+			results[i].Err = ErrNotFound
+		}
+	}
+
+	return results
+}
+
+// exists returns ErrNotFound if the resource owned by the given user
+// doesn't exist.
+func (ts *thingsService) exists(ctx context.Context, owner, resource, id string) error {
+	switch resource {
+	case ThingResource:
+		_, err := ts.things.RetrieveByID(ctx, owner, id)
+		return err
+	case ProjectResource:
+		_, err := ts.projects.RetrieveByID(ctx, owner, id)
+		return err
+	default:
+		return ErrMalformedEntity
+	}
+}
@@ -0,0 +1,82 @@
+package things
+
+import "context"
+
+const (
+	// ThingResource represents shared thing.
+	ThingResource = "thing"
+
+	// ProjectResource represents shared project.
+	ProjectResource = "project"
+)
+
+const (
+	// RoleViewer allows the member to view the resource.
+	RoleViewer = "viewer"
+
+	// RoleEditor allows the member to update the resource, its keys and
+	// connections, in addition to viewing it.
+	RoleEditor = "editor"
+
+	// RoleAdmin allows the member to remove and share the resource, in
+	// addition to editing it.
+	RoleAdmin = "admin"
+)
+
+var roles = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// Share represents the thing or the project shared by its owner with
+// another user (member) with the given role.
+type Share struct {
+	Resource string
+	ID       string
+	Owner    string
+	Member   string
+	Role     string
+}
+
+// Allows returns true if the share role grants the permissions of the
+// given role.
+func (s Share) Allows(role string) bool {
+	r, ok := roles[s.Role]
+	return ok && r >= roles[role]
+}
+
+// ValidRole returns true if the role is one of the known roles.
+func ValidRole(role string) bool {
+	_, ok := roles[role]
+	return ok
+}
+
+// ValidResource returns true if the resource can be shared.
+func ValidResource(resource string) bool {
+	return resource == ThingResource || resource == ProjectResource
+}
+
+// ShareRepository specifies a share persistence API.
+type ShareRepository interface {
+	// Save persists the share. Sharing the resource with the same member
+	// again replaces the member role.
+	Save(ctx context.Context, share Share) error
+
+	// Retrieve retrieves the share of the resource with the member.
+	Retrieve(ctx context.Context, resource, id, member string) (Share, error)
+
+	// RetrieveByResource retrieves all the shares of the resource owned by
+	// the specified user.
+	RetrieveByResource(ctx context.Context, owner, resource, id string) ([]Share, error)
+
+	// RetrieveByMember retrieves all the resources shared with the member.
+	RetrieveByMember(ctx context.Context, member string) ([]Share, error)
+
+	// Remove removes the share of the resource owned by the specified user
+	// with the member.
+	Remove(ctx context.Context, owner, resource, id, member string) error
+
+	// RemoveAll removes all the shares of the resource.
+	RemoveAll(ctx context.Context, resource, id string) error
+}
@@ -0,0 +1,310 @@
+package cbor
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/vietquy/alpha/errors"
+)
+
+// CBOR major types, see RFC 8949.
+const (
+	majorUint = iota
+	majorNegInt
+	majorBytes
+	majorText
+	majorArray
+	majorMap
+	majorTag
+	majorSimple
+)
+
+const (
+	indefiniteLength = 31
+	breakCode        = 0xff
+	// maxNesting limits the nesting of arrays and maps, so that the
+	// malformed payload can't exhaust the stack.
+	maxNesting = 16
+)
+
+var (
+	errMalformed = errors.New("malformed CBOR payload")
+	errBreak     = errors.New("unexpected CBOR break")
+)
+
+// Decode decodes the CBOR data item into the Go value. Integers are
+// decoded to int64, floats to float64, byte strings to []byte, text strings
+// to string, arrays to []interface{}, maps to map[interface{}]interface{},
+// and the simple values to bool or nil. Tags are ignored.
+func Decode(data []byte) (interface{}, error) {
+	d := decoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(d.data) {
+		return nil, errMalformed
+	}
+
+	return v, nil
+}
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+func (d *decoder) decode(depth int) (interface{}, error) {
+	if depth > maxNesting {
+		return nil, errMalformed
+	}
+	if d.off >= len(d.data) {
+		return nil, errMalformed
+	}
+
+	b := d.data[d.off]
+	if b == breakCode {
+		return nil, errBreak
+	}
+	d.off++
+	major, info := b>>5, b&0x1f
+
+	if major == majorSimple {
+		return d.simple(info)
+	}
+
+	if info == indefiniteLength {
+		return d.indefinite(major, depth)
+	}
+
+	arg, err := d.arg(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUint:
+		if arg > math.MaxInt64 {
+			return nil, errMalformed
+		}
+		return int64(arg), nil
+	case majorNegInt:
+		if arg > math.MaxInt64 {
+			return nil, errMalformed
+		}
+		return -1 - int64(arg), nil
+	case majorBytes:
+		return d.bytes(arg)
+	case majorText:
+		b, err := d.bytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case majorArray:
+		if arg > uint64(len(d.data)-d.off) {
+			return nil, errMalformed
+		}
+		arr := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	case majorMap:
+		if arg > uint64(len(d.data)-d.off) {
+			return nil, errMalformed
+		}
+		m := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			if err := d.entry(m, depth); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	default:
+		// Tagged data item is decoded as the untagged one.
+		return d.decode(depth + 1)
+	}
+}
+
+// indefinite decodes the indefinite length string, array or map, whose items
+// are terminated by the break.
+func (d *decoder) indefinite(major byte, depth int) (interface{}, error) {
+	switch major {
+	case majorBytes, majorText:
+		var buf []byte
+		for !d.isBreak() {
+			// Chunks must be the definite strings of the same type.
+			if d.off >= len(d.data) || d.data[d.off]>>5 != major || d.data[d.off]&0x1f == indefiniteLength {
+				return nil, errMalformed
+			}
+			chunk, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch c := chunk.(type) {
+			case []byte:
+				buf = append(buf, c...)
+			case string:
+				buf = append(buf, c...)
+			}
+		}
+		if major == majorText {
+			return string(buf), nil
+		}
+		return buf, nil
+	case majorArray:
+		arr := []interface{}{}
+		for !d.isBreak() {
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	case majorMap:
+		m := map[interface{}]interface{}{}
+		for !d.isBreak() {
+			if err := d.entry(m, depth); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	default:
+		return nil, errMalformed
+	}
+}
+
+// isBreak checks whether the next byte is the break, consuming it if so.
+func (d *decoder) isBreak() bool {
+	if d.off < len(d.data) && d.data[d.off] == breakCode {
+		d.off++
+		return true
+	}
+
+	return false
+}
+
+func (d *decoder) entry(m map[interface{}]interface{}, depth int) error {
+	k, err := d.decode(depth + 1)
+	if err != nil {
+		return err
+	}
+	switch k.(type) {
+	case int64, string:
+	default:
+		// Only the integer and the text keys are comparable and used by
+		// SenML.
+		return errMalformed
+	}
+
+	v, err := d.decode(depth + 1)
+	if err != nil {
+		return err
+	}
+	m[k] = v
+
+	return nil
+}
+
+func (d *decoder) simple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		b, err := d.bytes(2)
+		if err != nil {
+			return nil, err
+		}
+		return float16(binary.BigEndian.Uint16(b)), nil
+	case 26:
+		b, err := d.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 27:
+		b, err := d.bytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	default:
+		return nil, errMalformed
+	}
+}
+
+// arg returns the argument of the data item, following the initial byte.
+func (d *decoder) arg(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		b, err := d.bytes(1)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(b[0]), nil
+	case info == 25:
+		b, err := d.bytes(2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case info == 26:
+		b, err := d.bytes(4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	case info == 27:
+		b, err := d.bytes(8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(b), nil
+	default:
+		return 0, errMalformed
+	}
+}
+
+func (d *decoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.off) {
+		return nil, errMalformed
+	}
+	b := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+
+	return b, nil
+}
+
+// float16 converts the IEEE 754 half-precision float to float64.
+func float16(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	frac := float64(h & 0x3ff)
+
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	default:
+		return sign * math.Ldexp(frac+1024, exp-25)
+	}
+}
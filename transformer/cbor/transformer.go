@@ -0,0 +1,69 @@
+// Package cbor contains the transformer of the messages having the CBOR
+// (RFC 8949) payload.
+package cbor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/transformer"
+)
+
+// ContentType represents the CBOR content type.
+const ContentType = "application/cbor"
+
+var _ transformer.Transformer = (*cborTransformer)(nil)
+
+type cborTransformer struct {
+	json transformer.Transformer
+}
+
+// New returns the transformer of the CBOR messages. The payload is
+// converted to its JSON equivalent, see RFC 8949 section 6.1, and
+// transformed as the JSON message.
+func New() transformer.Transformer {
+	return cborTransformer{
+		json: transformer.New(),
+	}
+}
+
+func (ct cborTransformer) Transform(msg messaging.Message) (interface{}, error) {
+	v, err := Decode(msg.Payload)
+	if err != nil {
+		return nil, errors.Wrap(transformer.ErrTransform, err)
+	}
+
+	payload, err := json.Marshal(toJSON(v))
+	if err != nil {
+		return nil, errors.Wrap(transformer.ErrTransform, err)
+	}
+	msg.Payload = payload
+
+	return ct.json.Transform(msg)
+}
+
+// toJSON converts the decoded CBOR value to the one encoded as JSON. Byte
+// strings are base64url encoded, and the map keys are converted to strings.
+func toJSON(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		return base64.RawURLEncoding.EncodeToString(val)
+	case []interface{}:
+		arr := make([]interface{}, len(val))
+		for i, e := range val {
+			arr[i] = toJSON(e)
+		}
+		return arr
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[fmt.Sprint(k)] = toJSON(e)
+		}
+		return m
+	default:
+		return val
+	}
+}
@@ -0,0 +1,48 @@
+// Package raw contains the transformer of the messages whose payload is
+// stored as the opaque blob.
+package raw
+
+import (
+	"encoding/base64"
+
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/transformer"
+)
+
+const (
+	// ContentType represents the content type of the arbitrary binary data.
+	ContentType = "application/octet-stream"
+
+	// Format is the format of the transformed messages.
+	Format = "blobs"
+
+	// DataKey is the payload field holding the base64 encoded payload.
+	DataKey = "data"
+)
+
+var _ transformer.Transformer = (*rawTransformer)(nil)
+
+type rawTransformer struct{}
+
+// New returns the transformer keeping the message payload as is. The
+// payload is base64 encoded, so that it's stored by any writer, and the
+// message content type remains in the message headers.
+func New() transformer.Transformer {
+	return rawTransformer{}
+}
+
+func (rawTransformer) Transform(msg messaging.Message) (interface{}, error) {
+	m := transformer.Message{
+		Project:   msg.Project,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Headers:   msg.Headers,
+		Created:   msg.Created,
+		Payload: transformer.Payload{
+			DataKey: base64.StdEncoding.EncodeToString(msg.Payload),
+		},
+	}
+
+	return transformer.Messages{Data: []transformer.Message{m}, Format: Format}, nil
+}
@@ -0,0 +1,60 @@
+package transformer
+
+import (
+	"mime"
+
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/messaging"
+)
+
+// ContentType represents the JSON content type.
+const ContentType = "application/json"
+
+// ErrUnknownContentType indicates that there's no transformer of the message
+// content type.
+var ErrUnknownContentType = errors.New("unknown message content type")
+
+var _ Transformer = (*registry)(nil)
+
+type registry struct {
+	transformers map[string]Transformer
+	fallback     Transformer
+}
+
+// NewRegistry returns the transformer dispatching the messages to the
+// transformers keyed by the content type, taken from the content-type message
+// header. Messages without the header are transformed by the transformer
+// keyed by the empty content type. Messages having the content type with no
+// transformer are transformed by the fallback, or rejected if it's nil.
+func NewRegistry(transformers map[string]Transformer, fallback Transformer) Transformer {
+	return registry{
+		transformers: transformers,
+		fallback:     fallback,
+	}
+}
+
+func (r registry) Transform(msg messaging.Message) (interface{}, error) {
+	if t, ok := r.transformers[mediaType(msg.Header(messaging.ContentTypeHeader))]; ok {
+		return t.Transform(msg)
+	}
+	if r.fallback != nil {
+		return r.fallback.Transform(msg)
+	}
+
+	return nil, errors.Wrap(ErrTransform, ErrUnknownContentType)
+}
+
+// mediaType returns the media type of the content type, without the
+// parameters such as the charset.
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Malformed content type is unknown, unless registered as is.
+		return contentType
+	}
+
+	return mt
+}
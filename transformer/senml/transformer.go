@@ -0,0 +1,384 @@
+// Package senml contains the transformer of the messages having the SenML
+// (RFC 8428) payload, encoded as JSON or CBOR.
+package senml
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/transformer"
+	"github.com/vietquy/alpha/transformer/cbor"
+)
+
+const (
+	// JSON represents the SenML in JSON format content type.
+	JSON = "application/senml+json"
+
+	// CBOR represents the SenML in CBOR format content type.
+	CBOR = "application/senml+cbor"
+
+	// Format is the format of the transformed messages. SenML measurements
+	// are stored in the default format of the readers, whose value filters
+	// assume the SenML fields.
+	Format = "messages"
+
+	// relativeTime is the time, in seconds, below which the resolved time
+	// is relative to the current time.
+	relativeTime = 1 << 28
+)
+
+// Payload fields of the transformed messages.
+const (
+	NameKey        = "name"
+	UnitKey        = "unit"
+	ValueKey       = "value"
+	StringValueKey = "stringValue"
+	BoolValueKey   = "boolValue"
+	DataValueKey   = "dataValue"
+	SumKey         = "sum"
+	TimeKey        = "time"
+	UpdateTimeKey  = "updateTime"
+)
+
+var (
+	errUnknownContentType = errors.New("unknown SenML content type")
+	errInvalidRecord      = errors.New("invalid SenML record")
+	errInvalidName        = errors.New("invalid SenML name")
+	errNoValues           = errors.New("SenML record has no value nor sum")
+	errMultipleValues     = errors.New("SenML record has multiple values")
+)
+
+// labels maps the integer labels of the CBOR representation to the JSON
+// ones, see RFC 8428 section 6.
+var labels = map[int64]string{
+	-1: "bver",
+	-2: "bn",
+	-3: "bt",
+	-4: "bu",
+	-5: "bv",
+	-6: "bs",
+	0:  "n",
+	1:  "u",
+	2:  "v",
+	3:  "vs",
+	4:  "vb",
+	5:  "s",
+	6:  "t",
+	7:  "ut",
+	8:  "vd",
+}
+
+var _ transformer.Transformer = (*senmlTransformer)(nil)
+
+type senmlTransformer struct {
+	contentType string
+}
+
+// New returns the transformer of the SenML messages in the format of the
+// provided content type, i.e. JSON or CBOR. The message is transformed into
+// one message per SenML record, having the resolved name, unit, value, sum
+// and time of the record as the payload fields, and created at the record
+// time.
+func New(contentType string) transformer.Transformer {
+	return senmlTransformer{
+		contentType: contentType,
+	}
+}
+
+func (st senmlTransformer) Transform(msg messaging.Message) (interface{}, error) {
+	recs, err := decode(msg.Payload, st.contentType)
+	if err != nil {
+		return nil, errors.Wrap(transformer.ErrTransform, err)
+	}
+
+	msgs, err := resolve(recs, msg)
+	if err != nil {
+		return nil, errors.Wrap(transformer.ErrTransform, err)
+	}
+
+	return transformer.Messages{Data: msgs, Format: Format}, nil
+}
+
+// decode decodes the SenML pack into the records having the JSON labels.
+func decode(payload []byte, contentType string) ([]map[string]interface{}, error) {
+	switch contentType {
+	case JSON:
+		var recs []map[string]interface{}
+		if err := json.Unmarshal(payload, &recs); err != nil {
+			return nil, err
+		}
+		return recs, nil
+	case CBOR:
+		v, err := cbor.Decode(payload)
+		if err != nil {
+			return nil, err
+		}
+		pack, ok := v.([]interface{})
+		if !ok {
+			return nil, errInvalidRecord
+		}
+		recs := []map[string]interface{}{}
+		for _, p := range pack {
+			r, ok := p.(map[interface{}]interface{})
+			if !ok {
+				return nil, errInvalidRecord
+			}
+			rec, err := fromCBOR(r)
+			if err != nil {
+				return nil, err
+			}
+			recs = append(recs, rec)
+		}
+		return recs, nil
+	default:
+		return nil, errUnknownContentType
+	}
+}
+
+// fromCBOR converts the record of the CBOR representation to the one of the
+// JSON representation.
+func fromCBOR(r map[interface{}]interface{}) (map[string]interface{}, error) {
+	rec := map[string]interface{}{}
+	for k, v := range r {
+		label := ""
+		switch key := k.(type) {
+		case int64:
+			l, ok := labels[key]
+			if !ok {
+				// Unknown integer labels are the extensions that are
+				// ignored, as in JSON.
+				continue
+			}
+			label = l
+		case string:
+			label = key
+		}
+
+		switch val := v.(type) {
+		case int64:
+			rec[label] = float64(val)
+		case []byte:
+			// Data value is base64url encoded in JSON.
+			rec[label] = base64.RawURLEncoding.EncodeToString(val)
+		default:
+			rec[label] = val
+		}
+	}
+
+	return rec, nil
+}
+
+// base holds the base fields of the pack, which apply to all the following
+// records until redefined.
+type base struct {
+	name  string
+	time  float64
+	unit  string
+	value float64
+	sum   float64
+}
+
+// resolve resolves the records as described in RFC 8428 section 4.6, giving
+// the message per record.
+func resolve(recs []map[string]interface{}, msg messaging.Message) ([]transformer.Message, error) {
+	var b base
+	now := float64(time.Now().UnixNano()) / 1e9
+
+	ret := []transformer.Message{}
+	for _, rec := range recs {
+		if err := b.update(rec); err != nil {
+			return nil, err
+		}
+
+		n, err := str(rec, "n")
+		if err != nil {
+			return nil, err
+		}
+		name := b.name + n
+		if !validName(name) {
+			return nil, errors.Wrap(errInvalidName, errors.New(name))
+		}
+
+		payload := transformer.Payload{NameKey: name}
+
+		unit, err := str(rec, "u")
+		if err != nil {
+			return nil, err
+		}
+		if unit == "" {
+			unit = b.unit
+		}
+		if unit != "" {
+			payload[UnitKey] = unit
+		}
+
+		if err := values(rec, b, payload); err != nil {
+			return nil, err
+		}
+
+		t, err := num(rec, "t")
+		if err != nil {
+			return nil, err
+		}
+		t += b.time
+		if t < relativeTime {
+			t += now
+		}
+		payload[TimeKey] = t
+
+		if ut, ok := rec["ut"]; ok {
+			v, ok := ut.(float64)
+			if !ok {
+				return nil, errInvalidRecord
+			}
+			payload[UpdateTimeKey] = v
+		}
+
+		ret = append(ret, transformer.Message{
+			Project:   msg.Project,
+			Subtopic:  msg.Subtopic,
+			Publisher: msg.Publisher,
+			Protocol:  msg.Protocol,
+			Headers:   msg.Headers,
+			Created:   int64(t * 1e9),
+			Payload:   payload,
+		})
+	}
+
+	return ret, nil
+}
+
+// update updates the base fields defined by the record.
+func (b *base) update(rec map[string]interface{}) error {
+	var err error
+	if _, ok := rec["bn"]; ok {
+		if b.name, err = str(rec, "bn"); err != nil {
+			return err
+		}
+	}
+	if _, ok := rec["bt"]; ok {
+		if b.time, err = num(rec, "bt"); err != nil {
+			return err
+		}
+	}
+	if _, ok := rec["bu"]; ok {
+		if b.unit, err = str(rec, "bu"); err != nil {
+			return err
+		}
+	}
+	if _, ok := rec["bv"]; ok {
+		if b.value, err = num(rec, "bv"); err != nil {
+			return err
+		}
+	}
+	if _, ok := rec["bs"]; ok {
+		if b.sum, err = num(rec, "bs"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// values sets the value and the sum of the record to the payload. Record
+// must have exactly one of the values, or the sum.
+func values(rec map[string]interface{}, b base, payload transformer.Payload) error {
+	count := 0
+	if v, ok := rec["v"]; ok {
+		val, ok := v.(float64)
+		if !ok {
+			return errInvalidRecord
+		}
+		payload[ValueKey] = b.value + val
+		count++
+	}
+	if v, ok := rec["vs"]; ok {
+		val, ok := v.(string)
+		if !ok {
+			return errInvalidRecord
+		}
+		payload[StringValueKey] = val
+		count++
+	}
+	if v, ok := rec["vb"]; ok {
+		val, ok := v.(bool)
+		if !ok {
+			return errInvalidRecord
+		}
+		payload[BoolValueKey] = val
+		count++
+	}
+	if v, ok := rec["vd"]; ok {
+		val, ok := v.(string)
+		if !ok {
+			return errInvalidRecord
+		}
+		payload[DataValueKey] = val
+		count++
+	}
+	if count > 1 {
+		return errMultipleValues
+	}
+
+	if s, ok := rec["s"]; ok {
+		val, ok := s.(float64)
+		if !ok {
+			return errInvalidRecord
+		}
+		payload[SumKey] = b.sum + val
+		count++
+	}
+	if count == 0 {
+		return errNoValues
+	}
+
+	return nil
+}
+
+// validName checks that the name starts with a letter or a digit, and
+// contains only letters, digits and the -:./_ characters.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case i > 0 && (c == '-' || c == ':' || c == '.' || c == '/' || c == '_'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func str(rec map[string]interface{}, label string) (string, error) {
+	v, ok := rec[label]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", errors.Wrap(errInvalidRecord, errors.New(fmt.Sprintf("%s is not a string", label)))
+	}
+
+	return s, nil
+}
+
+func num(rec map[string]interface{}, label string) (float64, error) {
+	v, ok := rec[label]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return 0, errors.Wrap(errInvalidRecord, errors.New(fmt.Sprintf("%s is not a number", label)))
+	}
+
+	return n, nil
+}
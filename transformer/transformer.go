@@ -45,6 +45,7 @@ func funcTransformer(msg messaging.Message) (interface{}, error) {
 		Protocol:  msg.Protocol,
 		Project:   msg.Project,
 		Subtopic:  msg.Subtopic,
+		Headers:   msg.Headers,
 	}
 	subs := strings.Split(ret.Subtopic, ".")
 	if len(subs) == 0 {
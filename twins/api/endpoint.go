@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/vietquy/alpha/twins"
+)
+
+func addTwinEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addTwinReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		twin := twins.Twin{
+			Name:    req.Name,
+			ThingID: req.ThingID,
+		}
+
+		saved, err := svc.AddTwin(ctx, req.token, twin, req.Definition.definition())
+		if err != nil {
+			return nil, err
+		}
+
+		res := toTwinRes(saved)
+		res.created = true
+
+		return res, nil
+	}
+}
+
+func updateTwinEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateTwinReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		twin := twins.Twin{
+			ID:      req.id,
+			Name:    req.Name,
+			ThingID: req.ThingID,
+		}
+
+		var def *twins.Definition
+		if req.Definition != nil {
+			d := req.Definition.definition()
+			def = &d
+		}
+
+		if err := svc.UpdateTwin(ctx, req.token, twin, def); err != nil {
+			return nil, err
+		}
+
+		return updateRes{}, nil
+	}
+}
+
+func viewTwinEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewTwinReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		twin, err := svc.ViewTwin(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toTwinRes(twin), nil
+	}
+}
+
+func listTwinsEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListTwins(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := twinsPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Twins:  []twinRes{},
+		}
+		for _, twin := range page.Twins {
+			res.Twins = append(res.Twins, toTwinRes(twin))
+		}
+
+		return res, nil
+	}
+}
+
+func removeTwinEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewTwinReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveTwin(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func viewStateEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewTwinReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		state, err := svc.ViewState(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toStateRes(state), nil
+	}
+}
+
+func listStatesEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListStates(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := statesPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			States: []stateRes{},
+		}
+		for _, state := range page.States {
+			res.States = append(res.States, toStateRes(state))
+		}
+
+		return res, nil
+	}
+}
+
+func toTwinRes(twin twins.Twin) twinRes {
+	res := twinRes{
+		ID:          twin.ID,
+		Name:        twin.Name,
+		ThingID:     twin.ThingID,
+		Created:     twin.Created,
+		Updated:     twin.Updated,
+		Revision:    twin.Revision,
+		Definitions: []definitionRes{},
+	}
+	for _, d := range twin.Definitions {
+		def := definitionRes{
+			ID:         d.ID,
+			Created:    d.Created,
+			Attributes: []attributeRes{},
+		}
+		for _, attr := range d.Attributes {
+			def.Attributes = append(def.Attributes, attributeRes(attr))
+		}
+		res.Definitions = append(res.Definitions, def)
+	}
+
+	return res
+}
+
+func toStateRes(state twins.State) stateRes {
+	res := stateRes{
+		TwinID:     state.TwinID,
+		ID:         state.ID,
+		Definition: state.Definition,
+		Created:    state.Created,
+		Attributes: map[string]map[string]valueRes{},
+	}
+	for name, vals := range state.Attributes {
+		res.Attributes[name] = map[string]valueRes{}
+		for k, v := range vals {
+			res.Attributes[name][k] = valueRes(v)
+		}
+	}
+
+	return res
+}
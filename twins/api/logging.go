@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/twins"
+)
+
+var _ twins.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    twins.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc twins.Service, logger log.Logger) twins.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) AddTwin(ctx context.Context, token string, twin twins.Twin, def twins.Definition) (saved twins.Twin, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_twin for thing %s and twin %s took %s to complete", twin.ThingID, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddTwin(ctx, token, twin, def)
+}
+
+func (lm *loggingMiddleware) UpdateTwin(ctx context.Context, token string, twin twins.Twin, def *twins.Definition) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_twin for twin %s took %s to complete", twin.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateTwin(ctx, token, twin, def)
+}
+
+func (lm *loggingMiddleware) ViewTwin(ctx context.Context, token, id string) (_ twins.Twin, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_twin for twin %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewTwin(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListTwins(ctx context.Context, token string, offset, limit uint64) (_ twins.TwinsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_twins for offset %d and limit %d took %s to complete", offset, limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListTwins(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveTwin(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_twin for twin %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveTwin(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ViewState(ctx context.Context, token, id string) (_ twins.State, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_state for twin %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewState(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListStates(ctx context.Context, token, id string, offset, limit uint64) (_ twins.StatesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_states for twin %s, offset %d and limit %d took %s to complete", id, offset, limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListStates(ctx, token, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) SaveStates(ctx context.Context, msg messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_states for thing %s and project %s took %s to complete", msg.Publisher, msg.Project, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Debug(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveStates(ctx, msg)
+}
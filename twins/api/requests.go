@@ -0,0 +1,126 @@
+package api
+
+import "github.com/vietquy/alpha/twins"
+
+const (
+	maxLimitSize = 100
+	maxNameSize  = 1024
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type attributeReq struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Subtopic string `json:"subtopic,omitempty"`
+}
+
+type definitionReq struct {
+	Attributes []attributeReq `json:"attributes"`
+}
+
+func (req definitionReq) validate() error {
+	names := map[string]bool{}
+	for _, attr := range req.Attributes {
+		if attr.Name == "" || attr.Project == "" || len(attr.Name) > maxNameSize {
+			return twins.ErrMalformedEntity
+		}
+
+		if names[attr.Name] {
+			return twins.ErrMalformedEntity
+		}
+		names[attr.Name] = true
+	}
+
+	return nil
+}
+
+func (req definitionReq) definition() twins.Definition {
+	def := twins.Definition{Attributes: []twins.Attribute{}}
+	for _, attr := range req.Attributes {
+		def.Attributes = append(def.Attributes, twins.Attribute(attr))
+	}
+
+	return def
+}
+
+type addTwinReq struct {
+	token      string
+	Name       string        `json:"name,omitempty"`
+	ThingID    string        `json:"thing_id"`
+	Definition definitionReq `json:"definition"`
+}
+
+func (req addTwinReq) validate() error {
+	if req.token == "" {
+		return twins.ErrUnauthorizedAccess
+	}
+
+	if req.ThingID == "" || len(req.Name) > maxNameSize {
+		return twins.ErrMalformedEntity
+	}
+
+	return req.Definition.validate()
+}
+
+type updateTwinReq struct {
+	token      string
+	id         string
+	Name       string         `json:"name,omitempty"`
+	ThingID    string         `json:"thing_id,omitempty"`
+	Definition *definitionReq `json:"definition,omitempty"`
+}
+
+func (req updateTwinReq) validate() error {
+	if req.token == "" {
+		return twins.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || len(req.Name) > maxNameSize {
+		return twins.ErrMalformedEntity
+	}
+
+	if req.Definition != nil {
+		return req.Definition.validate()
+	}
+
+	return nil
+}
+
+type viewTwinReq struct {
+	token string
+	id    string
+}
+
+func (req viewTwinReq) validate() error {
+	if req.token == "" {
+		return twins.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return twins.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token  string
+	id     string
+	offset uint64
+	limit  uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return twins.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return twins.ErrMalformedEntity
+	}
+
+	return nil
+}
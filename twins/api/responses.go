@@ -0,0 +1,158 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vietquy/alpha"
+)
+
+var (
+	_ alpha.Response = (*twinRes)(nil)
+	_ alpha.Response = (*twinsPageRes)(nil)
+	_ alpha.Response = (*stateRes)(nil)
+	_ alpha.Response = (*statesPageRes)(nil)
+	_ alpha.Response = (*updateRes)(nil)
+	_ alpha.Response = (*removeRes)(nil)
+)
+
+type attributeRes struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Subtopic string `json:"subtopic,omitempty"`
+}
+
+type definitionRes struct {
+	ID         int            `json:"id"`
+	Created    time.Time      `json:"created"`
+	Attributes []attributeRes `json:"attributes"`
+}
+
+type twinRes struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name,omitempty"`
+	ThingID     string          `json:"thing_id"`
+	Created     time.Time       `json:"created"`
+	Updated     time.Time       `json:"updated"`
+	Revision    int             `json:"revision"`
+	Definitions []definitionRes `json:"definitions"`
+	created     bool
+}
+
+func (res twinRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res twinRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/twins/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res twinRes) Empty() bool {
+	return false
+}
+
+type twinsPageRes struct {
+	Total  uint64    `json:"total"`
+	Offset uint64    `json:"offset"`
+	Limit  uint64    `json:"limit"`
+	Twins  []twinRes `json:"twins"`
+}
+
+func (res twinsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res twinsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res twinsPageRes) Empty() bool {
+	return false
+}
+
+type valueRes struct {
+	Value   interface{} `json:"value"`
+	Updated time.Time   `json:"updated"`
+}
+
+type stateRes struct {
+	TwinID     string                         `json:"twin_id"`
+	ID         int64                          `json:"id"`
+	Definition int                            `json:"definition"`
+	Created    time.Time                      `json:"created"`
+	Attributes map[string]map[string]valueRes `json:"attributes"`
+}
+
+func (res stateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res stateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res stateRes) Empty() bool {
+	return false
+}
+
+type statesPageRes struct {
+	Total  uint64     `json:"total"`
+	Offset uint64     `json:"offset"`
+	Limit  uint64     `json:"limit"`
+	States []stateRes `json:"states"`
+}
+
+func (res statesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res statesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res statesPageRes) Empty() bool {
+	return false
+}
+
+type updateRes struct{}
+
+func (res updateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
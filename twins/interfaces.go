@@ -0,0 +1,17 @@
+package twins
+
+import "context"
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
+
+// ThingsService specifies an API for accessing the things of the user in the
+// things service, on behalf of the user identified by the provided token.
+type ThingsService interface {
+	// ViewThing checks that the thing having the provided ID exists and is
+	// accessible by the user.
+	ViewThing(ctx context.Context, token, id string) error
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host string
+	Port string
+	User string
+	Pass string
+	Name string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "twins_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS twins (
+						id          UUID PRIMARY KEY,
+						owner       VARCHAR(254) NOT NULL,
+						name        VARCHAR(1024),
+						thing_id    UUID NOT NULL,
+						created     TIMESTAMPTZ NOT NULL,
+						updated     TIMESTAMPTZ NOT NULL,
+						revision    INTEGER NOT NULL DEFAULT 0,
+						definitions JSONB NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS twins_owner_idx ON twins (owner)`,
+					`CREATE INDEX IF NOT EXISTS twins_thing_idx ON twins (thing_id)`,
+					`CREATE TABLE IF NOT EXISTS states (
+						twin_id    UUID NOT NULL REFERENCES twins (id) ON DELETE CASCADE,
+						id         BIGINT NOT NULL,
+						definition INTEGER NOT NULL,
+						created    TIMESTAMPTZ NOT NULL,
+						attributes JSONB NOT NULL,
+						PRIMARY KEY (twin_id, id)
+					)`,
+				},
+				Down: []string{"DROP TABLE states", "DROP TABLE twins"},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/twins"
+)
+
+var (
+	errSaveStateDB     = errors.New("Save state to DB failed")
+	errRetrieveStateDB = errors.New("Retrieving state from DB failed")
+)
+
+var _ twins.StateRepository = (*stateRepository)(nil)
+
+type stateRepository struct {
+	db *sqlx.DB
+}
+
+// NewStateRepository instantiates a PostgreSQL implementation of state
+// repository.
+func NewStateRepository(db *sqlx.DB) twins.StateRepository {
+	return &stateRepository{
+		db: db,
+	}
+}
+
+func (sr stateRepository) Save(ctx context.Context, state twins.State) error {
+	q := `INSERT INTO states (twin_id, id, definition, created, attributes)
+	      VALUES (:twin_id, :id, :definition, :created, :attributes)`
+
+	dbs, err := toDBState(state)
+	if err != nil {
+		return errors.Wrap(errSaveStateDB, err)
+	}
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(twins.ErrConflict, err)
+		}
+		return errors.Wrap(errSaveStateDB, err)
+	}
+
+	return nil
+}
+
+func (sr stateRepository) RetrieveLast(ctx context.Context, twinID string) (twins.State, error) {
+	q := `SELECT twin_id, id, definition, created, attributes FROM states
+	      WHERE twin_id = $1 ORDER BY id DESC LIMIT 1`
+
+	dbs := dbState{}
+	if err := sr.db.QueryRowxContext(ctx, q, twinID).StructScan(&dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return twins.State{}, errors.Wrap(twins.ErrNotFound, err)
+		}
+		return twins.State{}, errors.Wrap(errRetrieveStateDB, err)
+	}
+
+	return toState(dbs)
+}
+
+func (sr stateRepository) RetrieveAll(ctx context.Context, twinID string, offset, limit uint64) (twins.StatesPage, error) {
+	q := `SELECT twin_id, id, definition, created, attributes FROM states
+	      WHERE twin_id = $1 ORDER BY id DESC LIMIT $2 OFFSET $3`
+
+	rows, err := sr.db.QueryxContext(ctx, q, twinID, limit, offset)
+	if err != nil {
+		return twins.StatesPage{}, errors.Wrap(errRetrieveStateDB, err)
+	}
+	defer rows.Close()
+
+	states := []twins.State{}
+	for rows.Next() {
+		dbs := dbState{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return twins.StatesPage{}, errors.Wrap(errRetrieveStateDB, err)
+		}
+
+		state, err := toState(dbs)
+		if err != nil {
+			return twins.StatesPage{}, err
+		}
+		states = append(states, state)
+	}
+
+	var total uint64
+	if err := sr.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM states WHERE twin_id = $1`, twinID).Scan(&total); err != nil {
+		return twins.StatesPage{}, errors.Wrap(errRetrieveStateDB, err)
+	}
+
+	page := twins.StatesPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		States: states,
+	}
+
+	return page, nil
+}
+
+type dbState struct {
+	TwinID     string    `db:"twin_id"`
+	ID         int64     `db:"id"`
+	Definition int       `db:"definition"`
+	Created    time.Time `db:"created"`
+	Attributes []byte    `db:"attributes"`
+}
+
+type dbValue struct {
+	Value   interface{} `json:"value"`
+	Updated time.Time   `json:"updated"`
+}
+
+func toDBState(state twins.State) (dbState, error) {
+	attrs := map[string]map[string]dbValue{}
+	for name, vals := range state.Attributes {
+		attrs[name] = map[string]dbValue{}
+		for k, v := range vals {
+			attrs[name][k] = dbValue(v)
+		}
+	}
+
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		return dbState{}, err
+	}
+
+	dbs := dbState{
+		TwinID:     state.TwinID,
+		ID:         state.ID,
+		Definition: state.Definition,
+		Created:    state.Created,
+		Attributes: data,
+	}
+
+	return dbs, nil
+}
+
+func toState(dbs dbState) (twins.State, error) {
+	var attrs map[string]map[string]dbValue
+	if err := json.Unmarshal(dbs.Attributes, &attrs); err != nil {
+		return twins.State{}, errors.Wrap(errRetrieveStateDB, err)
+	}
+
+	state := twins.State{
+		TwinID:     dbs.TwinID,
+		ID:         dbs.ID,
+		Definition: dbs.Definition,
+		Created:    dbs.Created,
+		Attributes: map[string]map[string]twins.Value{},
+	}
+	for name, vals := range attrs {
+		state.Attributes[name] = map[string]twins.Value{}
+		for k, v := range vals {
+			state.Attributes[name][k] = twins.Value(v)
+		}
+	}
+
+	return state, nil
+}
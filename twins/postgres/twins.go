@@ -0,0 +1,279 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/twins"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var (
+	errSaveDB      = errors.New("Save twin to DB failed")
+	errRetrieveDB  = errors.New("Retrieving twin from DB failed")
+	errUpdateDB    = errors.New("Update twin in DB failed")
+	errRemoveDB    = errors.New("Remove twin from DB failed")
+	errMarshalJSON = errors.New("Marshal twin definitions failed")
+)
+
+var _ twins.TwinRepository = (*twinRepository)(nil)
+
+type twinRepository struct {
+	db *sqlx.DB
+}
+
+// NewTwinRepository instantiates a PostgreSQL implementation of twin
+// repository.
+func NewTwinRepository(db *sqlx.DB) twins.TwinRepository {
+	return &twinRepository{
+		db: db,
+	}
+}
+
+func (tr twinRepository) Save(ctx context.Context, twin twins.Twin) error {
+	q := `INSERT INTO twins (id, owner, name, thing_id, created, updated, revision, definitions)
+	      VALUES (:id, :owner, :name, :thing_id, :created, :updated, :revision, :definitions)`
+
+	dbt, err := toDBTwin(twin)
+	if err != nil {
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	if _, err := tr.db.NamedExecContext(ctx, q, dbt); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return errors.Wrap(twins.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(twins.ErrConflict, err)
+			}
+		}
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	return nil
+}
+
+func (tr twinRepository) Update(ctx context.Context, twin twins.Twin) error {
+	q := `UPDATE twins SET name = :name, thing_id = :thing_id, updated = :updated, revision = :revision,
+	      definitions = :definitions WHERE id = :id AND owner = :owner`
+
+	dbt, err := toDBTwin(twin)
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, dbt)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(twins.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	if cnt == 0 {
+		return twins.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr twinRepository) RetrieveByID(ctx context.Context, owner, id string) (twins.Twin, error) {
+	q := `SELECT id, owner, name, thing_id, created, updated, revision, definitions
+	      FROM twins WHERE id = $1 AND owner = $2`
+
+	dbt := dbTwin{}
+	if err := tr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbt); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return twins.Twin{}, errors.Wrap(twins.ErrNotFound, err)
+		}
+		return twins.Twin{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toTwin(dbt)
+}
+
+func (tr twinRepository) RetrieveByThing(ctx context.Context, thingID string) ([]twins.Twin, error) {
+	q := `SELECT id, owner, name, thing_id, created, updated, revision, definitions
+	      FROM twins WHERE thing_id = $1`
+
+	rows, err := tr.db.QueryxContext(ctx, q, thingID)
+	if err != nil {
+		// Messages published by the things having the invalid ID are not
+		// mapped to any twin.
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return []twins.Twin{}, nil
+		}
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	return scanTwins(rows)
+}
+
+func (tr twinRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (twins.TwinsPage, error) {
+	q := `SELECT id, owner, name, thing_id, created, updated, revision, definitions
+	      FROM twins WHERE owner = $1 ORDER BY created, id LIMIT $2 OFFSET $3`
+
+	rows, err := tr.db.QueryxContext(ctx, q, owner, limit, offset)
+	if err != nil {
+		return twins.TwinsPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	items, err := scanTwins(rows)
+	if err != nil {
+		return twins.TwinsPage{}, err
+	}
+
+	var total uint64
+	if err := tr.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM twins WHERE owner = $1`, owner).Scan(&total); err != nil {
+		return twins.TwinsPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	page := twins.TwinsPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Twins:  items,
+	}
+
+	return page, nil
+}
+
+func (tr twinRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM twins WHERE id = $1 AND owner = $2`
+
+	if _, err := tr.db.ExecContext(ctx, q, id, owner); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return errors.Wrap(errRemoveDB, err)
+	}
+
+	return nil
+}
+
+func scanTwins(rows *sqlx.Rows) ([]twins.Twin, error) {
+	items := []twins.Twin{}
+	for rows.Next() {
+		dbt := dbTwin{}
+		if err := rows.StructScan(&dbt); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+
+		twin, err := toTwin(dbt)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, twin)
+	}
+
+	return items, nil
+}
+
+type dbTwin struct {
+	ID          string         `db:"id"`
+	Owner       string         `db:"owner"`
+	Name        sql.NullString `db:"name"`
+	ThingID     string         `db:"thing_id"`
+	Created     time.Time      `db:"created"`
+	Updated     time.Time      `db:"updated"`
+	Revision    int            `db:"revision"`
+	Definitions []byte         `db:"definitions"`
+}
+
+type dbDefinition struct {
+	ID         int           `json:"id"`
+	Created    time.Time     `json:"created"`
+	Attributes []dbAttribute `json:"attributes"`
+}
+
+type dbAttribute struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Subtopic string `json:"subtopic,omitempty"`
+}
+
+func toDBTwin(twin twins.Twin) (dbTwin, error) {
+	defs := []dbDefinition{}
+	for _, d := range twin.Definitions {
+		def := dbDefinition{
+			ID:         d.ID,
+			Created:    d.Created,
+			Attributes: []dbAttribute{},
+		}
+		for _, a := range d.Attributes {
+			def.Attributes = append(def.Attributes, dbAttribute(a))
+		}
+		defs = append(defs, def)
+	}
+
+	data, err := json.Marshal(defs)
+	if err != nil {
+		return dbTwin{}, errors.Wrap(errMarshalJSON, err)
+	}
+
+	dbt := dbTwin{
+		ID:          twin.ID,
+		Owner:       twin.Owner,
+		Name:        sql.NullString{String: twin.Name, Valid: twin.Name != ""},
+		ThingID:     twin.ThingID,
+		Created:     twin.Created,
+		Updated:     twin.Updated,
+		Revision:    twin.Revision,
+		Definitions: data,
+	}
+
+	return dbt, nil
+}
+
+func toTwin(dbt dbTwin) (twins.Twin, error) {
+	var defs []dbDefinition
+	if err := json.Unmarshal(dbt.Definitions, &defs); err != nil {
+		return twins.Twin{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	twin := twins.Twin{
+		ID:          dbt.ID,
+		Owner:       dbt.Owner,
+		Name:        dbt.Name.String,
+		ThingID:     dbt.ThingID,
+		Created:     dbt.Created,
+		Updated:     dbt.Updated,
+		Revision:    dbt.Revision,
+		Definitions: []twins.Definition{},
+	}
+	for _, d := range defs {
+		def := twins.Definition{
+			ID:         d.ID,
+			Created:    d.Created,
+			Attributes: []twins.Attribute{},
+		}
+		for _, a := range d.Attributes {
+			def.Attributes = append(def.Attributes, twins.Attribute(a))
+		}
+		twin.Definitions = append(twin.Definitions, def)
+	}
+
+	return twin, nil
+}
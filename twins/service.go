@@ -0,0 +1,333 @@
+package twins
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/authn"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/transformer"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrConflict indicates that the entity already exists.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrThings indicates failed communication with the things service.
+	ErrThings = errors.New("things service request failed")
+
+	// ErrPayload indicates the message payload that can't be mapped to the
+	// twin state.
+	ErrPayload = errors.New("message payload is not a JSON object")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// AddTwin adds the twin of the thing, having the provided definition,
+	// to the user identified by the provided key.
+	AddTwin(ctx context.Context, token string, twin Twin, def Definition) (Twin, error)
+
+	// UpdateTwin updates the name and the thing of the twin, keeping the
+	// current ones if empty. If the definition is provided, it becomes the
+	// current definition of the twin.
+	UpdateTwin(ctx context.Context, token string, twin Twin, def *Definition) error
+
+	// ViewTwin retrieves the twin having the provided ID, that belongs to
+	// the user identified by the provided key.
+	ViewTwin(ctx context.Context, token, id string) (Twin, error)
+
+	// ListTwins retrieves the subset of twins that belong to the user
+	// identified by the provided key.
+	ListTwins(ctx context.Context, token string, offset, limit uint64) (TwinsPage, error)
+
+	// RemoveTwin removes the twin along with its states.
+	RemoveTwin(ctx context.Context, token, id string) error
+
+	// ViewState retrieves the latest known state of the twin.
+	ViewState(ctx context.Context, token, id string) (State, error)
+
+	// ListStates retrieves the subset of the state revisions of the twin,
+	// starting from the latest one.
+	ListStates(ctx context.Context, token, id string, offset, limit uint64) (StatesPage, error)
+
+	// SaveStates updates the states of the twins of the thing that published
+	// the message, if the message is mapped to any of their attributes.
+	SaveStates(ctx context.Context, msg messaging.Message) error
+}
+
+var _ Service = (*twinsService)(nil)
+
+type twinsService struct {
+	auth   alpha.AuthNServiceClient
+	twins  TwinRepository
+	states StateRepository
+	things ThingsService
+	idp    IdentityProvider
+}
+
+// New instantiates the twins service implementation.
+func New(auth alpha.AuthNServiceClient, twins TwinRepository, states StateRepository, things ThingsService, idp IdentityProvider) Service {
+	return &twinsService{
+		auth:   auth,
+		twins:  twins,
+		states: states,
+		things: things,
+		idp:    idp,
+	}
+}
+
+func (ts twinsService) AddTwin(ctx context.Context, token string, twin Twin, def Definition) (Twin, error) {
+	owner, err := ts.identify(ctx, token)
+	if err != nil {
+		return Twin{}, err
+	}
+
+	if err := ts.things.ViewThing(ctx, token, twin.ThingID); err != nil {
+		return Twin{}, err
+	}
+
+	twin.ID, err = ts.idp.ID()
+	if err != nil {
+		return Twin{}, err
+	}
+
+	now := time.Now().UTC()
+	def.ID = 0
+	def.Created = now
+
+	twin.Owner = owner
+	twin.Created = now
+	twin.Updated = now
+	twin.Revision = 0
+	twin.Definitions = []Definition{def}
+
+	if err := ts.twins.Save(ctx, twin); err != nil {
+		return Twin{}, err
+	}
+
+	return twin, nil
+}
+
+func (ts twinsService) UpdateTwin(ctx context.Context, token string, twin Twin, def *Definition) error {
+	owner, err := ts.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	current, err := ts.twins.RetrieveByID(ctx, owner, twin.ID)
+	if err != nil {
+		return err
+	}
+
+	if twin.Name != "" {
+		current.Name = twin.Name
+	}
+
+	if twin.ThingID != "" && twin.ThingID != current.ThingID {
+		if err := ts.things.ViewThing(ctx, token, twin.ThingID); err != nil {
+			return err
+		}
+		current.ThingID = twin.ThingID
+	}
+
+	now := time.Now().UTC()
+	if def != nil {
+		d := *def
+		d.ID = current.Definition().ID + 1
+		d.Created = now
+		current.Definitions = append(current.Definitions, d)
+	}
+
+	current.Updated = now
+	current.Revision++
+
+	return ts.twins.Update(ctx, current)
+}
+
+func (ts twinsService) ViewTwin(ctx context.Context, token, id string) (Twin, error) {
+	owner, err := ts.identify(ctx, token)
+	if err != nil {
+		return Twin{}, err
+	}
+
+	return ts.twins.RetrieveByID(ctx, owner, id)
+}
+
+func (ts twinsService) ListTwins(ctx context.Context, token string, offset, limit uint64) (TwinsPage, error) {
+	owner, err := ts.identify(ctx, token)
+	if err != nil {
+		return TwinsPage{}, err
+	}
+
+	return ts.twins.RetrieveAll(ctx, owner, offset, limit)
+}
+
+func (ts twinsService) RemoveTwin(ctx context.Context, token, id string) error {
+	owner, err := ts.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return ts.twins.Remove(ctx, owner, id)
+}
+
+func (ts twinsService) ViewState(ctx context.Context, token, id string) (State, error) {
+	twin, err := ts.ViewTwin(ctx, token, id)
+	if err != nil {
+		return State{}, err
+	}
+
+	return ts.states.RetrieveLast(ctx, twin.ID)
+}
+
+func (ts twinsService) ListStates(ctx context.Context, token, id string, offset, limit uint64) (StatesPage, error) {
+	twin, err := ts.ViewTwin(ctx, token, id)
+	if err != nil {
+		return StatesPage{}, err
+	}
+
+	return ts.states.RetrieveAll(ctx, twin.ID, offset, limit)
+}
+
+func (ts twinsService) SaveStates(ctx context.Context, msg messaging.Message) error {
+	tws, err := ts.twins.RetrieveByThing(ctx, msg.Publisher)
+	if err != nil {
+		return err
+	}
+
+	// The payload is parsed only if the message is mapped to any attribute,
+	// since the things may publish the messages that are not JSON objects.
+	var payloads []map[string]interface{}
+	for _, twin := range tws {
+		attrs := matching(twin.Definition(), msg)
+		if len(attrs) == 0 {
+			continue
+		}
+
+		if payloads == nil {
+			if payloads, err = flatten(msg.Payload); err != nil {
+				return err
+			}
+		}
+
+		if err := ts.saveState(ctx, twin, attrs, payloads, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ts twinsService) saveState(ctx context.Context, twin Twin, attrs []Attribute, payloads []map[string]interface{}, msg messaging.Message) error {
+	last, err := ts.states.RetrieveLast(ctx, twin.ID)
+	if err != nil && !errors.Contains(err, ErrNotFound) {
+		return err
+	}
+
+	def := twin.Definition()
+	state := State{
+		TwinID:     twin.ID,
+		ID:         last.ID + 1,
+		Definition: def.ID,
+		Created:    time.Now().UTC(),
+		Attributes: map[string]map[string]Value{},
+	}
+
+	// Values of the attributes removed from the definition are dropped.
+	for _, attr := range def.Attributes {
+		if vals, ok := last.Attributes[attr.Name]; ok {
+			state.Attributes[attr.Name] = vals
+		}
+	}
+
+	updated := time.Unix(0, msg.Created).UTC()
+	for _, attr := range attrs {
+		vals := map[string]Value{}
+		for k, v := range state.Attributes[attr.Name] {
+			vals[k] = v
+		}
+		for _, p := range payloads {
+			for k, v := range p {
+				vals[k] = Value{Value: v, Updated: updated}
+			}
+		}
+		state.Attributes[attr.Name] = vals
+	}
+
+	return ts.states.Save(ctx, state)
+}
+
+// identify returns the owner of the twins of the user identified by the
+// provided token, see authn.Owner.
+func (ts twinsService) identify(ctx context.Context, token string) (string, error) {
+	res, err := ts.auth.Identify(ctx, &alpha.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return authn.Owner(res.GetValue(), res.GetOrganization()), nil
+}
+
+// matching returns the attributes of the definition the message is mapped to.
+func matching(def Definition, msg messaging.Message) []Attribute {
+	attrs := []Attribute{}
+	for _, attr := range def.Attributes {
+		if attr.Project != msg.Project {
+			continue
+		}
+		if attr.Subtopic != "" && attr.Subtopic != msg.Subtopic {
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+
+	return attrs
+}
+
+// flatten parses the JSON payload, being either the object or the array of
+// objects, and flattens each of the objects.
+func flatten(payload []byte) ([]map[string]interface{}, error) {
+	var p interface{}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, errors.Wrap(ErrPayload, err)
+	}
+
+	var objs []interface{}
+	switch val := p.(type) {
+	case map[string]interface{}:
+		objs = []interface{}{val}
+	case []interface{}:
+		objs = val
+	default:
+		return nil, ErrPayload
+	}
+
+	payloads := []map[string]interface{}{}
+	for _, o := range objs {
+		obj, ok := o.(map[string]interface{})
+		if !ok {
+			return nil, ErrPayload
+		}
+		flat, err := transformer.Flatten(obj)
+		if err != nil {
+			return nil, errors.Wrap(ErrPayload, err)
+		}
+		payloads = append(payloads, flat)
+	}
+
+	return payloads, nil
+}
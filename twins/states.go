@@ -0,0 +1,47 @@
+package twins
+
+import (
+	"context"
+	"time"
+)
+
+// Value represents the last value of the flattened payload key, along with
+// the time it was published at.
+type Value struct {
+	Value   interface{}
+	Updated time.Time
+}
+
+// State represents the revision of the twin state. Each attribute holds the
+// last value of each flattened payload key of the messages mapped to it.
+type State struct {
+	TwinID     string
+	ID         int64
+	Definition int
+	Created    time.Time
+	Attributes map[string]map[string]Value
+}
+
+// StatesPage contains page related metadata as well as the list of states
+// that belong to this page.
+type StatesPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	States []State
+}
+
+// StateRepository specifies a state persistence API.
+type StateRepository interface {
+	// Save persists the state. ErrConflict is returned if the state having
+	// the same ID already exists.
+	Save(ctx context.Context, state State) error
+
+	// RetrieveLast retrieves the latest state of the twin. ErrNotFound is
+	// returned if the twin has no state.
+	RetrieveLast(ctx context.Context, twinID string) (State, error)
+
+	// RetrieveAll retrieves the subset of states of the twin, starting from
+	// the latest one.
+	RetrieveAll(ctx context.Context, twinID string, offset, limit uint64) (StatesPage, error)
+}
@@ -0,0 +1,80 @@
+package twins
+
+import (
+	"context"
+	"time"
+)
+
+// Attribute maps the messages published by the twin thing to the project
+// and the subtopic into the named attribute of the twin state. An empty
+// subtopic matches the messages published to any subtopic of the project.
+type Attribute struct {
+	Name     string
+	Project  string
+	Subtopic string
+}
+
+// Definition specifies the attributes of the twin state. Each update of the
+// definition creates the new definition, so that the states created using
+// the previous definitions remain meaningful.
+type Definition struct {
+	ID         int
+	Created    time.Time
+	Attributes []Attribute
+}
+
+// Twin represents the digital twin of the thing, holding the latest known
+// state of the thing built from the messages it publishes.
+type Twin struct {
+	ID          string
+	Owner       string
+	Name        string
+	ThingID     string
+	Created     time.Time
+	Updated     time.Time
+	Revision    int
+	Definitions []Definition
+}
+
+// Definition returns the current definition of the twin.
+func (t Twin) Definition() Definition {
+	if len(t.Definitions) == 0 {
+		return Definition{}
+	}
+
+	return t.Definitions[len(t.Definitions)-1]
+}
+
+// TwinsPage contains page related metadata as well as the list of twins
+// that belong to this page.
+type TwinsPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Twins  []Twin
+}
+
+// TwinRepository specifies a twin persistence API.
+type TwinRepository interface {
+	// Save persists the twin.
+	Save(ctx context.Context, twin Twin) error
+
+	// Update updates the name, the thing and the definitions of the twin,
+	// along with its revision.
+	Update(ctx context.Context, twin Twin) error
+
+	// RetrieveByID retrieves the twin having the provided identifier, that
+	// is owned by the specified user.
+	RetrieveByID(ctx context.Context, owner, id string) (Twin, error)
+
+	// RetrieveByThing retrieves all twins of the thing having the provided
+	// identifier.
+	RetrieveByThing(ctx context.Context, thingID string) ([]Twin, error)
+
+	// RetrieveAll retrieves the subset of twins owned by the specified user.
+	RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (TwinsPage, error)
+
+	// Remove removes the twin having the provided identifier, that is owned
+	// by the specified user, along with its states.
+	Remove(ctx context.Context, owner, id string) error
+}
@@ -91,3 +91,129 @@ func loginEndpoint(svc users.Service) endpoint.Endpoint {
 		return tokenRes{token}, nil
 	}
 }
+
+func createOrgEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createOrgReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		org, err := svc.CreateOrganization(ctx, req.token, users.Organization{Name: req.Name})
+		if err != nil {
+			return nil, err
+		}
+
+		return createOrgRes{ID: org.ID}, nil
+	}
+}
+
+func listOrgsEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserInfoReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		orgs, err := svc.ListOrganizations(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := orgsRes{Organizations: []orgRes{}}
+		for _, org := range orgs {
+			res.Organizations = append(res.Organizations, orgRes{
+				ID:    org.ID,
+				Name:  org.Name,
+				Owner: org.Owner,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func inviteMemberEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(inviteMemberReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		member := users.Member{
+			OrgID: req.id,
+			Email: req.Email,
+			Role:  req.Role,
+		}
+		if err := svc.InviteMember(ctx, req.token, member); err != nil {
+			return nil, err
+		}
+
+		return inviteMemberRes{}, nil
+	}
+}
+
+func listMembersEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewOrgReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		members, err := svc.ListMembers(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := membersRes{Members: []memberRes{}}
+		for _, m := range members {
+			res.Members = append(res.Members, memberRes{
+				Email: m.Email,
+				Role:  m.Role,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func removeMemberEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewOrgReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if req.member == "" {
+			return nil, users.ErrMalformedEntity
+		}
+
+		if err := svc.RemoveMember(ctx, req.token, req.id, req.member); err != nil {
+			return nil, err
+		}
+
+		return removeMemberRes{}, nil
+	}
+}
+
+func switchOrgEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewOrgReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		token, err := svc.SwitchOrganization(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return tokenRes{token}, nil
+	}
+}
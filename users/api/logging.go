@@ -85,4 +85,81 @@ func (lm *loggingMiddleware) ChangePassword(ctx context.Context, email, password
 	}(time.Now())
 
 	return lm.svc.ChangePassword(ctx, email, password, oldPassword)
-}
\ No newline at end of file
+}
+func (lm *loggingMiddleware) CreateOrganization(ctx context.Context, token string, org users.Organization) (saved users.Organization, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_organization for organization %s took %s to complete", saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateOrganization(ctx, token, org)
+}
+
+func (lm *loggingMiddleware) ListOrganizations(ctx context.Context, token string) (orgs []users.Organization, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_organizations took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListOrganizations(ctx, token)
+}
+
+func (lm *loggingMiddleware) InviteMember(ctx context.Context, token string, member users.Member) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method invite_member for user %s and organization %s took %s to complete", member.Email, member.OrgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.InviteMember(ctx, token, member)
+}
+
+func (lm *loggingMiddleware) ListMembers(ctx context.Context, token, orgID string) (members []users.Member, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_members for organization %s took %s to complete", orgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListMembers(ctx, token, orgID)
+}
+
+func (lm *loggingMiddleware) RemoveMember(ctx context.Context, token, orgID, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_member for user %s and organization %s took %s to complete", email, orgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveMember(ctx, token, orgID, email)
+}
+
+func (lm *loggingMiddleware) SwitchOrganization(ctx context.Context, token, orgID string) (t string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method switch_organization for organization %s took %s to complete", orgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SwitchOrganization(ctx, token, orgID)
+}
@@ -4,7 +4,10 @@ import (
 	"github.com/vietquy/alpha/users"
 )
 
-const minPassLen = 8
+const (
+	minPassLen  = 8
+	maxNameSize = 1024
+)
 
 type apiReq interface {
 	validate() error
@@ -90,3 +93,54 @@ func (req passwChangeReq) validate() error {
 	}
 	return nil
 }
+
+type createOrgReq struct {
+	token string
+	Name  string `json:"name"`
+}
+
+func (req createOrgReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.Name == "" || len(req.Name) > maxNameSize {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type viewOrgReq struct {
+	token  string
+	id     string
+	member string
+}
+
+func (req viewOrgReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.id == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type inviteMemberReq struct {
+	token string
+	id    string
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+func (req inviteMemberReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.id == "" || req.Email == "" {
+		return users.ErrMalformedEntity
+	}
+	if !users.ValidOrgRole(req.Role) {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
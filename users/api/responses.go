@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/vietquy/alpha"
@@ -10,6 +11,11 @@ var (
 	_ alpha.Response = (*tokenRes)(nil)
 	_ alpha.Response = (*identityRes)(nil)
 	_ alpha.Response = (*passwChangeRes)(nil)
+	_ alpha.Response = (*createOrgRes)(nil)
+	_ alpha.Response = (*orgsRes)(nil)
+	_ alpha.Response = (*inviteMemberRes)(nil)
+	_ alpha.Response = (*membersRes)(nil)
+	_ alpha.Response = (*removeMemberRes)(nil)
 )
 
 // MailSent message response when link is sent
@@ -81,3 +87,92 @@ func (res passwChangeRes) Headers() map[string]string {
 func (res passwChangeRes) Empty() bool {
 	return false
 }
+
+type createOrgRes struct {
+	ID string `json:"id"`
+}
+
+func (res createOrgRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createOrgRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/orgs/%s", res.ID),
+	}
+}
+
+func (res createOrgRes) Empty() bool {
+	return false
+}
+
+type orgRes struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+}
+
+type orgsRes struct {
+	Organizations []orgRes `json:"organizations"`
+}
+
+func (res orgsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res orgsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res orgsRes) Empty() bool {
+	return false
+}
+
+type inviteMemberRes struct{}
+
+func (res inviteMemberRes) Code() int {
+	return http.StatusOK
+}
+
+func (res inviteMemberRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res inviteMemberRes) Empty() bool {
+	return true
+}
+
+type memberRes struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type membersRes struct {
+	Members []memberRes `json:"members"`
+}
+
+func (res membersRes) Code() int {
+	return http.StatusOK
+}
+
+func (res membersRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res membersRes) Empty() bool {
+	return false
+}
+
+type removeMemberRes struct{}
+
+func (res removeMemberRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeMemberRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeMemberRes) Empty() bool {
+	return true
+}
@@ -74,6 +74,48 @@ func MakeHandler(svc users.Service, l log.Logger) http.Handler {
 		opts...,
 	))
 
+	mux.Post("/orgs", kithttp.NewServer(
+		createOrgEndpoint(svc),
+		decodeCreateOrg,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/orgs", kithttp.NewServer(
+		listOrgsEndpoint(svc),
+		decodeViewInfo,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/orgs/:id/members", kithttp.NewServer(
+		inviteMemberEndpoint(svc),
+		decodeInviteMember,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/orgs/:id/members", kithttp.NewServer(
+		listMembersEndpoint(svc),
+		decodeViewOrg,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/orgs/:id/members/:member", kithttp.NewServer(
+		removeMemberEndpoint(svc),
+		decodeViewOrg,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/orgs/:id/tokens", kithttp.NewServer(
+		switchOrgEndpoint(svc),
+		decodeViewOrg,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.GetFunc("/version", alpha.Version("users"))
 
 	return mux
@@ -96,6 +138,45 @@ func decodeUpdateUser(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeCreateOrg(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, ErrUnsupportedContentType
+	}
+
+	req := createOrgReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(users.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeInviteMember(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, ErrUnsupportedContentType
+	}
+
+	req := inviteMemberReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(users.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeViewOrg(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewOrgReq{
+		token:  r.Header.Get("Authorization"),
+		id:     bone.GetValue(r, "id"),
+		member: bone.GetValue(r, "member"),
+	}
+
+	return req, nil
+}
+
 func decodeCredentials(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, ErrUnsupportedContentType
@@ -202,6 +283,8 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 			w.WriteHeader(http.StatusBadRequest)
 		case errors.Contains(errorVal, users.ErrUserNotFound):
 			w.WriteHeader(http.StatusBadRequest)
+		case errors.Contains(errorVal, users.ErrNotFound):
+			w.WriteHeader(http.StatusNotFound)
 		case errors.Contains(errorVal, users.ErrRecoveryToken):
 			w.WriteHeader(http.StatusInternalServerError)
 		default:
@@ -36,6 +36,12 @@ type Hasher interface {
 	Compare(string, string) error
 }
 
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
+
 // User represents a user account. Each user is identified given its
 // email and password.
 type User struct {
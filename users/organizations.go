@@ -0,0 +1,77 @@
+package users
+
+import "context"
+
+const (
+	// OrgMember allows the user to act on behalf of the organization.
+	OrgMember = "member"
+
+	// OrgAdmin allows the user to invite and remove organization members,
+	// in addition to acting on behalf of the organization.
+	OrgAdmin = "admin"
+
+	// OrgOwner is the role of the user who created the organization.
+	// The owner can't be removed from the organization.
+	OrgOwner = "owner"
+)
+
+var orgRoles = map[string]int{
+	OrgMember: 1,
+	OrgAdmin:  2,
+	OrgOwner:  3,
+}
+
+// Organization represents a group of users owning the resources together.
+// Each user has the personal organization created on registration.
+type Organization struct {
+	ID    string
+	Name  string
+	Owner string
+}
+
+// Member represents the membership of the user in the organization.
+type Member struct {
+	OrgID string
+	Email string
+	Role  string
+}
+
+// Allows returns true if the member role grants the permissions of the
+// given role.
+func (m Member) Allows(role string) bool {
+	r, ok := orgRoles[m.Role]
+	return ok && r >= orgRoles[role]
+}
+
+// ValidOrgRole returns true if the role can be assigned to the invited
+// member. There is exactly one owner of the organization, so the owner role
+// can't be assigned.
+func ValidOrgRole(role string) bool {
+	return role == OrgMember || role == OrgAdmin
+}
+
+// OrganizationRepository specifies an organization persistence API.
+type OrganizationRepository interface {
+	// Save persists the organization along with its owner membership.
+	Save(ctx context.Context, org Organization) error
+
+	// RetrieveByID retrieves the organization by its unique identifier.
+	RetrieveByID(ctx context.Context, id string) (Organization, error)
+
+	// RetrieveByMember retrieves all the organizations the user is member of.
+	RetrieveByMember(ctx context.Context, email string) ([]Organization, error)
+
+	// SaveMember persists the organization membership. Saving the existing
+	// member again replaces the member role.
+	SaveMember(ctx context.Context, member Member) error
+
+	// RetrieveMember retrieves the membership of the user in the
+	// organization.
+	RetrieveMember(ctx context.Context, orgID, email string) (Member, error)
+
+	// RetrieveMembers retrieves all the members of the organization.
+	RetrieveMembers(ctx context.Context, orgID string) ([]Member, error)
+
+	// RemoveMember removes the user from the organization.
+	RemoveMember(ctx context.Context, orgID, email string) error
+}
@@ -54,6 +54,35 @@ func migrateDB(db *sqlx.DB) error {
 					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS metadata JSONB`,
 				},
 			},
+			{
+				Id: "users_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS organizations (
+						id    UUID PRIMARY KEY,
+						name  VARCHAR(1024),
+						owner VARCHAR(254) NOT NULL REFERENCES users (email) ON DELETE CASCADE
+					)`,
+					`CREATE TABLE IF NOT EXISTS org_members (
+						org_id UUID REFERENCES organizations (id) ON DELETE CASCADE,
+						member VARCHAR(254) REFERENCES users (email) ON DELETE CASCADE,
+						role   VARCHAR(32) NOT NULL,
+						PRIMARY KEY (org_id, member)
+					)`,
+					`CREATE INDEX IF NOT EXISTS org_members_member_idx ON org_members (member)`,
+					// Existing users get the personal organization, identified
+					// the same way as authn.PersonalOrganization does.
+					`INSERT INTO organizations (id, name, owner)
+					 SELECT md5(email)::uuid, email, email FROM users
+					 ON CONFLICT (id) DO NOTHING`,
+					`INSERT INTO org_members (org_id, member, role)
+					 SELECT md5(email)::uuid, email, 'owner' FROM users
+					 ON CONFLICT (org_id, member) DO NOTHING`,
+				},
+				Down: []string{
+					"DROP TABLE org_members",
+					"DROP TABLE organizations",
+				},
+			},
 		},
 	}
 
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/users"
+)
+
+const errInvalid = "invalid_text_representation"
+
+var (
+	errSaveOrgDB      = errors.New("Save organization to DB failed")
+	errSaveMemberDB   = errors.New("Save organization member to DB failed")
+	errRemoveMemberDB = errors.New("Remove organization member from DB failed")
+)
+
+var _ users.OrganizationRepository = (*orgRepository)(nil)
+
+type orgRepository struct {
+	db *sqlx.DB
+}
+
+// NewOrganizationRepository instantiates a PostgreSQL implementation of
+// organization repository.
+func NewOrganizationRepository(db *sqlx.DB) users.OrganizationRepository {
+	return &orgRepository{
+		db: db,
+	}
+}
+
+func (or orgRepository) Save(ctx context.Context, org users.Organization) error {
+	tx, err := or.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errSaveOrgDB, err)
+	}
+
+	q := `INSERT INTO organizations (id, name, owner) VALUES (:id, :name, :owner)`
+	if _, err := tx.NamedExecContext(ctx, q, toDBOrg(org)); err != nil {
+		tx.Rollback()
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(users.ErrConflict, err)
+		}
+		return errors.Wrap(errSaveOrgDB, err)
+	}
+
+	owner := users.Member{
+		OrgID: org.ID,
+		Email: org.Owner,
+		Role:  users.OrgOwner,
+	}
+	q = `INSERT INTO org_members (org_id, member, role) VALUES (:org_id, :member, :role)`
+	if _, err := tx.NamedExecContext(ctx, q, toDBMember(owner)); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errSaveOrgDB, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errSaveOrgDB, err)
+	}
+
+	return nil
+}
+
+func (or orgRepository) RetrieveByID(ctx context.Context, id string) (users.Organization, error) {
+	q := `SELECT id, name, owner FROM organizations WHERE id = $1`
+
+	dbo := dbOrg{}
+	if err := or.db.QueryRowxContext(ctx, q, id).StructScan(&dbo); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return users.Organization{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.Organization{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toOrg(dbo), nil
+}
+
+func (or orgRepository) RetrieveByMember(ctx context.Context, email string) ([]users.Organization, error) {
+	q := `SELECT o.id, o.name, o.owner FROM organizations o
+	      INNER JOIN org_members m ON m.org_id = o.id
+	      WHERE m.member = $1 ORDER BY o.name, o.id`
+
+	rows, err := or.db.QueryxContext(ctx, q, email)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	orgs := []users.Organization{}
+	for rows.Next() {
+		dbo := dbOrg{}
+		if err := rows.StructScan(&dbo); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+		orgs = append(orgs, toOrg(dbo))
+	}
+
+	return orgs, nil
+}
+
+func (or orgRepository) SaveMember(ctx context.Context, member users.Member) error {
+	q := `INSERT INTO org_members (org_id, member, role) VALUES (:org_id, :member, :role)
+	      ON CONFLICT (org_id, member) DO UPDATE SET role = :role`
+
+	if _, err := or.db.NamedExecContext(ctx, q, toDBMember(member)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(users.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errSaveMemberDB, err)
+	}
+
+	return nil
+}
+
+func (or orgRepository) RetrieveMember(ctx context.Context, orgID, email string) (users.Member, error) {
+	q := `SELECT org_id, member, role FROM org_members WHERE org_id = $1 AND member = $2`
+
+	dbm := dbMember{}
+	if err := or.db.QueryRowxContext(ctx, q, orgID, email).StructScan(&dbm); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return users.Member{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.Member{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toMember(dbm), nil
+}
+
+func (or orgRepository) RetrieveMembers(ctx context.Context, orgID string) ([]users.Member, error) {
+	q := `SELECT org_id, member, role FROM org_members WHERE org_id = $1 ORDER BY member`
+
+	rows, err := or.db.QueryxContext(ctx, q, orgID)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	members := []users.Member{}
+	for rows.Next() {
+		dbm := dbMember{}
+		if err := rows.StructScan(&dbm); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+		members = append(members, toMember(dbm))
+	}
+
+	return members, nil
+}
+
+func (or orgRepository) RemoveMember(ctx context.Context, orgID, email string) error {
+	q := `DELETE FROM org_members WHERE org_id = $1 AND member = $2`
+
+	res, err := or.db.ExecContext(ctx, q, orgID, email)
+	if err != nil {
+		return errors.Wrap(errRemoveMemberDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errRemoveMemberDB, err)
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbOrg struct {
+	ID    string `db:"id"`
+	Name  string `db:"name"`
+	Owner string `db:"owner"`
+}
+
+func toDBOrg(org users.Organization) dbOrg {
+	return dbOrg{
+		ID:    org.ID,
+		Name:  org.Name,
+		Owner: org.Owner,
+	}
+}
+
+func toOrg(dbo dbOrg) users.Organization {
+	return users.Organization{
+		ID:    dbo.ID,
+		Name:  dbo.Name,
+		Owner: dbo.Owner,
+	}
+}
+
+type dbMember struct {
+	OrgID  string `db:"org_id"`
+	Member string `db:"member"`
+	Role   string `db:"role"`
+}
+
+func toDBMember(m users.Member) dbMember {
+	return dbMember{
+		OrgID:  m.OrgID,
+		Member: m.Email,
+		Role:   m.Role,
+	}
+}
+
+func toMember(dbm dbMember) users.Member {
+	return users.Member{
+		OrgID: dbm.OrgID,
+		Email: dbm.Member,
+		Role:  dbm.Role,
+	}
+}
@@ -42,6 +42,9 @@ var (
 
 	// ErrGetToken indicates error in getting signed token.
 	ErrGetToken = errors.New("Get signed token failed")
+
+	// ErrCreateOrganization indicates error in creating organization.
+	ErrCreateOrganization = errors.New("create organization failed")
 )
 
 // Service specifies an API that must be fullfiled by the domain service
@@ -64,21 +67,50 @@ type Service interface {
 
 	// ChangePassword change users password for authenticated user.
 	ChangePassword(ctx context.Context, authToken, password, oldPassword string) error
+
+	// CreateOrganization creates new organization owned by the authenticated
+	// user.
+	CreateOrganization(ctx context.Context, token string, org Organization) (Organization, error)
+
+	// ListOrganizations retrieves all the organizations the authenticated
+	// user is member of.
+	ListOrganizations(ctx context.Context, token string) ([]Organization, error)
+
+	// InviteMember adds the existing user to the organization with the given
+	// role. Only the organization owner and admins can invite members.
+	InviteMember(ctx context.Context, token string, member Member) error
+
+	// ListMembers retrieves all the members of the organization the
+	// authenticated user is member of.
+	ListMembers(ctx context.Context, token, orgID string) ([]Member, error)
+
+	// RemoveMember removes the user from the organization. Only the
+	// organization owner and admins can remove other members, while any
+	// member can leave the organization.
+	RemoveMember(ctx context.Context, token, orgID, email string) error
+
+	// SwitchOrganization issues new access token acting on behalf of the
+	// organization the authenticated user is member of.
+	SwitchOrganization(ctx context.Context, token, orgID string) (string, error)
 }
 
 var _ Service = (*usersService)(nil)
 
 type usersService struct {
 	users  UserRepository
+	orgs   OrganizationRepository
 	hasher Hasher
+	idp    IdentityProvider
 	auth   alpha.AuthNServiceClient
 }
 
 // New instantiates the users service implementation
-func New(users UserRepository, hasher Hasher, auth alpha.AuthNServiceClient) Service {
+func New(users UserRepository, orgs OrganizationRepository, hasher Hasher, idp IdentityProvider, auth alpha.AuthNServiceClient) Service {
 	return &usersService{
 		users:  users,
+		orgs:   orgs,
 		hasher: hasher,
+		idp:    idp,
 		auth:   auth,
 	}
 }
@@ -90,7 +122,16 @@ func (svc usersService) Register(ctx context.Context, user User) error {
 	}
 
 	user.Password = hash
-	return svc.users.Save(ctx, user)
+	if err := svc.users.Save(ctx, user); err != nil {
+		return err
+	}
+
+	org := Organization{
+		ID:    authn.PersonalOrganization(user.Email),
+		Name:  user.Email,
+		Owner: user.Email,
+	}
+	return svc.orgs.Save(ctx, org)
 }
 
 func (svc usersService) Login(ctx context.Context, user User) (string, error) {
@@ -103,7 +144,7 @@ func (svc usersService) Login(ctx context.Context, user User) (string, error) {
 		return "", errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	return svc.issue(ctx, dbUser.Email, authn.UserKey)
+	return svc.issue(ctx, dbUser.Email, authn.PersonalOrganization(dbUser.Email), authn.UserKey)
 }
 
 func (svc usersService) UserInfo(ctx context.Context, token string) (User, error) {
@@ -165,6 +206,120 @@ func (svc usersService) ChangePassword(ctx context.Context, authToken, password,
 	return svc.users.UpdatePassword(ctx, email, password)
 }
 
+func (svc usersService) CreateOrganization(ctx context.Context, token string, org Organization) (Organization, error) {
+	email, err := svc.identify(ctx, token)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	org.ID, err = svc.idp.ID()
+	if err != nil {
+		return Organization{}, errors.Wrap(ErrCreateOrganization, err)
+	}
+	org.Owner = email
+
+	if err := svc.orgs.Save(ctx, org); err != nil {
+		return Organization{}, err
+	}
+
+	return org, nil
+}
+
+func (svc usersService) ListOrganizations(ctx context.Context, token string) ([]Organization, error) {
+	email, err := svc.identify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.orgs.RetrieveByMember(ctx, email)
+}
+
+func (svc usersService) InviteMember(ctx context.Context, token string, member Member) error {
+	if _, err := svc.member(ctx, token, member.OrgID, OrgAdmin); err != nil {
+		return err
+	}
+
+	if !ValidOrgRole(member.Role) {
+		return ErrMalformedEntity
+	}
+
+	if _, err := svc.users.RetrieveByID(ctx, member.Email); err != nil {
+		return errors.Wrap(ErrUserNotFound, err)
+	}
+
+	// The owner role can't be changed by re-inviting the owner.
+	invited, err := svc.orgs.RetrieveMember(ctx, member.OrgID, member.Email)
+	switch {
+	case err == nil && invited.Role == OrgOwner:
+		return ErrMalformedEntity
+	case err != nil && !errors.Contains(err, ErrNotFound):
+		return err
+	}
+
+	return svc.orgs.SaveMember(ctx, member)
+}
+
+func (svc usersService) ListMembers(ctx context.Context, token, orgID string) ([]Member, error) {
+	if _, err := svc.member(ctx, token, orgID, OrgMember); err != nil {
+		return nil, err
+	}
+
+	return svc.orgs.RetrieveMembers(ctx, orgID)
+}
+
+func (svc usersService) RemoveMember(ctx context.Context, token, orgID, email string) error {
+	m, err := svc.member(ctx, token, orgID, OrgMember)
+	if err != nil {
+		return err
+	}
+
+	if m.Email != email && !m.Allows(OrgAdmin) {
+		return ErrUnauthorizedAccess
+	}
+
+	removed, err := svc.orgs.RetrieveMember(ctx, orgID, email)
+	if err != nil {
+		return err
+	}
+
+	if removed.Role == OrgOwner {
+		return ErrMalformedEntity
+	}
+
+	return svc.orgs.RemoveMember(ctx, orgID, email)
+}
+
+func (svc usersService) SwitchOrganization(ctx context.Context, token, orgID string) (string, error) {
+	m, err := svc.member(ctx, token, orgID, OrgMember)
+	if err != nil {
+		return "", err
+	}
+
+	return svc.issue(ctx, m.Email, m.OrgID, authn.UserKey)
+}
+
+// member returns the membership of the authenticated user in the
+// organization, if the user has at least the given role.
+func (svc usersService) member(ctx context.Context, token, orgID, role string) (Member, error) {
+	email, err := svc.identify(ctx, token)
+	if err != nil {
+		return Member{}, err
+	}
+
+	m, err := svc.orgs.RetrieveMember(ctx, orgID, email)
+	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return Member{}, ErrUnauthorizedAccess
+		}
+		return Member{}, err
+	}
+
+	if !m.Allows(role) {
+		return Member{}, ErrUnauthorizedAccess
+	}
+
+	return m, nil
+}
 
 func (svc usersService) identify(ctx context.Context, token string) (string, error) {
 	email, err := svc.auth.Identify(ctx, &alpha.Token{Value: token})
@@ -174,8 +329,8 @@ func (svc usersService) identify(ctx context.Context, token string) (string, err
 	return email.GetValue(), nil
 }
 
-func (svc usersService) issue(ctx context.Context, email string, keyType uint32) (string, error) {
-	key, err := svc.auth.Issue(ctx, &alpha.IssueReq{Issuer: email, Type: keyType})
+func (svc usersService) issue(ctx context.Context, email, org string, keyType uint32) (string, error) {
+	key, err := svc.auth.Issue(ctx, &alpha.IssueReq{Issuer: email, Type: keyType, Organization: org})
 	if err != nil {
 		return "", errors.Wrap(ErrUserNotFound, err)
 	}
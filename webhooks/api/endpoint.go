@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/vietquy/alpha/webhooks"
+)
+
+func addWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(webhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		saved, err := svc.AddWebhook(ctx, req.token, req.webhook())
+		if err != nil {
+			return nil, err
+		}
+
+		res := toWebhookRes(saved)
+		res.created = true
+
+		return res, nil
+	}
+}
+
+func updateWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(webhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if req.id == "" {
+			return nil, webhooks.ErrMalformedEntity
+		}
+
+		if err := svc.UpdateWebhook(ctx, req.token, req.webhook()); err != nil {
+			return nil, err
+		}
+
+		return updateRes{}, nil
+	}
+}
+
+func viewWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewWebhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		hook, err := svc.ViewWebhook(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toWebhookRes(hook), nil
+	}
+}
+
+func listWebhooksEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListWebhooks(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := webhooksPageRes{
+			Total:    page.Total,
+			Offset:   page.Offset,
+			Limit:    page.Limit,
+			Webhooks: []webhookRes{},
+		}
+		for _, hook := range page.Webhooks {
+			res.Webhooks = append(res.Webhooks, toWebhookRes(hook))
+		}
+
+		return res, nil
+	}
+}
+
+func removeWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewWebhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveWebhook(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listDeadLettersEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeadLetters(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := deadLettersPageRes{
+			Total:       page.Total,
+			Offset:      page.Offset,
+			Limit:       page.Limit,
+			DeadLetters: []deadLetterRes{},
+		}
+		for _, dl := range page.DeadLetters {
+			res.DeadLetters = append(res.DeadLetters, toDeadLetterRes(dl))
+		}
+
+		return res, nil
+	}
+}
+
+func toWebhookRes(hook webhooks.Webhook) webhookRes {
+	return webhookRes{
+		ID:          hook.ID,
+		Name:        hook.Name,
+		Project:     hook.Project,
+		Subtopic:    hook.Subtopic,
+		URL:         hook.URL,
+		Format:      hook.Format,
+		HTTPHeaders: hook.Headers,
+		Signed:      hook.Secret != "",
+		Created:     hook.Created,
+	}
+}
+
+// toDeadLetterRes embeds the payload that is a valid JSON as is, while the
+// other payloads are encoded as base64 strings.
+func toDeadLetterRes(dl webhooks.DeadLetter) deadLetterRes {
+	msg := messageRes{
+		Project:   dl.Message.Project,
+		Subtopic:  dl.Message.Subtopic,
+		Publisher: dl.Message.Publisher,
+		Protocol:  dl.Message.Protocol,
+		Created:   dl.Message.Created,
+		Payload:   dl.Message.Payload,
+	}
+	if json.Valid(dl.Message.Payload) {
+		msg.Payload = json.RawMessage(dl.Message.Payload)
+	}
+
+	return deadLetterRes{
+		ID:       dl.ID,
+		Message:  msg,
+		Error:    dl.Error,
+		Attempts: dl.Attempts,
+		Failed:   dl.Failed,
+	}
+}
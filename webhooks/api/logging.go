@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/webhooks"
+)
+
+var _ webhooks.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    webhooks.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc webhooks.Service, logger log.Logger) webhooks.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) AddWebhook(ctx context.Context, token string, hook webhooks.Webhook) (saved webhooks.Webhook, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_webhook for project %s and webhook %s took %s to complete", hook.Project, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddWebhook(ctx, token, hook)
+}
+
+func (lm *loggingMiddleware) UpdateWebhook(ctx context.Context, token string, hook webhooks.Webhook) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_webhook for webhook %s took %s to complete", hook.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateWebhook(ctx, token, hook)
+}
+
+func (lm *loggingMiddleware) ViewWebhook(ctx context.Context, token, id string) (_ webhooks.Webhook, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_webhook for webhook %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewWebhook(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListWebhooks(ctx context.Context, token string, offset, limit uint64) (_ webhooks.WebhooksPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_webhooks for offset %d and limit %d took %s to complete", offset, limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListWebhooks(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveWebhook(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_webhook for webhook %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveWebhook(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListDeadLetters(ctx context.Context, token, id string, offset, limit uint64) (_ webhooks.DeadLettersPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_dead_letters for webhook %s, offset %d and limit %d took %s to complete", id, offset, limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDeadLetters(ctx, token, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) Deliver(ctx context.Context, msg messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method deliver for thing %s and project %s took %s to complete", msg.Publisher, msg.Project, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Debug(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Deliver(ctx, msg)
+}
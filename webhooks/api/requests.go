@@ -0,0 +1,103 @@
+package api
+
+import (
+	"github.com/vietquy/alpha/webhooks"
+)
+
+const (
+	maxLimitSize = 100
+	maxNameSize  = 1024
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type webhookReq struct {
+	token    string
+	id       string
+	Name     string            `json:"name,omitempty"`
+	Project  string            `json:"project"`
+	Subtopic string            `json:"subtopic,omitempty"`
+	URL      string            `json:"url"`
+	Secret   string            `json:"secret,omitempty"`
+	Format   string            `json:"format,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+func (req webhookReq) validate() error {
+	if req.token == "" {
+		return webhooks.ErrUnauthorizedAccess
+	}
+
+	if req.Project == "" || len(req.Name) > maxNameSize {
+		return webhooks.ErrMalformedEntity
+	}
+
+	if !webhooks.ValidURL(req.URL) {
+		return webhooks.ErrMalformedEntity
+	}
+
+	switch req.Format {
+	case "", webhooks.RawFormat, webhooks.JSONFormat:
+	default:
+		return webhooks.ErrMalformedEntity
+	}
+
+	for k := range req.Headers {
+		if k == "" {
+			return webhooks.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+func (req webhookReq) webhook() webhooks.Webhook {
+	return webhooks.Webhook{
+		ID:       req.id,
+		Name:     req.Name,
+		Project:  req.Project,
+		Subtopic: req.Subtopic,
+		URL:      req.URL,
+		Secret:   req.Secret,
+		Format:   req.Format,
+		Headers:  req.Headers,
+	}
+}
+
+type viewWebhookReq struct {
+	token string
+	id    string
+}
+
+func (req viewWebhookReq) validate() error {
+	if req.token == "" {
+		return webhooks.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return webhooks.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token  string
+	id     string
+	offset uint64
+	limit  uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return webhooks.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return webhooks.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vietquy/alpha"
+)
+
+var (
+	_ alpha.Response = (*webhookRes)(nil)
+	_ alpha.Response = (*webhooksPageRes)(nil)
+	_ alpha.Response = (*deadLettersPageRes)(nil)
+	_ alpha.Response = (*updateRes)(nil)
+	_ alpha.Response = (*removeRes)(nil)
+)
+
+// webhookRes omits the webhook secret, which can only be set.
+type webhookRes struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name,omitempty"`
+	Project     string            `json:"project"`
+	Subtopic    string            `json:"subtopic,omitempty"`
+	URL         string            `json:"url"`
+	Format      string            `json:"format"`
+	HTTPHeaders map[string]string `json:"headers,omitempty"`
+	Signed      bool              `json:"signed"`
+	Created     time.Time         `json:"created"`
+	created     bool
+}
+
+func (res webhookRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res webhookRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/webhooks/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res webhookRes) Empty() bool {
+	return false
+}
+
+type webhooksPageRes struct {
+	Total    uint64       `json:"total"`
+	Offset   uint64       `json:"offset"`
+	Limit    uint64       `json:"limit"`
+	Webhooks []webhookRes `json:"webhooks"`
+}
+
+func (res webhooksPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res webhooksPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res webhooksPageRes) Empty() bool {
+	return false
+}
+
+type messageRes struct {
+	Project   string      `json:"project"`
+	Subtopic  string      `json:"subtopic,omitempty"`
+	Publisher string      `json:"publisher"`
+	Protocol  string      `json:"protocol"`
+	Created   int64       `json:"created"`
+	Payload   interface{} `json:"payload"`
+}
+
+type deadLetterRes struct {
+	ID       string     `json:"id"`
+	Message  messageRes `json:"message"`
+	Error    string     `json:"error"`
+	Attempts int        `json:"attempts"`
+	Failed   time.Time  `json:"failed"`
+}
+
+type deadLettersPageRes struct {
+	Total       uint64          `json:"total"`
+	Offset      uint64          `json:"offset"`
+	Limit       uint64          `json:"limit"`
+	DeadLetters []deadLetterRes `json:"dead_letters"`
+}
+
+func (res deadLettersPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deadLettersPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deadLettersPageRes) Empty() bool {
+	return false
+}
+
+type updateRes struct{}
+
+func (res updateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/vietquy/alpha/messaging"
+)
+
+// DeadLetter represents the message that couldn't be delivered to the
+// webhook, along with the reason of the last failed attempt.
+type DeadLetter struct {
+	ID        string
+	WebhookID string
+	Message   messaging.Message
+	Error     string
+	Attempts  int
+	Failed    time.Time
+}
+
+// DeadLettersPage contains page related metadata as well as the list of
+// dead letters that belong to this page.
+type DeadLettersPage struct {
+	Total       uint64
+	Offset      uint64
+	Limit       uint64
+	DeadLetters []DeadLetter
+}
+
+// DeadLetterRepository specifies a dead letter persistence API.
+type DeadLetterRepository interface {
+	// Save persists the dead letter.
+	Save(ctx context.Context, dl DeadLetter) error
+
+	// RetrieveAll retrieves the subset of dead letters of the webhook,
+	// starting from the latest one.
+	RetrieveAll(ctx context.Context, webhookID string, offset, limit uint64) (DeadLettersPage, error)
+}
@@ -0,0 +1,33 @@
+package webhooks
+
+import "context"
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
+
+// ThingsService specifies an API for accessing the projects of the user in
+// the things service, on behalf of the user identified by the provided token.
+type ThingsService interface {
+	// ViewProject checks that the project having the provided ID exists and
+	// is accessible by the user.
+	ViewProject(ctx context.Context, token, id string) error
+}
+
+// Delivery represents the request body sent to the webhook, along with its
+// content type and the additional headers.
+type Delivery struct {
+	ContentType string
+	Headers     map[string]string
+	Body        []byte
+}
+
+// Sender specifies an API for sending the deliveries to the webhooks.
+type Sender interface {
+	// Send makes a single attempt to send the delivery to the webhook.
+	// ErrRejected is returned if the webhook rejects the delivery, so that
+	// it must not be retried.
+	Send(ctx context.Context, hook Webhook, d Delivery) error
+}
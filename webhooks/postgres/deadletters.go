@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/webhooks"
+)
+
+var (
+	errSaveDeadLetterDB     = errors.New("Save dead letter to DB failed")
+	errRetrieveDeadLetterDB = errors.New("Retrieving dead letter from DB failed")
+)
+
+var _ webhooks.DeadLetterRepository = (*deadLetterRepository)(nil)
+
+type deadLetterRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeadLetterRepository instantiates a PostgreSQL implementation of dead
+// letter repository.
+func NewDeadLetterRepository(db *sqlx.DB) webhooks.DeadLetterRepository {
+	return &deadLetterRepository{
+		db: db,
+	}
+}
+
+func (dr deadLetterRepository) Save(ctx context.Context, dl webhooks.DeadLetter) error {
+	q := `INSERT INTO dead_letters (id, webhook_id, project, subtopic, publisher, protocol, payload, created, error, attempts, failed)
+	      VALUES (:id, :webhook_id, :project, :subtopic, :publisher, :protocol, :payload, :created, :error, :attempts, :failed)`
+
+	if _, err := dr.db.NamedExecContext(ctx, q, toDBDeadLetter(dl)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return errors.Wrap(webhooks.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(webhooks.ErrConflict, err)
+			case errFK:
+				// The webhook is removed while the message was delivered.
+				return errors.Wrap(webhooks.ErrNotFound, err)
+			}
+		}
+		return errors.Wrap(errSaveDeadLetterDB, err)
+	}
+
+	return nil
+}
+
+func (dr deadLetterRepository) RetrieveAll(ctx context.Context, webhookID string, offset, limit uint64) (webhooks.DeadLettersPage, error) {
+	q := `SELECT id, webhook_id, project, subtopic, publisher, protocol, payload, created, error, attempts, failed
+	      FROM dead_letters WHERE webhook_id = $1 ORDER BY failed DESC, id LIMIT $2 OFFSET $3`
+
+	rows, err := dr.db.QueryxContext(ctx, q, webhookID, limit, offset)
+	if err != nil {
+		return webhooks.DeadLettersPage{}, errors.Wrap(errRetrieveDeadLetterDB, err)
+	}
+	defer rows.Close()
+
+	items := []webhooks.DeadLetter{}
+	for rows.Next() {
+		dbdl := dbDeadLetter{}
+		if err := rows.StructScan(&dbdl); err != nil {
+			return webhooks.DeadLettersPage{}, errors.Wrap(errRetrieveDeadLetterDB, err)
+		}
+		items = append(items, toDeadLetter(dbdl))
+	}
+
+	var total uint64
+	if err := dr.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM dead_letters WHERE webhook_id = $1`, webhookID).Scan(&total); err != nil {
+		return webhooks.DeadLettersPage{}, errors.Wrap(errRetrieveDeadLetterDB, err)
+	}
+
+	page := webhooks.DeadLettersPage{
+		Total:       total,
+		Offset:      offset,
+		Limit:       limit,
+		DeadLetters: items,
+	}
+
+	return page, nil
+}
+
+type dbDeadLetter struct {
+	ID        string         `db:"id"`
+	WebhookID string         `db:"webhook_id"`
+	Project   string         `db:"project"`
+	Subtopic  sql.NullString `db:"subtopic"`
+	Publisher string         `db:"publisher"`
+	Protocol  string         `db:"protocol"`
+	Payload   []byte         `db:"payload"`
+	Created   int64          `db:"created"`
+	Error     string         `db:"error"`
+	Attempts  int            `db:"attempts"`
+	Failed    time.Time      `db:"failed"`
+}
+
+func toDBDeadLetter(dl webhooks.DeadLetter) dbDeadLetter {
+	return dbDeadLetter{
+		ID:        dl.ID,
+		WebhookID: dl.WebhookID,
+		Project:   dl.Message.Project,
+		Subtopic:  sql.NullString{String: dl.Message.Subtopic, Valid: dl.Message.Subtopic != ""},
+		Publisher: dl.Message.Publisher,
+		Protocol:  dl.Message.Protocol,
+		Payload:   dl.Message.Payload,
+		Created:   dl.Message.Created,
+		Error:     dl.Error,
+		Attempts:  dl.Attempts,
+		Failed:    dl.Failed,
+	}
+}
+
+func toDeadLetter(dbdl dbDeadLetter) webhooks.DeadLetter {
+	return webhooks.DeadLetter{
+		ID:        dbdl.ID,
+		WebhookID: dbdl.WebhookID,
+		Message: messaging.Message{
+			Project:   dbdl.Project,
+			Subtopic:  dbdl.Subtopic.String,
+			Publisher: dbdl.Publisher,
+			Protocol:  dbdl.Protocol,
+			Payload:   dbdl.Payload,
+			Created:   dbdl.Created,
+		},
+		Error:    dbdl.Error,
+		Attempts: dbdl.Attempts,
+		Failed:   dbdl.Failed,
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host string
+	Port string
+	User string
+	Pass string
+	Name string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "webhooks_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS webhooks (
+						id       UUID PRIMARY KEY,
+						owner    VARCHAR(254) NOT NULL,
+						name     VARCHAR(1024),
+						project  UUID NOT NULL,
+						subtopic VARCHAR(1024),
+						url      TEXT NOT NULL,
+						secret   TEXT,
+						format   VARCHAR(32) NOT NULL,
+						headers  JSONB NOT NULL,
+						created  TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS webhooks_owner_idx ON webhooks (owner)`,
+					`CREATE INDEX IF NOT EXISTS webhooks_project_idx ON webhooks (project)`,
+					`CREATE TABLE IF NOT EXISTS dead_letters (
+						id         UUID PRIMARY KEY,
+						webhook_id UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+						project    VARCHAR(254) NOT NULL,
+						subtopic   VARCHAR(1024),
+						publisher  VARCHAR(254) NOT NULL,
+						protocol   VARCHAR(254) NOT NULL,
+						payload    BYTEA,
+						created    BIGINT NOT NULL,
+						error      TEXT NOT NULL,
+						attempts   INTEGER NOT NULL,
+						failed     TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS dead_letters_webhook_idx ON dead_letters (webhook_id, failed)`,
+				},
+				Down: []string{"DROP TABLE dead_letters", "DROP TABLE webhooks"},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
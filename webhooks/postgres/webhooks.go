@@ -0,0 +1,255 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/webhooks"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+	errFK        = "foreign_key_violation"
+)
+
+var (
+	errSaveDB      = errors.New("Save webhook to DB failed")
+	errRetrieveDB  = errors.New("Retrieving webhook from DB failed")
+	errUpdateDB    = errors.New("Update webhook in DB failed")
+	errRemoveDB    = errors.New("Remove webhook from DB failed")
+	errMarshalJSON = errors.New("Marshal webhook headers failed")
+)
+
+var _ webhooks.WebhookRepository = (*webhookRepository)(nil)
+
+type webhookRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepository instantiates a PostgreSQL implementation of webhook
+// repository.
+func NewWebhookRepository(db *sqlx.DB) webhooks.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (wr webhookRepository) Save(ctx context.Context, hook webhooks.Webhook) error {
+	q := `INSERT INTO webhooks (id, owner, name, project, subtopic, url, secret, format, headers, created)
+	      VALUES (:id, :owner, :name, :project, :subtopic, :url, :secret, :format, :headers, :created)`
+
+	dbw, err := toDBWebhook(hook)
+	if err != nil {
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	if _, err := wr.db.NamedExecContext(ctx, q, dbw); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return errors.Wrap(webhooks.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(webhooks.ErrConflict, err)
+			}
+		}
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) Update(ctx context.Context, hook webhooks.Webhook) error {
+	q := `UPDATE webhooks SET name = :name, project = :project, subtopic = :subtopic, url = :url,
+	      secret = :secret, format = :format, headers = :headers WHERE id = :id AND owner = :owner`
+
+	dbw, err := toDBWebhook(hook)
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	res, err := wr.db.NamedExecContext(ctx, q, dbw)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(webhooks.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	if cnt == 0 {
+		return webhooks.ErrNotFound
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) RetrieveByID(ctx context.Context, owner, id string) (webhooks.Webhook, error) {
+	q := `SELECT id, owner, name, project, subtopic, url, secret, format, headers, created
+	      FROM webhooks WHERE id = $1 AND owner = $2`
+
+	dbw := dbWebhook{}
+	if err := wr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbw); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return webhooks.Webhook{}, errors.Wrap(webhooks.ErrNotFound, err)
+		}
+		return webhooks.Webhook{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toWebhook(dbw)
+}
+
+func (wr webhookRepository) RetrieveByProject(ctx context.Context, project string) ([]webhooks.Webhook, error) {
+	q := `SELECT id, owner, name, project, subtopic, url, secret, format, headers, created
+	      FROM webhooks WHERE project = $1`
+
+	rows, err := wr.db.QueryxContext(ctx, q, project)
+	if err != nil {
+		// Messages published to the projects having the invalid ID are not
+		// delivered to any webhook.
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return []webhooks.Webhook{}, nil
+		}
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	return scanWebhooks(rows)
+}
+
+func (wr webhookRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (webhooks.WebhooksPage, error) {
+	q := `SELECT id, owner, name, project, subtopic, url, secret, format, headers, created
+	      FROM webhooks WHERE owner = $1 ORDER BY created, id LIMIT $2 OFFSET $3`
+
+	rows, err := wr.db.QueryxContext(ctx, q, owner, limit, offset)
+	if err != nil {
+		return webhooks.WebhooksPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	items, err := scanWebhooks(rows)
+	if err != nil {
+		return webhooks.WebhooksPage{}, err
+	}
+
+	var total uint64
+	if err := wr.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM webhooks WHERE owner = $1`, owner).Scan(&total); err != nil {
+		return webhooks.WebhooksPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	page := webhooks.WebhooksPage{
+		Total:    total,
+		Offset:   offset,
+		Limit:    limit,
+		Webhooks: items,
+	}
+
+	return page, nil
+}
+
+func (wr webhookRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM webhooks WHERE id = $1 AND owner = $2`
+
+	if _, err := wr.db.ExecContext(ctx, q, id, owner); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return errors.Wrap(errRemoveDB, err)
+	}
+
+	return nil
+}
+
+func scanWebhooks(rows *sqlx.Rows) ([]webhooks.Webhook, error) {
+	items := []webhooks.Webhook{}
+	for rows.Next() {
+		dbw := dbWebhook{}
+		if err := rows.StructScan(&dbw); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+
+		hook, err := toWebhook(dbw)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, hook)
+	}
+
+	return items, nil
+}
+
+type dbWebhook struct {
+	ID       string         `db:"id"`
+	Owner    string         `db:"owner"`
+	Name     sql.NullString `db:"name"`
+	Project  string         `db:"project"`
+	Subtopic sql.NullString `db:"subtopic"`
+	URL      string         `db:"url"`
+	Secret   sql.NullString `db:"secret"`
+	Format   string         `db:"format"`
+	Headers  []byte         `db:"headers"`
+	Created  time.Time      `db:"created"`
+}
+
+func toDBWebhook(hook webhooks.Webhook) (dbWebhook, error) {
+	headers := hook.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return dbWebhook{}, errors.Wrap(errMarshalJSON, err)
+	}
+
+	dbw := dbWebhook{
+		ID:       hook.ID,
+		Owner:    hook.Owner,
+		Name:     sql.NullString{String: hook.Name, Valid: hook.Name != ""},
+		Project:  hook.Project,
+		Subtopic: sql.NullString{String: hook.Subtopic, Valid: hook.Subtopic != ""},
+		URL:      hook.URL,
+		Secret:   sql.NullString{String: hook.Secret, Valid: hook.Secret != ""},
+		Format:   hook.Format,
+		Headers:  data,
+		Created:  hook.Created,
+	}
+
+	return dbw, nil
+}
+
+func toWebhook(dbw dbWebhook) (webhooks.Webhook, error) {
+	headers := map[string]string{}
+	if err := json.Unmarshal(dbw.Headers, &headers); err != nil {
+		return webhooks.Webhook{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	hook := webhooks.Webhook{
+		ID:       dbw.ID,
+		Owner:    dbw.Owner,
+		Name:     dbw.Name.String,
+		Project:  dbw.Project,
+		Subtopic: dbw.Subtopic.String,
+		URL:      dbw.URL,
+		Secret:   dbw.Secret.String,
+		Format:   dbw.Format,
+		Headers:  headers,
+		Created:  dbw.Created,
+	}
+
+	return hook, nil
+}
@@ -0,0 +1,79 @@
+package webhooks
+
+import (
+	"sync"
+
+	"github.com/vietquy/alpha/messaging"
+)
+
+// QueueConfig specifies the number of the workers sending the deliveries of
+// each webhook, and the number of the deliveries waiting to be sent, above
+// which the messages are saved as the dead letters instead.
+type QueueConfig struct {
+	Workers int
+	Size    int
+}
+
+type job struct {
+	hook     Webhook
+	delivery Delivery
+	msg      messaging.Message
+}
+
+// queues holds the bounded queue of the deliveries of each webhook, started
+// on the first delivery along with its workers.
+type queues struct {
+	mu     sync.Mutex
+	cfg    QueueConfig
+	send   func(job)
+	queues map[string]chan job
+}
+
+func newQueues(cfg QueueConfig, send func(job)) *queues {
+	return &queues{
+		cfg:    cfg,
+		send:   send,
+		queues: make(map[string]chan job),
+	}
+}
+
+// push enqueues the delivery, returning false if the queue of the webhook is
+// full.
+func (qs *queues) push(j job) bool {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	q, ok := qs.queues[j.hook.ID]
+	if !ok {
+		q = make(chan job, qs.cfg.Size)
+		qs.queues[j.hook.ID] = q
+		for i := 0; i < qs.cfg.Workers; i++ {
+			go qs.work(q)
+		}
+	}
+
+	select {
+	case q <- j:
+		return true
+	default:
+		return false
+	}
+}
+
+// remove stops the workers of the webhook once the queued deliveries are
+// sent.
+func (qs *queues) remove(id string) {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	if q, ok := qs.queues[id]; ok {
+		close(q)
+		delete(qs.queues, id)
+	}
+}
+
+func (qs *queues) work(q chan job) {
+	for j := range q {
+		qs.send(j)
+	}
+}
@@ -0,0 +1,110 @@
+// Package sender provides the HTTP sender of the webhook deliveries.
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	goerrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/webhooks"
+)
+
+// SignatureHeader is the header carrying the HMAC-SHA256 signature of the
+// delivery body, computed using the webhook secret.
+const SignatureHeader = "X-Alpha-Signature"
+
+var errInternalAddress = errors.New("webhook resolves to internal address")
+
+var _ webhooks.Sender = (*sender)(nil)
+
+type sender struct {
+	http *http.Client
+}
+
+// New instantiates the sender posting the deliveries to the webhooks, and
+// failing if the webhook doesn't respond within the timeout. Connections to
+// the internal addresses are refused, including the ones the webhook host
+// name resolves to, or the redirects lead to.
+func New(timeout time.Duration) webhooks.Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &sender{
+		http: &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// control refuses the connections to the internal addresses, being called
+// with the resolved address right before connecting.
+func control(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || webhooks.Internal(ip) {
+		return errInternalAddress
+	}
+
+	return nil
+}
+
+func (s sender) Send(ctx context.Context, hook webhooks.Webhook, d webhooks.Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return errors.Wrap(webhooks.ErrRejected, err)
+	}
+
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range d.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", d.ContentType)
+	if hook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(hook.Secret, d.Body))
+	}
+
+	res, err := s.http.Do(req)
+	if err != nil {
+		// The dialer error is wrapped by the net and the url errors.
+		if goerrors.Is(err, errInternalAddress) {
+			return errors.Wrap(webhooks.ErrRejected, errInternalAddress)
+		}
+		return err
+	}
+	res.Body.Close()
+
+	switch {
+	case res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices:
+		return nil
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError:
+		return errors.New(fmt.Sprintf("webhook responded with status %d", res.StatusCode))
+	default:
+		return errors.Wrap(webhooks.ErrRejected, errors.New(fmt.Sprintf("webhook responded with status %d", res.StatusCode)))
+	}
+}
+
+// Sign returns the signature of the body, in the form of sha256=<hex digest>,
+// that the webhooks use to verify the deliveries.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
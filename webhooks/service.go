@@ -0,0 +1,332 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/vietquy/alpha"
+	"github.com/vietquy/alpha/authn"
+	"github.com/vietquy/alpha/errors"
+	log "github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/transformer"
+)
+
+const (
+	rawContentType  = "application/octet-stream"
+	jsonContentType = "application/json"
+
+	// Headers of the raw deliveries carrying the message fields.
+	projectHeader   = "X-Alpha-Project"
+	subtopicHeader  = "X-Alpha-Subtopic"
+	publisherHeader = "X-Alpha-Publisher"
+	protocolHeader  = "X-Alpha-Protocol"
+	createdHeader   = "X-Alpha-Created"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrConflict indicates that the entity already exists.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrThings indicates failed communication with the things service.
+	ErrThings = errors.New("things service request failed")
+
+	// ErrRejected indicates that the webhook rejected the delivery.
+	ErrRejected = errors.New("delivery rejected by webhook")
+
+	// ErrQueueFull indicates that the delivery was dropped since too many
+	// deliveries to the webhook are pending.
+	ErrQueueFull = errors.New("webhook delivery queue is full")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// AddWebhook adds the webhook to the user identified by the provided key.
+	AddWebhook(ctx context.Context, token string, hook Webhook) (Webhook, error)
+
+	// UpdateWebhook replaces the webhook having the provided ID, that
+	// belongs to the user identified by the provided key.
+	UpdateWebhook(ctx context.Context, token string, hook Webhook) error
+
+	// ViewWebhook retrieves the webhook having the provided ID, that belongs
+	// to the user identified by the provided key.
+	ViewWebhook(ctx context.Context, token, id string) (Webhook, error)
+
+	// ListWebhooks retrieves the subset of webhooks that belong to the user
+	// identified by the provided key.
+	ListWebhooks(ctx context.Context, token string, offset, limit uint64) (WebhooksPage, error)
+
+	// RemoveWebhook removes the webhook along with its dead letters.
+	RemoveWebhook(ctx context.Context, token, id string) error
+
+	// ListDeadLetters retrieves the subset of the messages that couldn't be
+	// delivered to the webhook, starting from the latest one.
+	ListDeadLetters(ctx context.Context, token, id string, offset, limit uint64) (DeadLettersPage, error)
+
+	// Deliver delivers the message to the webhooks of the project it is
+	// published to, in the background. Messages that can't be delivered
+	// after all attempts, or can't be queued since the queue of the webhook
+	// is full, are saved as the dead letters.
+	Deliver(ctx context.Context, msg messaging.Message) error
+}
+
+var _ Service = (*webhooksService)(nil)
+
+type webhooksService struct {
+	auth        alpha.AuthNServiceClient
+	webhooks    WebhookRepository
+	deadLetters DeadLetterRepository
+	things      ThingsService
+	idp         IdentityProvider
+	transformer transformer.Transformer
+	sender      Sender
+	retry       messaging.RetryPolicy
+	queues      *queues
+	logger      log.Logger
+}
+
+// New instantiates the webhooks service implementation. Deliveries of each
+// webhook are sent by the workers of its bounded queue.
+func New(auth alpha.AuthNServiceClient, webhooks WebhookRepository, deadLetters DeadLetterRepository, things ThingsService, idp IdentityProvider, t transformer.Transformer, sender Sender, retry messaging.RetryPolicy, queue QueueConfig, logger log.Logger) Service {
+	ws := &webhooksService{
+		auth:        auth,
+		webhooks:    webhooks,
+		deadLetters: deadLetters,
+		things:      things,
+		idp:         idp,
+		transformer: t,
+		sender:      sender,
+		retry:       retry,
+		logger:      logger,
+	}
+	ws.queues = newQueues(queue, ws.send)
+
+	return ws
+}
+
+func (ws webhooksService) AddWebhook(ctx context.Context, token string, hook Webhook) (Webhook, error) {
+	owner, err := ws.identify(ctx, token)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	if err := ws.things.ViewProject(ctx, token, hook.Project); err != nil {
+		return Webhook{}, err
+	}
+
+	hook.ID, err = ws.idp.ID()
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	if hook.Format == "" {
+		hook.Format = RawFormat
+	}
+	hook.Owner = owner
+	hook.Created = time.Now().UTC()
+
+	if err := ws.webhooks.Save(ctx, hook); err != nil {
+		return Webhook{}, err
+	}
+
+	return hook, nil
+}
+
+func (ws webhooksService) UpdateWebhook(ctx context.Context, token string, hook Webhook) error {
+	owner, err := ws.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	current, err := ws.webhooks.RetrieveByID(ctx, owner, hook.ID)
+	if err != nil {
+		return err
+	}
+
+	if hook.Project != current.Project {
+		if err := ws.things.ViewProject(ctx, token, hook.Project); err != nil {
+			return err
+		}
+	}
+
+	if hook.Format == "" {
+		hook.Format = RawFormat
+	}
+	hook.Owner = owner
+
+	return ws.webhooks.Update(ctx, hook)
+}
+
+func (ws webhooksService) ViewWebhook(ctx context.Context, token, id string) (Webhook, error) {
+	owner, err := ws.identify(ctx, token)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	return ws.webhooks.RetrieveByID(ctx, owner, id)
+}
+
+func (ws webhooksService) ListWebhooks(ctx context.Context, token string, offset, limit uint64) (WebhooksPage, error) {
+	owner, err := ws.identify(ctx, token)
+	if err != nil {
+		return WebhooksPage{}, err
+	}
+
+	return ws.webhooks.RetrieveAll(ctx, owner, offset, limit)
+}
+
+func (ws webhooksService) RemoveWebhook(ctx context.Context, token, id string) error {
+	owner, err := ws.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if err := ws.webhooks.Remove(ctx, owner, id); err != nil {
+		return err
+	}
+
+	ws.queues.remove(id)
+	return nil
+}
+
+func (ws webhooksService) ListDeadLetters(ctx context.Context, token, id string, offset, limit uint64) (DeadLettersPage, error) {
+	hook, err := ws.ViewWebhook(ctx, token, id)
+	if err != nil {
+		return DeadLettersPage{}, err
+	}
+
+	return ws.deadLetters.RetrieveAll(ctx, hook.ID, offset, limit)
+}
+
+func (ws webhooksService) Deliver(ctx context.Context, msg messaging.Message) error {
+	hooks, err := ws.webhooks.RetrieveByProject(ctx, msg.Project)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		if hook.Subtopic != "" && hook.Subtopic != msg.Subtopic {
+			continue
+		}
+
+		d, err := ws.delivery(hook, msg)
+		if err != nil {
+			// The message that can't be transformed is never delivered.
+			ws.saveDeadLetter(hook, msg, err, 0)
+			continue
+		}
+
+		if !ws.queues.push(job{hook: hook, delivery: d, msg: msg}) {
+			ws.saveDeadLetter(hook, msg, ErrQueueFull, 0)
+		}
+	}
+
+	return nil
+}
+
+// send sends the delivery to the webhook, retrying it according to the retry
+// policy, and saves the message as the dead letter if all attempts fail.
+func (ws webhooksService) send(j job) {
+	for attempts := 1; ; attempts++ {
+		err := ws.sender.Send(context.Background(), j.hook, j.delivery)
+		if err == nil {
+			return
+		}
+
+		if errors.Contains(err, ErrRejected) || !ws.retry.ShouldRetry(err, attempts) {
+			ws.saveDeadLetter(j.hook, j.msg, err, attempts)
+			return
+		}
+
+		time.Sleep(ws.retry.Delay(attempts))
+	}
+}
+
+func (ws webhooksService) saveDeadLetter(hook Webhook, msg messaging.Message, reason error, attempts int) {
+	id, err := ws.idp.ID()
+	if err != nil {
+		ws.logger.Error(fmt.Sprintf("Failed to save dead letter of webhook %s: %s", hook.ID, err))
+		return
+	}
+
+	dl := DeadLetter{
+		ID:        id,
+		WebhookID: hook.ID,
+		Message:   msg,
+		Error:     reason.Error(),
+		Attempts:  attempts,
+		Failed:    time.Now().UTC(),
+	}
+	if err := ws.deadLetters.Save(context.Background(), dl); err != nil {
+		ws.logger.Error(fmt.Sprintf("Failed to save dead letter of webhook %s: %s", hook.ID, err))
+	}
+}
+
+// delivery returns the delivery of the message in the format of the webhook.
+func (ws webhooksService) delivery(hook Webhook, msg messaging.Message) (Delivery, error) {
+	if hook.Format == JSONFormat {
+		t, err := ws.transformer.Transform(msg)
+		if err != nil {
+			return Delivery{}, err
+		}
+
+		var data interface{} = t
+		if msgs, ok := t.(transformer.Messages); ok {
+			data = msgs.Data
+		}
+
+		body, err := json.Marshal(data)
+		if err != nil {
+			return Delivery{}, err
+		}
+
+		return Delivery{ContentType: jsonContentType, Body: body}, nil
+	}
+
+	d := Delivery{
+		ContentType: rawContentType,
+		Headers: map[string]string{
+			projectHeader:   msg.Project,
+			publisherHeader: msg.Publisher,
+			protocolHeader:  msg.Protocol,
+			createdHeader:   strconv.FormatInt(msg.Created, 10),
+		},
+		Body: msg.Payload,
+	}
+	if msg.Subtopic != "" {
+		d.Headers[subtopicHeader] = msg.Subtopic
+	}
+	switch ct := msg.Header(messaging.ContentTypeHeader); {
+	case ct != "":
+		d.ContentType = ct
+	case json.Valid(msg.Payload):
+		d.ContentType = jsonContentType
+	}
+
+	return d, nil
+}
+
+// identify returns the owner of the webhooks of the user identified by the
+// provided token, see authn.Owner.
+func (ws webhooksService) identify(ctx context.Context, token string) (string, error) {
+	res, err := ws.auth.Identify(ctx, &alpha.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return authn.Owner(res.GetValue(), res.GetOrganization()), nil
+}
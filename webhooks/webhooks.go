@@ -0,0 +1,103 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Formats of the messages delivered to the webhooks.
+const (
+	// RawFormat delivers the message payload as is, passing the message
+	// fields in the request headers.
+	RawFormat = "raw"
+
+	// JSONFormat delivers the messages transformed by the JSON transformer.
+	JSONFormat = "json"
+)
+
+// Webhook represents the URL the messages published to the project are
+// delivered to. An empty subtopic matches the messages published to any
+// subtopic of the project. If the secret is set, the deliveries are signed
+// using it, while the headers are added to each delivery request.
+type Webhook struct {
+	ID       string
+	Owner    string
+	Name     string
+	Project  string
+	Subtopic string
+	URL      string
+	Secret   string
+	Format   string
+	Headers  map[string]string
+	Created  time.Time
+}
+
+// ValidURL reports whether the webhook URL uses the http or https scheme and
+// doesn't point to the internal destination, i.e. the localhost or the
+// internal IP address. Host names are checked against the addresses they
+// resolve to by the sender, when connecting to the webhook.
+func ValidURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return false
+	}
+	if h := strings.ToLower(strings.TrimSuffix(host, ".")); h == "localhost" || strings.HasSuffix(h, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil && Internal(ip) {
+		return false
+	}
+
+	return true
+}
+
+// Internal reports whether the IP address is the loopback, private,
+// link-local, multicast or unspecified address, which the deliveries are
+// never sent to.
+func Internal(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// WebhooksPage contains page related metadata as well as the list of
+// webhooks that belong to this page.
+type WebhooksPage struct {
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Webhooks []Webhook
+}
+
+// WebhookRepository specifies a webhook persistence API.
+type WebhookRepository interface {
+	// Save persists the webhook.
+	Save(ctx context.Context, hook Webhook) error
+
+	// Update updates the webhook, except for its owner and creation time.
+	Update(ctx context.Context, hook Webhook) error
+
+	// RetrieveByID retrieves the webhook having the provided identifier,
+	// that is owned by the specified user.
+	RetrieveByID(ctx context.Context, owner, id string) (Webhook, error)
+
+	// RetrieveByProject retrieves all webhooks the messages published to
+	// the project having the provided identifier are delivered to.
+	RetrieveByProject(ctx context.Context, project string) ([]Webhook, error)
+
+	// RetrieveAll retrieves the subset of webhooks owned by the specified
+	// user.
+	RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (WebhooksPage, error)
+
+	// Remove removes the webhook having the provided identifier, that is
+	// owned by the specified user, along with its dead letters.
+	Remove(ctx context.Context, owner, id string) error
+}
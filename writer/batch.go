@@ -0,0 +1,229 @@
+package writer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/logger"
+	"github.com/vietquy/alpha/messaging"
+	"github.com/vietquy/alpha/transformer"
+)
+
+// ErrBatchWriterClosed indicates that the message is written after the
+// batch writer is closed.
+var ErrBatchWriterClosed = errors.New("batch writer is closed")
+
+// BatchConfig specifies how the messages are batched and written.
+type BatchConfig struct {
+	// Size is the number of messages that makes the batch written. Zero
+	// size disables batching, so that the messages are written at once.
+	Size int
+
+	// Timeout is the interval the buffered messages are written at, even if
+	// the batch isn't full.
+	Timeout time.Duration
+
+	// QueueSize is the number of batches waiting to be written, beyond which
+	// writing blocks, so that the subscriber slows down.
+	QueueSize int
+
+	// Concurrency is the number of batches written at the same time.
+	Concurrency int
+
+	// Retry specifies how many times writing of the batch is attempted, e.g.
+	// while the database is unavailable, before the batch is dropped.
+	Retry messaging.RetryPolicy
+}
+
+// BatchWriter is the Writer buffering the messages and writing them in
+// batches using the wrapped Writer. Write returns once the batch of the
+// messages is written, with the error the batch is written with, so that
+// the messages are acknowledged only once persisted. The subscriptions are
+// therefore expected to handle up to the batch size messages at the same
+// time, see messaging.Pipeline.
+type BatchWriter interface {
+	Writer
+
+	// Close writes the buffered messages, waiting for all batches to be
+	// written, and rejects the messages written afterwards.
+	Close()
+}
+
+var _ BatchWriter = (*batchWriter)(nil)
+
+type batchWriter struct {
+	writer  Writer
+	cfg     BatchConfig
+	logger  logger.Logger
+	mu      sync.Mutex
+	closed  bool
+	buffers map[string]*batch
+	queue   chan *batch
+	pending sync.WaitGroup
+	workers sync.WaitGroup
+	done    chan struct{}
+	ticker  sync.WaitGroup
+}
+
+// batch holds the buffered messages of the same format, and the result of
+// their writing, available once done is closed.
+type batch struct {
+	msgs transformer.Messages
+	done chan struct{}
+	err  error
+}
+
+// NewBatchWriter returns the BatchWriter buffering the transformed messages
+// of the same format, and writing them once the batch is full or the batch
+// timeout passes. Messages that are not transformed are written at once.
+func NewBatchWriter(writer Writer, cfg BatchConfig, logger logger.Logger) BatchWriter {
+	bw := &batchWriter{
+		writer:  writer,
+		cfg:     cfg,
+		logger:  logger,
+		buffers: make(map[string]*batch),
+		queue:   make(chan *batch, cfg.QueueSize),
+		done:    make(chan struct{}),
+	}
+	if cfg.Size < 1 {
+		return bw
+	}
+
+	for i := 0; i < cfg.Concurrency; i++ {
+		bw.workers.Add(1)
+		go bw.work()
+	}
+
+	bw.ticker.Add(1)
+	go bw.tick()
+
+	return bw
+}
+
+func (bw *batchWriter) Write(messages interface{}) error {
+	msgs, ok := messages.(transformer.Messages)
+	if !ok || bw.cfg.Size < 1 {
+		return bw.writer.Write(messages)
+	}
+
+	bw.mu.Lock()
+	if bw.closed {
+		bw.mu.Unlock()
+		return ErrBatchWriterClosed
+	}
+
+	b, ok := bw.buffers[msgs.Format]
+	if !ok {
+		b = &batch{
+			msgs: transformer.Messages{Format: msgs.Format},
+			done: make(chan struct{}),
+		}
+		bw.buffers[msgs.Format] = b
+	}
+	b.msgs.Data = append(b.msgs.Data, msgs.Data...)
+	if len(b.msgs.Data) < bw.cfg.Size {
+		bw.mu.Unlock()
+		<-b.done
+		return b.err
+	}
+	delete(bw.buffers, msgs.Format)
+	bw.pending.Add(1)
+	bw.mu.Unlock()
+
+	bw.queue <- b
+	bw.pending.Done()
+	<-b.done
+
+	return b.err
+}
+
+func (bw *batchWriter) Close() {
+	bw.mu.Lock()
+	if bw.closed {
+		bw.mu.Unlock()
+		return
+	}
+	bw.closed = true
+	batches := bw.take()
+	bw.mu.Unlock()
+
+	close(bw.done)
+	bw.ticker.Wait()
+	bw.pending.Wait()
+
+	for _, b := range batches {
+		bw.queue <- b
+	}
+	close(bw.queue)
+	bw.workers.Wait()
+}
+
+// tick queues the buffered messages once per batch timeout.
+func (bw *batchWriter) tick() {
+	defer bw.ticker.Done()
+
+	t := time.NewTicker(bw.cfg.Timeout)
+	defer t.Stop()
+	for {
+		select {
+		case <-bw.done:
+			return
+		case <-t.C:
+			bw.mu.Lock()
+			batches := bw.take()
+			bw.mu.Unlock()
+			for _, b := range batches {
+				select {
+				case bw.queue <- b:
+				case <-bw.done:
+					// Close waits for the ticker before closing the
+					// queue, so the batch is written here instead.
+					bw.write(b)
+				}
+			}
+		}
+	}
+}
+
+// take empties the buffers, returning their batches. The caller must hold
+// the lock.
+func (bw *batchWriter) take() []*batch {
+	batches := []*batch{}
+	for _, b := range bw.buffers {
+		batches = append(batches, b)
+	}
+	bw.buffers = make(map[string]*batch)
+
+	return batches
+}
+
+func (bw *batchWriter) work() {
+	defer bw.workers.Done()
+	for b := range bw.queue {
+		bw.write(b)
+	}
+}
+
+// write writes the batch, retrying it according to the retry policy, and
+// passes the result to the writers of the batch messages.
+func (bw *batchWriter) write(b *batch) {
+	defer close(b.done)
+
+	attempts := 0
+	for {
+		attempts++
+		b.err = bw.writer.Write(b.msgs)
+		if b.err == nil {
+			return
+		}
+		if !bw.cfg.Retry.ShouldRetry(b.err, attempts) {
+			bw.logger.Error(fmt.Sprintf("Failed to write batch of %d messages after %d attempts: %s", len(b.msgs.Data), attempts, b.err))
+			return
+		}
+
+		bw.logger.Warn(fmt.Sprintf("Failed to write batch of %d messages: %s", len(b.msgs.Data), b.err))
+		time.Sleep(bw.cfg.Retry.Delay(attempts))
+	}
+}
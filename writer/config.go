@@ -0,0 +1,145 @@
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/messaging"
+	pubsub "github.com/vietquy/alpha/messaging/nats"
+)
+
+const prjPrefix = "projects"
+
+// Handling of the messages having the content type with no transformer.
+const (
+	// RejectUnknown rejects the messages, so that they're not written.
+	RejectUnknown = "reject"
+
+	// BlobUnknown writes the messages as the opaque blobs.
+	BlobUnknown = "blob"
+)
+
+// Config specifies the messages the writer persists. Subjects and the
+// excluded subjects are in the NATS format, i.e. projects.<project>.<subtopic>
+// with the * and > wildcards. Empty lists of the content types and the
+// formats don't filter the messages.
+type Config struct {
+	// Subjects are the subjects the writer subscribes to.
+	Subjects []string `json:"subjects"`
+
+	// Exclude are the subjects whose messages are dropped, e.g. the noisy
+	// subtopics of the subscribed projects.
+	Exclude []string `json:"exclude"`
+
+	// ContentTypes are the content types of the persisted messages, taken
+	// from the content-type message header. Messages without the header are
+	// dropped unless the empty content type is listed.
+	ContentTypes []string `json:"content_types"`
+
+	// Formats are the formats of the persisted transformed messages.
+	Formats []string `json:"formats"`
+
+	// UnknownContentTypes specifies how the messages having the content type
+	// with no transformer are handled, i.e. rejected or written as blobs.
+	// Messages are rejected by default.
+	UnknownContentTypes string `json:"unknown_content_types"`
+}
+
+// LoadConfig reads the configuration file having the provided path. Empty
+// path gives the configuration subscribing to all projects with no filters.
+func LoadConfig(path string) (Config, error) {
+	cfg := Config{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, errors.Wrap(errOpenConfFile, err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return Config{}, errors.Wrap(errParseConfFile, err)
+		}
+	}
+
+	if len(cfg.Subjects) == 0 {
+		cfg.Subjects = []string{pubsub.SubjectAllProjects}
+	}
+
+	switch cfg.UnknownContentTypes {
+	case "":
+		cfg.UnknownContentTypes = RejectUnknown
+	case RejectUnknown, BlobUnknown:
+	default:
+		return Config{}, errors.Wrap(errParseConfFile, errors.New(fmt.Sprintf("invalid unknown content types handling %s", cfg.UnknownContentTypes)))
+	}
+
+	for _, s := range append(cfg.Subjects, cfg.Exclude...) {
+		if !validSubject(s) {
+			return Config{}, errors.Wrap(errParseConfFile, errors.New(fmt.Sprintf("invalid subject %s", s)))
+		}
+	}
+
+	return cfg, nil
+}
+
+// accepts checks whether the message passes the subject and the content type
+// filters.
+func (cfg Config) accepts(msg messaging.Message) bool {
+	subject := []string{prjPrefix, msg.Project}
+	if msg.Subtopic != "" {
+		subject = append(subject, strings.Split(msg.Subtopic, ".")...)
+	}
+	for _, e := range cfg.Exclude {
+		if match(strings.Split(e, "."), subject) {
+			return false
+		}
+	}
+
+	return len(cfg.ContentTypes) == 0 || contains(cfg.ContentTypes, msg.Header(messaging.ContentTypeHeader))
+}
+
+// acceptsFormat checks whether the transformed messages pass the formats
+// filter.
+func (cfg Config) acceptsFormat(format string) bool {
+	return len(cfg.Formats) == 0 || contains(cfg.Formats, format)
+}
+
+func validSubject(subject string) bool {
+	tokens := strings.Split(subject, ".")
+	for i, t := range tokens {
+		if t == "" || (t == ">" && i != len(tokens)-1) {
+			return false
+		}
+		if len(t) > 1 && strings.ContainsAny(t, "*>") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// match checks whether the subject matches the pattern having the NATS
+// wildcards.
+func match(pattern, subject []string) bool {
+	for i, t := range pattern {
+		if t == ">" {
+			return len(subject) > i
+		}
+		if i >= len(subject) || (t != "*" && t != subject[i]) {
+			return false
+		}
+	}
+
+	return len(pattern) == len(subject)
+}
+
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+
+	return false
+}
@@ -7,10 +7,13 @@ import (
 	"github.com/vietquy/alpha/writer"
 	"github.com/vietquy/alpha/errors"
 	"github.com/vietquy/alpha/transformer"
+	"github.com/vietquy/alpha/transformer/senml"
 
 	influxdata "github.com/influxdata/influxdb/client/v2"
 )
 
+const headers = "headers/"
+
 var errSaveMessage = errors.New("failed to save message to influxdb database")
 
 var _ writer.Writer = (*influxRepo)(nil)
@@ -37,7 +40,13 @@ func (repo *influxRepo) Write(message interface{}) error {
 	if err != nil {
 		return errors.Wrap(errSaveMessage, err)
 	}
-	pts, err = repo.jsonPoints(pts, message.(transformer.Messages))
+	msgs := message.(transformer.Messages)
+	switch msgs.Format {
+	case senml.Format:
+		pts, err = repo.senmlPoints(pts, msgs)
+	default:
+		pts, err = repo.jsonPoints(pts, msgs)
+	}
 	if err != nil {
 		return err
 	}
@@ -59,6 +68,11 @@ func (repo *influxRepo) jsonPoints(pts influxdata.BatchPoints, msgs transformer.
 		}
 		// At least one known field need to exist so that COUNT can be performed.
 		fields["protocol"] = m.Protocol
+		// Headers are stored as the composite keys, so that the reader
+		// returns them as the nested object.
+		for k, v := range m.Headers {
+			fields[headers+k] = v
+		}
 		pt, err := influxdata.NewPoint(msgs.Format, jsonTags(m), fields, t)
 		if err != nil {
 			return nil, errors.Wrap(errSaveMessage, err)
@@ -69,6 +83,40 @@ func (repo *influxRepo) jsonPoints(pts influxdata.BatchPoints, msgs transformer.
 	return pts, nil
 }
 
+// senmlPoints adds the points of the SenML measurements, tagged by the
+// measurement name. Point time is the measurement time, so it isn't stored
+// as the field.
+func (repo *influxRepo) senmlPoints(pts influxdata.BatchPoints, msgs transformer.Messages) (influxdata.BatchPoints, error) {
+	for _, m := range msgs.Data {
+		t := time.Unix(0, m.Created)
+
+		fields := make(map[string]interface{})
+		for k, v := range m.Payload {
+			switch k {
+			case senml.NameKey, senml.TimeKey:
+				continue
+			}
+			fields[k] = v
+		}
+		fields["protocol"] = m.Protocol
+		for k, v := range m.Headers {
+			fields[headers+k] = v
+		}
+
+		tgs := jsonTags(m)
+		if name, ok := m.Payload[senml.NameKey].(string); ok {
+			tgs[senml.NameKey] = name
+		}
+		pt, err := influxdata.NewPoint(msgs.Format, tgs, fields, t)
+		if err != nil {
+			return nil, errors.Wrap(errSaveMessage, err)
+		}
+		pts.AddPoint(pt)
+	}
+
+	return pts, nil
+}
+
 func jsonTags(msg transformer.Message) tags {
 	return tags{
 		"project":   msg.Project,
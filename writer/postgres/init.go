@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host string
+	Port string
+	User string
+	Pass string
+	Name string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "messages_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS messages (
+						project   VARCHAR(254) NOT NULL,
+						subtopic  VARCHAR(1024),
+						publisher VARCHAR(254) NOT NULL,
+						protocol  VARCHAR(254) NOT NULL,
+						format    VARCHAR(254) NOT NULL,
+						headers   JSONB,
+						payload   JSONB,
+						created   BIGINT NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS messages_project_created_idx ON messages (project, created DESC)`,
+					// The table is turned into the hypertable partitioned by
+					// the creation time in nanoseconds, in daily chunks, if
+					// the TimescaleDB extension is available.
+					`DO $$
+					BEGIN
+						IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+							PERFORM create_hypertable('messages', 'created', chunk_time_interval => 86400000000000, if_not_exists => TRUE);
+						END IF;
+					END
+					$$`,
+				},
+				Down: []string{"DROP TABLE messages"},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
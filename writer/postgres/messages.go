@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/transformer"
+	"github.com/vietquy/alpha/writer"
+)
+
+var (
+	errSaveMessage        = errors.New("failed to save message to postgres database")
+	errUnsupportedMessage = errors.New("unsupported message type")
+)
+
+var _ writer.Writer = (*postgresRepo)(nil)
+
+type postgresRepo struct {
+	db *sqlx.DB
+}
+
+// New returns new PostgreSQL writer.
+func New(db *sqlx.DB) writer.Writer {
+	return &postgresRepo{
+		db: db,
+	}
+}
+
+func (repo *postgresRepo) Write(message interface{}) error {
+	msgs, ok := message.(transformer.Messages)
+	if !ok {
+		return errors.Wrap(errSaveMessage, errUnsupportedMessage)
+	}
+
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.Wrap(errSaveMessage, err)
+	}
+
+	q := `INSERT INTO messages (project, subtopic, publisher, protocol, format, headers, payload, created)
+	      VALUES (:project, :subtopic, :publisher, :protocol, :format, :headers, :payload, :created);`
+
+	for _, m := range msgs.Data {
+		dbm, err := toDBMessage(m, msgs.Format)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(errSaveMessage, err)
+		}
+
+		if _, err := tx.NamedExec(q, dbm); err != nil {
+			tx.Rollback()
+			return errors.Wrap(errSaveMessage, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errSaveMessage, err)
+	}
+
+	return nil
+}
+
+type dbMessage struct {
+	Project   string         `db:"project"`
+	Subtopic  sql.NullString `db:"subtopic"`
+	Publisher string         `db:"publisher"`
+	Protocol  string         `db:"protocol"`
+	Format    string         `db:"format"`
+	Headers   sql.NullString `db:"headers"`
+	Payload   []byte         `db:"payload"`
+	Created   int64          `db:"created"`
+}
+
+func toDBMessage(msg transformer.Message, format string) (dbMessage, error) {
+	var headers sql.NullString
+	if len(msg.Headers) > 0 {
+		h, err := json.Marshal(msg.Headers)
+		if err != nil {
+			return dbMessage{}, err
+		}
+		headers = sql.NullString{String: string(h), Valid: true}
+	}
+
+	payload, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return dbMessage{}, err
+	}
+
+	return dbMessage{
+		Project:   msg.Project,
+		Subtopic:  sql.NullString{String: msg.Subtopic, Valid: msg.Subtopic != ""},
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Format:    format,
+		Headers:   headers,
+		Payload:   payload,
+		Created:   msg.Created,
+	}, nil
+}
@@ -4,7 +4,6 @@ import (
 	"github.com/vietquy/alpha/logger"
 	"github.com/vietquy/alpha/errors"
 	"github.com/vietquy/alpha/messaging"
-	pubsub "github.com/vietquy/alpha/messaging/nats"
 	"github.com/vietquy/alpha/transformer"
 )
 
@@ -21,29 +20,60 @@ type Writer interface {
 }
 
 // Start method starts writing messages received from NATS.
-// This method transforms messages before
-// using MessageRepository to store them.
-func Start(sub messaging.Subscriber, writer Writer, transformer transformer.Transformer, logger logger.Logger) error {
-	subjects := []string{pubsub.SubjectAllProjects}
-
-	for _, subject := range subjects {
-		if err := sub.Subscribe(subject, handler(transformer, writer)); err != nil {
+// This method subscribes to the configured subjects, and transforms the
+// messages passing the configured filters before using MessageRepository
+// to store them.
+func Start(sub messaging.Subscriber, writer Writer, transformer transformer.Transformer, cfg Config, logger logger.Logger) error {
+	for _, subject := range cfg.Subjects {
+		if err := sub.Subscribe(subject, handler(transformer, writer, cfg)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func handler(t transformer.Transformer, c Writer) messaging.MessageHandler {
+// Run starts writing the messages received by the subscriber in batches, as
+// specified by the batch configuration, selecting the transformer of the
+// message by its content type. Messages without the content type are
+// transformed by the provided transformer. The returned BatchWriter is to be
+// closed once the subscription is, to write the buffered messages.
+func Run(sub messaging.Subscriber, repo Writer, def transformer.Transformer, cfg Config, batch BatchConfig, logger logger.Logger) (BatchWriter, error) {
+	bw := NewBatchWriter(repo, batch, logger)
+	if err := Start(sub, bw, NewTransformer(def, cfg), cfg, logger); err != nil {
+		bw.Close()
+		return nil, err
+	}
+
+	return bw, nil
+}
+
+// Retryable reports whether writing of the message failed with the error is
+// worth retrying. Messages failing to transform, i.e. having the malformed
+// payload or the unknown content type, fail the same way once retried.
+func Retryable(err error) bool {
+	return !errors.Contains(err, transformer.ErrTransform)
+}
+
+func handler(t transformer.Transformer, c Writer, cfg Config) messaging.MessageHandler {
 	return func(msg messaging.Message) error {
+		if !cfg.accepts(msg) {
+			return nil
+		}
+
 		m := interface{}(msg)
 		var err error
 		if t != nil {
 			m, err = t.Transform(msg)
 			if err != nil {
+				if !errors.Contains(err, transformer.ErrTransform) {
+					err = errors.Wrap(transformer.ErrTransform, err)
+				}
 				return err
 			}
 		}
+		if msgs, ok := m.(transformer.Messages); ok && !cfg.acceptsFormat(msgs.Format) {
+			return nil
+		}
 		return c.Write(m)
 	}
-}
\ No newline at end of file
+}
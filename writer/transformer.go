@@ -0,0 +1,59 @@
+package writer
+
+import (
+	"github.com/vietquy/alpha/errors"
+	"github.com/vietquy/alpha/transformer"
+	"github.com/vietquy/alpha/transformer/cbor"
+	"github.com/vietquy/alpha/transformer/raw"
+	"github.com/vietquy/alpha/transformer/senml"
+)
+
+// Transformers of the messages without the content type.
+const (
+	// JSONTransformer transforms the payloads as JSON objects.
+	JSONTransformer = "json"
+
+	// SenMLTransformer transforms the payloads as SenML records.
+	SenMLTransformer = "senml"
+)
+
+var errUnknownTransformer = errors.New("unknown transformer")
+
+// DefaultTransformer returns the transformer of the messages without the
+// content type having the provided name. SenML transformer decodes the
+// payloads of the provided SenML content type.
+func DefaultTransformer(name, contentType string) (transformer.Transformer, error) {
+	switch name {
+	case JSONTransformer:
+		return transformer.New(), nil
+	case SenMLTransformer:
+		if contentType != senml.JSON && contentType != senml.CBOR {
+			return nil, errors.Wrap(transformer.ErrUnknownContentType, errors.New(contentType))
+		}
+		return senml.New(contentType), nil
+	default:
+		return nil, errors.Wrap(errUnknownTransformer, errors.New(name))
+	}
+}
+
+// NewTransformer returns the transformer selecting the transformer of the
+// message by its content type, i.e. JSON, SenML, CBOR or raw bytes. Messages
+// without the content type are transformed by the provided transformer, and
+// the ones having the unknown content type are handled as configured.
+func NewTransformer(def transformer.Transformer, cfg Config) transformer.Transformer {
+	transformers := map[string]transformer.Transformer{
+		"":                      def,
+		transformer.ContentType: transformer.New(),
+		senml.JSON:              senml.New(senml.JSON),
+		senml.CBOR:              senml.New(senml.CBOR),
+		cbor.ContentType:        cbor.New(),
+		raw.ContentType:         raw.New(),
+	}
+
+	var fallback transformer.Transformer
+	if cfg.UnknownContentTypes == BlobUnknown {
+		fallback = raw.New()
+	}
+
+	return transformer.NewRegistry(transformers, fallback)
+}